@@ -1,15 +1,28 @@
 package app
 
 import (
+	"os"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kanishkathakur1/pomodoro/internal/calendar"
 	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/dbus"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/hook"
+	"github.com/kanishkathakur1/pomodoro/internal/metrics"
+	"github.com/kanishkathakur1/pomodoro/internal/mqtt"
 	"github.com/kanishkathakur1/pomodoro/internal/notify"
+	"github.com/kanishkathakur1/pomodoro/internal/plan"
+	"github.com/kanishkathakur1/pomodoro/internal/project"
+	"github.com/kanishkathakur1/pomodoro/internal/status"
+	"github.com/kanishkathakur1/pomodoro/internal/task"
 	"github.com/kanishkathakur1/pomodoro/internal/timer"
 	"github.com/kanishkathakur1/pomodoro/internal/ui"
+	"github.com/kanishkathakur1/pomodoro/internal/webhook"
 )
 
 // ViewState represents the current view
@@ -26,6 +39,11 @@ type TickMsg time.Time
 type SplashTickMsg time.Time
 type FlashMsg struct{}
 type FlashEndMsg struct{}
+type CelebrationEndMsg struct{}
+type ConfigCheckMsg time.Time
+
+// configCheckInterval is how often the config file is checked for changes
+const configCheckInterval = 2 * time.Second
 
 // Model is the main bubbletea model
 type Model struct {
@@ -39,26 +57,157 @@ type Model struct {
 	ShowHelp    bool
 	SplashFrame int
 	FlashActive bool
+
+	// Session history
+	History      *history.Store
+	SessionStart time.Time // When the current session was first started, zero if not yet
+	LastRecordID string    // ID of the most recently recorded session
+	Reflecting   bool      // Whether the reflection prompt is open in the complete view
+	Reflection   textinput.Model
+	FocusRating  int // Focus rating being entered, 0 if unrated
+
+	// Goal progress computed from history
+	DailyGoal   ui.GoalProgress
+	WeeklyGoal  ui.GoalProgress
+	GoalDay     time.Time // Start of the day the progress was counted in
+	Celebration string    // Message of the goal celebration overlay, empty when hidden
+
+	// Project and tags attached to recorded sessions
+	Project          string
+	Tags             []string
+	Labeling         bool // Whether the label picker is open
+	LabelInput       textinput.Model
+	LabelSuggestions []string
+	LabelChoice      int
+	KnownProjects    []string
+	KnownTags        []string
+
+	// Active task picked from the task sources
+	TaskSources  []task.Source
+	ActiveTask   *task.Task
+	PickingTask  bool // Whether the task picker is open
+	TaskInput    textinput.Model
+	TaskChoices  []task.Task
+	TaskMatches  []int // Indices into TaskChoices matching the filter
+	TaskChoice   int
+	TaskTracking bool // Whether the active task was started in its tracker
+
+	// Short status message shown in the timer view
+	Toast        string
+	ToastID      int
+	NoAudioShown bool // Whether the toast about a build without audio was shown
+
+	// Watches the config file for edits made while running
+	ConfigWatcher *config.Watcher
+	ConfigWarning string            // Why the config file couldn't be fully applied
+	Warnings      map[string]string // Why an integration couldn't be set up, by name
+
+	// Profile picker
+	PickingProfile bool
+	ProfileChoice  int
+
+	// Day plan walked through block by block, nil until loaded or opened
+	Plan        *plan.Plan
+	PlanPath    string          // Where the plan is saved, empty to keep it in memory
+	PastPlan    *plan.Plan      // The plan of the day that ended, shown until a key is pressed
+	PlanWatcher *config.Watcher // Notices the plan file being changed by another process
+	PlanBlock   int             // Block last seen as current, to notice start times coming
+	Planning    bool            // Whether the plan editor is open
+	PlanInput   textinput.Model
+	PlanChoice  int
+	PlanMessage string // Why the last line couldn't be added
+
+	// Reminders while a finished session waits for a key press
+	CompletedAt time.Time // When the session finished, zero once acknowledged
+	Reminders   int       // Reminders sent so far
+	ReminderID  int
+
+	// Webhook deliveries waiting for a retry
+	Outbox *webhook.Outbox
+
+	// Chat status shown while working, nil when off
+	Status *status.Syncer
+
+	// Timer state published for home automation, nil until connected
+	MQTT *mqtt.Publisher
+
+	// Meetings to plan sessions around, nil when no files are configured
+	Calendar *calendar.Calendar
+
+	// Timer exposed on the session bus, nil until registered
+	DBus *dbus.Service
+
+	// Prometheus metrics, nil when off
+	Metrics       *metrics.Metrics
+	MetricsServer *metrics.Server
 }
 
 // New creates a new Model
 func New() Model {
-	cfg, _ := config.Load()
-	return Model{
-		Timer:       timer.New(),
+	cfg, cfgErr := config.Load()
+	store, _ := history.Open()
+	outbox, _ := webhook.OpenOutbox()
+	m := Model{
+		Timer:       timer.NewWithDurations(timerDurations(cfg.Timer)),
 		Config:      cfg,
 		Notifier:    notify.New(cfg),
-		Keys:        DefaultKeyMap(),
+		History:     store,
+		Outbox:      outbox,
+		Keys:        KeyMapFromConfig(cfg.Keys),
 		CurrentView: ViewSplash,
 		Width:       80,
 		Height:      24,
 	}
+	if cfgErr != nil {
+		m.ConfigWarning = configWarning(cfgErr)
+	}
+	if syncer, err := newStatusSyncer(cfg.Status); err != nil {
+		m.setWarning("status", err.Error())
+	} else {
+		m.Status = syncer
+	}
+	if stats, srv, err := startMetrics(cfg.Metrics); err != nil {
+		m.setWarning("metrics", "Metrics: "+err.Error())
+	} else {
+		m.Metrics, m.MetricsServer = stats, srv
+	}
+	_ = ui.ApplyTheme(cfg.UI.Theme)
+	if path, err := config.Path(); err == nil {
+		m.ConfigWatcher = config.NewWatcher(path)
+	}
+	if dir, err := os.Getwd(); err == nil {
+		m.Project = project.Detect(cfg.Projects, dir)
+		m.TaskSources = task.FromConfig(cfg.Tasks, dir)
+		m.Calendar = newCalendar(cfg.Calendar, dir)
+	}
+	if m.Calendar != nil {
+		if err := m.Calendar.Refresh(); err != nil {
+			m.setWarning("calendar", "Calendar: "+err.Error())
+		}
+	}
+	if p, past, path, err := loadPlan(planDay(cfg.Goals, time.Now())); err != nil {
+		// Without a path the unreadable plan is never saved over
+		m.setWarning("plan", "Plan: "+err.Error())
+	} else {
+		m.Plan, m.PlanPath, m.PastPlan = p, path, past
+		m.PlanWatcher = config.NewWatcher(path)
+		m.PlanBlock = p.Current(time.Now())
+		m.followPlan()
+	}
+	m.refreshGoals()
+	m.updateMetrics()
+	return m
 }
 
 // Init implements tea.Model
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		splashTick(),
+		configTick(),
+		m.flushOutbox(),
+		outboxTick(),
+		connectMQTT(m.Config.MQTT),
+		connectDBus(m.Config.DBus),
 		tea.SetWindowTitle("Pomodoro"),
 	)
 }
@@ -77,6 +226,13 @@ func timerTick() tea.Cmd {
 	})
 }
 
+// configTick creates a tick command for checking the config file
+func configTick() tea.Cmd {
+	return tea.Tick(configCheckInterval, func(t time.Time) tea.Msg {
+		return ConfigCheckMsg(t)
+	})
+}
+
 // flashCmd creates a command to end the flash effect
 func flashCmd() tea.Cmd {
 	return tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
@@ -84,6 +240,13 @@ func flashCmd() tea.Cmd {
 	})
 }
 
+// celebrationCmd creates a command to hide the goal celebration
+func celebrationCmd() tea.Cmd {
+	return tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+		return CelebrationEndMsg{}
+	})
+}
+
 // Update implements tea.Model
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -108,6 +271,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.Timer.IsComplete() {
 				return m.handleSessionComplete()
 			}
+			m.publishDBus()
+			if m.Timer.WarningDue(m.warningLead()) {
+				return m, tea.Batch(timerTick(), m.warn())
+			}
 			return m, timerTick()
 		}
 		return m, nil
@@ -115,6 +282,81 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case FlashEndMsg:
 		m.FlashActive = false
 		return m, nil
+
+	case CelebrationEndMsg:
+		m.Celebration = ""
+		return m, nil
+
+	case ReminderMsg:
+		return m.remind(msg)
+
+	case HookMsg:
+		if msg.Err != nil {
+			return m, m.showToast(msg.Err.Error())
+		}
+		return m, nil
+
+	case OutboxTickMsg:
+		return m, tea.Batch(m.flushOutbox(), outboxTick())
+
+	case WebhookMsg:
+		if msg.Err != nil {
+			return m, m.showToast(msg.Err.Error())
+		}
+		return m, nil
+
+	case MQTTConnectedMsg:
+		return m.mqttConnected(msg)
+
+	case MQTTTickMsg:
+		m.publishState()
+		return m, mqttTick()
+
+	case MQTTCommandMsg:
+		return m.mqttCommand(msg)
+
+	case DBusConnectedMsg:
+		return m.dbusConnected(msg)
+
+	case DBusCommandMsg:
+		return m.dbusCommand(msg)
+
+	case StatusMsg:
+		if msg.Err != nil {
+			return m, m.showToast("Status sync failed: " + msg.Err.Error())
+		}
+		return m, nil
+
+	case PushMsg:
+		if msg.Err != nil {
+			return m, m.showToast("Push failed: " + msg.Err.Error())
+		}
+		return m, nil
+
+	case TaskDoneMsg:
+		return m.taskDone(msg)
+
+	case TaskListMsg:
+		return m.showTaskPicker(msg)
+
+	case TaskSyncMsg:
+		if msg.Err != nil {
+			return m, m.showToast("Task sync failed: " + msg.Err.Error())
+		}
+		return m, nil
+
+	case ConfigCheckMsg:
+		m.checkGoals(time.Now())
+		refreshed := m.refreshCalendar()
+		planned := m.checkPlan(time.Now())
+		result, cmd := m.reloadConfig()
+		return result, tea.Batch(cmd, refreshed, planned)
+
+	case ToastEndMsg:
+		if msg.ID == m.ToastID {
+			m.Toast = ""
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -128,6 +370,40 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Any key acknowledges a finished session, then does its usual job
+	m.acknowledge()
+
+	// Any key dismisses the goal celebration
+	if m.Celebration != "" {
+		m.Celebration = ""
+		return m, nil
+	}
+
+	// Any key dismisses the summary of the day that ended; only then does
+	// the new day's plan take its place on disk
+	if m.PastPlan != nil {
+		m.PastPlan = nil
+		_ = m.savePlan()
+		return m, nil
+	}
+
+	// The reflection prompt captures text, so it gets keys before any shortcut
+	if m.Reflecting {
+		return m.handleReflectionKey(msg)
+	}
+	if m.Labeling {
+		return m.handleLabelKey(msg)
+	}
+	if m.PickingTask {
+		return m.handleTaskKey(msg)
+	}
+	if m.PickingProfile {
+		return m.handleProfileKey(msg)
+	}
+	if m.Planning {
+		return m.handlePlanKey(msg)
+	}
+
 	// Handle help toggle in any view
 	if key.Matches(msg, m.Keys.Help) {
 		m.ShowHelp = !m.ShowHelp
@@ -142,9 +418,7 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Handle quit
 	if key.Matches(msg, m.Keys.Quit) {
-		// Save config before quitting
-		_ = m.Config.Save()
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	// Handle view-specific keys
@@ -162,31 +436,112 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleTimerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.Keys.Toggle):
-		m.Timer.Toggle()
-		if m.Timer.Running {
-			return m, timerTick()
-		}
-		return m, nil
+		return m.toggleTimer()
 
 	case key.Matches(msg, m.Keys.Skip):
-		m.Timer.Skip()
-		m.CurrentView = ViewComplete
-		return m, nil
+		return m.skipSession()
 
 	case key.Matches(msg, m.Keys.Reset):
-		m.Timer.Reset()
-		return m, nil
+		return m.resetSession()
+
+	case key.Matches(msg, m.Keys.Labels):
+		return m.openLabelPicker()
+
+	case key.Matches(msg, m.Keys.PickTask):
+		return m.openTaskPicker()
+
+	case key.Matches(msg, m.Keys.CompleteTask):
+		return m.completeActiveTask()
+
+	case key.Matches(msg, m.Keys.PickProfile):
+		return m.openProfilePicker()
+
+	case key.Matches(msg, m.Keys.Plan):
+		return m.openPlan()
 
 	case key.Matches(msg, m.Keys.Notify):
 		m.Notifier.ToggleSystemNotification()
 		m.Notifier.ToggleTerminalBell()
 		m.Notifier.ToggleVisualFlash()
 		return m, nil
+
+	case key.Matches(msg, m.Keys.DoNotDisturb):
+		m.Notifier.ToggleDoNotDisturb()
+		toast := "Do not disturb off"
+		if m.Notifier.DoNotDisturb() {
+			toast = "Do not disturb on"
+		}
+		return m, tea.Batch(m.syncTicking(), m.showToast(toast))
 	}
 
 	return m, nil
 }
 
+// toggleTimer starts or pauses the timer
+func (m Model) toggleTimer() (tea.Model, tea.Cmd) {
+	m.Timer.Toggle()
+	var fitted tea.Cmd
+	if m.Timer.Running && m.SessionStart.IsZero() {
+		if notice := m.fitToCalendar(time.Now()); notice != "" {
+			fitted = m.showToast(notice)
+		}
+	}
+	m.publishState()
+	sync := tea.Batch(m.syncTaskTracking(), m.syncTicking(), m.syncStatus())
+	if m.Timer.Running {
+		var started tea.Cmd
+		if m.SessionStart.IsZero() {
+			m.SessionStart = time.Now()
+			started = m.runHook(hook.SessionStart, false)
+		}
+		return m, tea.Batch(timerTick(), sync, started, fitted)
+	}
+	return m, sync
+}
+
+// skipSession ends the current session early and moves on to the next
+func (m Model) skipSession() (tea.Model, tea.Cmd) {
+	var ended tea.Cmd
+	if !m.SessionStart.IsZero() {
+		ended = m.runHook(hook.SessionEnd, false)
+	}
+	m.recordSession(false)
+	m.Timer.Skip()
+	m.followPlan()
+	m.CurrentView = ViewComplete
+	m.publishState()
+	return m, tea.Batch(m.stopTaskTracking(), m.syncTicking(), m.syncStatus(), ended)
+}
+
+// resetSession restarts the current session from its full length
+func (m Model) resetSession() (tea.Model, tea.Cmd) {
+	m.recordMetrics(metrics.Voided, m.SessionStart)
+	m.Timer.Reset()
+	m.SessionStart = time.Time{}
+	m.publishState()
+	return m, tea.Batch(m.syncTaskTracking(), m.syncTicking(), m.syncStatus())
+}
+
+// remoteCommand toggles, skips or resets as if the key had been pressed in
+// the timer view, for commands arriving over MQTT or D-Bus
+func (m Model) remoteCommand(command string) (tea.Model, tea.Cmd) {
+	m.acknowledge()
+	m.Celebration = ""
+	if m.CurrentView == ViewSplash || m.CurrentView == ViewComplete {
+		m.CurrentView = ViewTimer
+	}
+
+	switch command {
+	case "toggle":
+		return m.toggleTimer()
+	case "skip":
+		return m.skipSession()
+	case "reset":
+		return m.resetSession()
+	}
+	return m, nil
+}
+
 // handleCompleteKey handles keys in complete view
 func (m Model) handleCompleteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if key.Matches(msg, m.Keys.Toggle) {
@@ -197,38 +552,239 @@ func (m Model) handleCompleteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// quit saves the config, shuts down what the app started and quits once
+// the task tracker and chat status are cleared up
+// Every way of quitting goes through here, whatever overlay is open
+func (m Model) quit() tea.Cmd {
+	_ = m.Config.Save()
+	_ = m.Notifier.SetTicking(false)
+	if m.MQTT != nil {
+		m.MQTT.Close()
+	}
+	if m.MetricsServer != nil {
+		_ = m.MetricsServer.Close()
+	}
+	if m.DBus != nil {
+		_ = m.DBus.Close()
+	}
+	stopped, cleared := m.stopTaskTracking(), m.clearStatus()
+	if stopped == nil && cleared == nil {
+		return tea.Quit
+	}
+	return tea.Sequence(stopped, cleared, tea.Quit)
+}
+
+// handleReflectionKey handles keys while the reflection prompt is open
+func (m Model) handleReflectionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, m.quit()
+
+	case key.Matches(msg, m.Keys.SkipReflection):
+		m.Reflecting = false
+		return m, nil
+
+	case key.Matches(msg, m.Keys.SaveReflection):
+		m.saveReflection()
+		m.Reflecting = false
+		return m, nil
+
+	case key.Matches(msg, m.Keys.FocusUp):
+		if m.FocusRating < 5 {
+			m.FocusRating++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.FocusDown):
+		if m.FocusRating > 1 {
+			m.FocusRating--
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.Reflection, cmd = m.Reflection.Update(msg)
+	return m, cmd
+}
+
+// saveReflection stores the typed note and focus rating on the last record
+func (m Model) saveReflection() {
+	if m.History == nil || m.LastRecordID == "" {
+		return
+	}
+	note := m.Reflection.Value()
+	focus := m.FocusRating
+	_ = m.History.Update(m.LastRecordID, func(r *history.Record) {
+		r.Note = note
+		r.Focus = focus
+	})
+}
+
+// recordSession appends the current session to history
+// It must be called before the timer transitions to the next session
+func (m *Model) recordSession(completed bool) {
+	start := m.SessionStart
+	task := m.activeTaskTitle()
+	m.SessionStart = time.Time{}
+	m.LastRecordID = ""
+	if completed {
+		m.recordMetrics(metrics.Completed, start)
+	} else {
+		m.recordMetrics(metrics.Skipped, start)
+	}
+	m.recordPlan(start, completed)
+	if m.History == nil || start.IsZero() {
+		return
+	}
+
+	record := history.Record{
+		ID:        history.NewID(start),
+		Type:      m.Timer.SessionType,
+		Start:     start,
+		End:       time.Now(),
+		Planned:   m.Timer.Duration,
+		Elapsed:   m.Timer.Duration - m.Timer.Remaining,
+		Completed: completed,
+		Project:   m.Project,
+		Task:      task,
+		Tags:      m.Tags,
+		Profile:   m.Config.ActiveProfile(),
+	}
+	if err := m.History.Append(record); err != nil {
+		return
+	}
+	m.LastRecordID = record.ID
+
+	dailyMet, weeklyMet := m.DailyGoal.Met(), m.WeeklyGoal.Met()
+	m.refreshGoals()
+	switch {
+	case !weeklyMet && m.WeeklyGoal.Met():
+		m.Celebration = "Weekly goal reached!"
+	case !dailyMet && m.DailyGoal.Met():
+		m.Celebration = "Daily goal reached!"
+	}
+}
+
+// refreshGoals recomputes daily and weekly progress from history
+func (m *Model) refreshGoals() {
+	goals := m.Config.Goals
+	boundary, _ := goals.DayBoundary()
+	now := time.Now()
+	m.GoalDay = history.DayStart(now, boundary)
+	m.DailyGoal = ui.GoalProgress{PomodoroTarget: goals.DailyPomodoros, FocusTarget: goals.DailyFocus}
+	m.WeeklyGoal = ui.GoalProgress{PomodoroTarget: goals.WeeklyPomodoros, FocusTarget: goals.WeeklyFocus}
+	if m.History == nil || (!m.DailyGoal.Tracked() && !m.WeeklyGoal.Tracked()) {
+		return
+	}
+
+	records, err := m.History.All()
+	if err != nil {
+		return
+	}
+	first, _ := goals.FirstWeekday()
+	weekStart := history.WeekStart(now, boundary, first)
+
+	daily := history.Sum(records, m.GoalDay, now.Add(time.Second))
+	weekly := history.Sum(records, weekStart, now.Add(time.Second))
+	m.DailyGoal.Pomodoros, m.DailyGoal.Focus = daily.Pomodoros, daily.Focus
+	m.WeeklyGoal.Pomodoros, m.WeeklyGoal.Focus = weekly.Pomodoros, weekly.Focus
+}
+
+// checkGoals starts counting afresh once a new day, and with it perhaps a
+// new week, has begun since the progress was counted
+func (m *Model) checkGoals(now time.Time) {
+	boundary, _ := m.Config.Goals.DayBoundary()
+	if history.DayStart(now, boundary).Equal(m.GoalDay) {
+		return
+	}
+	m.refreshGoals()
+	m.updateMetrics()
+}
+
+// newReflectionInput creates the text input for session notes
+func newReflectionInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "What did you get done?"
+	input.CharLimit = 280
+	input.Width = 40
+	input.Focus()
+	return input
+}
+
 // handleSessionComplete handles timer completion
 func (m Model) handleSessionComplete() (tea.Model, tea.Cmd) {
 	// Store completed session type before transition
 	completedSession := m.Timer.SessionType
 
 	// Send notification
-	var title, message string
-	switch completedSession {
-	case timer.Work:
-		title = "Work Session Complete!"
-		message = "Time for a break."
-	case timer.ShortBreak:
-		title = "Break Over!"
-		message = "Ready to focus again?"
-	case timer.LongBreak:
-		title = "Long Break Complete!"
-		message = "Great work! Ready for more?"
-	}
+	title, message := completionMessage(completedSession)
 	_ = m.Notifier.Notify(title, message)
+	event := notify.BreakEnd
+	if completedSession == timer.Work {
+		event = notify.WorkEnd
+	}
+	_ = m.Notifier.SetTicking(false)
+	var cmds []tea.Cmd
+	if played := m.playSound(event); played != nil {
+		cmds = append(cmds, played)
+	}
+	if pushed := m.push(completedSession, title, message); pushed != nil {
+		cmds = append(cmds, pushed)
+	}
 
-	// Transition to next session
+	// Record and transition to next session
+	elapsed := m.Timer.Duration - m.Timer.Remaining
+	if ended := m.runHook(hook.SessionEnd, true); ended != nil {
+		cmds = append(cmds, ended)
+	}
+	m.recordSession(true)
+	if completedSession == timer.Work {
+		stop := m.stopTaskTracking()
+		if annotate := m.annotateTaskPomodoro(elapsed); annotate != nil {
+			cmds = append(cmds, tea.Sequence(stop, annotate))
+		}
+	}
 	m.Timer.CompleteSession()
+	m.followPlan()
 	m.CurrentView = ViewComplete
+	m.publishState()
+	if synced := m.syncStatus(); synced != nil {
+		cmds = append(cmds, synced)
+	}
+
+	// Offer the reflection prompt after work sessions
+	if completedSession == timer.Work && m.Config.Reflection.Enabled && m.LastRecordID != "" {
+		m.Reflecting = true
+		m.Reflection = newReflectionInput()
+		m.FocusRating = 0
+		cmds = append(cmds, textinput.Blink)
+	}
 
 	// Trigger flash if enabled
-	var cmd tea.Cmd
 	if m.Notifier.VisualFlash() {
 		m.FlashActive = true
-		cmd = flashCmd()
+		cmds = append(cmds, flashCmd())
 	}
 
-	return m, cmd
+	if m.Celebration != "" {
+		cmds = append(cmds, celebrationCmd())
+	}
+	cmds = append(cmds, m.awaitAcknowledgement())
+
+	return m, tea.Batch(cmds...)
+}
+
+// completionMessage returns the notification for a finished session
+func completionMessage(completed timer.SessionType) (title, message string) {
+	switch completed {
+	case timer.Work:
+		return "Work Session Complete!", "Time for a break."
+	case timer.ShortBreak:
+		return "Break Over!", "Ready to focus again?"
+	case timer.LongBreak:
+		return "Long Break Complete!", "Great work! Ready for more?"
+	}
+	return "", ""
 }
 
 // View implements tea.Model
@@ -238,9 +794,19 @@ func (m Model) View() string {
 		return ui.RenderFlash(m.Width, m.Height)
 	}
 
+	// Show goal celebration once the flash is over
+	if m.Celebration != "" {
+		return ui.RenderCelebration(m.Celebration, m.Width, m.Height)
+	}
+
+	// Show how the day that ended went against its plan
+	if m.PastPlan != nil && m.CurrentView != ViewSplash {
+		return ui.RenderPastPlan(m.PastPlan, m.Width, m.Height)
+	}
+
 	// Show help overlay if active
 	if m.ShowHelp {
-		return ui.RenderHelpCentered(m.Width, m.Height)
+		return ui.RenderHelpItemsCentered(m.Keys.HelpItems(), m.Width, m.Height)
 	}
 
 	switch m.CurrentView {
@@ -248,11 +814,53 @@ func (m Model) View() string {
 		return ui.RenderSplash(m.SplashFrame, m.Width, m.Height)
 
 	case ViewTimer:
-		return ui.RenderTimer(m.Timer, m.Width, m.Height, !m.Timer.Running)
+		if m.Labeling {
+			picker := ui.RenderPicker("🏷 Project & Tags", m.LabelInput.View(), m.LabelSuggestions, m.LabelChoice,
+				"tab complete • ↑/↓ choose • ENTER to apply • ESC to cancel")
+			return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, picker)
+		}
+		if m.PickingTask {
+			picker := ui.RenderPicker("✔ Active Task", m.TaskInput.View(), m.taskPickerItems(), m.TaskChoice,
+				"↑/↓ choose • ENTER to select • ESC to cancel")
+			return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, picker)
+		}
+		if m.PickingProfile {
+			picker := ui.RenderPicker("◆ Profile", m.profileSummary(), m.profileItems(), m.ProfileChoice,
+				"↑/↓ choose • ENTER to switch • ESC to cancel")
+			return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, picker)
+		}
+		if m.Planning {
+			editor := ui.RenderPlan(m.Plan, time.Now(), m.PlanInput.View(), m.PlanChoice, m.PlanMessage,
+				"ENTER to add • shift+↑/↓ move • ctrl+x remove • ENTER on empty or ESC to close")
+			return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, editor)
+		}
+		meeting, conflict := m.upcomingMeeting(time.Now())
+		return ui.RenderTimerWithOptions(m.Timer, m.Width, m.Height, !m.Timer.Running, ui.TimerOptions{
+			DailyGoal:  m.DailyGoal,
+			WeeklyGoal: m.WeeklyGoal,
+			Project:    m.Project,
+			Tags:       m.Tags,
+			Task:       m.activeTaskTitle(),
+			Toast:      m.Toast,
+			Warning:    m.warning(),
+			Profile:    m.Config.ActiveProfile(),
+			EndingSoon: m.Timer.InWarning(m.warningLead()),
+			Quiet:      m.Notifier.Quiet(),
+			Meeting:    meeting,
+			Conflict:   conflict,
+			Plan:       m.planStatus(time.Now()),
+		})
 
 	case ViewComplete:
 		// Render complete view centered
 		content := ui.RenderComplete(getCompletedSession(m.Timer), m.Timer.SessionType)
+		if m.Reflecting {
+			content = ui.RenderReflection(getCompletedSession(m.Timer), m.Reflection.View(), m.FocusRating)
+		} else if m.planFinished(time.Now()) {
+			content += "\n\n" + ui.RenderPlanSummary(m.Plan)
+		} else if status := m.planStatus(time.Now()); status != "" {
+			content += "\n\n" + ui.HelpDescStyle.Render("▤ "+status)
+		}
 		return lipgloss.Place(
 			m.Width, m.Height,
 			lipgloss.Center, lipgloss.Center,
@@ -1,11 +1,16 @@
 package app
 
 import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
 	"github.com/kanishkathakur1/pomodoro/internal/notify"
 	"github.com/kanishkathakur1/pomodoro/internal/timer"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +52,31 @@ func TestNew(t *testing.T) {
 	assert.False(t, m.FlashActive)
 }
 
+func TestNew_CollectsEveryWarning(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pomodoro"), 0755))
+	content := fmt.Sprintf("version = %d\n\n[status]\nservice = \"slack\"\n\n[metrics]\nlisten = %q\n", config.CurrentVersion(), busy.Addr().String())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pomodoro", "config.toml"), []byte(content), 0644))
+	planPath := filepath.Join(dir, "pomodoro", "plan.json")
+	require.NoError(t, os.WriteFile(planPath, []byte("{"), 0644))
+
+	m := New()
+
+	assert.Contains(t, m.warning(), "no slack token")
+	assert.Contains(t, m.warning(), "Metrics: ")
+	assert.Contains(t, m.warning(), "Plan: ")
+	assert.Nil(t, m.Plan, "an unreadable plan isn't replaced")
+	assert.Empty(t, m.PlanPath)
+	data, err := os.ReadFile(planPath)
+	require.NoError(t, err)
+	assert.Equal(t, "{", string(data))
+}
+
 func TestInit(t *testing.T) {
 	m := newTestModel()
 	cmd := m.Init()
@@ -281,6 +311,42 @@ func TestHandleKey_NotifyToggle(t *testing.T) {
 	assert.NotEqual(t, initialFlash, model.Config.Notifications.VisualFlash, "n should toggle notifications")
 }
 
+func TestHandleKey_DoNotDisturb(t *testing.T) {
+	m := newTestModel()
+	m.CurrentView = ViewTimer
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	model := result.(Model)
+
+	assert.True(t, model.Notifier.DoNotDisturb())
+	assert.Equal(t, "Do not disturb on", model.Toast)
+	assert.Contains(t, model.View(), "☾ Do not disturb")
+
+	result, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	assert.False(t, result.(Model).Notifier.DoNotDisturb())
+}
+
+func TestHandleSessionComplete_DoNotDisturbStillRecords(t *testing.T) {
+	bells := 0
+	notify.SetNotifyFuncsForTesting(nil, func() { bells++ })
+	defer notify.SetNotifyFuncsForTesting(nil, func() {})
+
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Notifier.ToggleDoNotDisturb()
+	m.SessionStart = time.Now().Add(-25 * time.Minute)
+	m.Timer.Remaining = 0
+
+	result, _ := m.handleSessionComplete()
+	model := result.(Model)
+
+	records, err := model.History.All()
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, ViewComplete, model.CurrentView)
+	assert.Zero(t, bells)
+}
+
 func TestHandleKey_CompleteView_Toggle(t *testing.T) {
 	m := newTestModel()
 	m.CurrentView = ViewComplete
@@ -414,6 +480,7 @@ func TestHandleSessionComplete_NoFlashWhenDisabled(t *testing.T) {
 	m.CurrentView = ViewTimer
 	m.Timer.SessionType = timer.Work
 	m.Config.Notifications.VisualFlash = false
+	m.Config.Reminders.Max = 0
 
 	result, cmd := m.handleSessionComplete()
 	model := result.(Model)
@@ -438,3 +505,299 @@ func TestModel_InitialState(t *testing.T) {
 	assert.Equal(t, 0, m.Timer.PomodoroCount)
 	assert.False(t, m.Timer.Running)
 }
+
+func newTestModelWithHistory(t *testing.T) Model {
+	t.Helper()
+	m := newTestModel()
+	m.History = history.NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	m.Config.Notifications.VisualFlash = false
+	return m
+}
+
+// startSession presses the toggle key in the timer view
+func startSession(t *testing.T, m Model) Model {
+	t.Helper()
+	m.CurrentView = ViewTimer
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	require.True(t, m.Timer.Running)
+	return m
+}
+
+// finishSession runs the current session to its end, starting it first if
+// it isn't running
+func finishSession(t *testing.T, m Model) Model {
+	t.Helper()
+	if !m.Timer.Running {
+		m = startSession(t, m)
+	}
+	m.Timer.Remaining = time.Second
+	result, _ := m.Update(TickMsg(time.Now()))
+	m = result.(Model)
+	require.Equal(t, ViewComplete, m.CurrentView)
+	return m
+}
+
+func TestHandleKey_Toggle_SetsSessionStart(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	model := result.(Model)
+	start := model.SessionStart
+
+	assert.False(t, start.IsZero(), "starting should record the session start")
+
+	// Pausing and resuming keeps the original start
+	result, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace})
+	result, _ = result.(Model).Update(tea.KeyMsg{Type: tea.KeySpace})
+	assert.Equal(t, start, result.(Model).SessionStart)
+}
+
+func TestHandleSessionComplete_RecordsHistory(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.SessionStart = time.Now().Add(-25 * time.Minute)
+	m.Timer.Remaining = 0
+
+	result, _ := m.handleSessionComplete()
+	model := result.(Model)
+
+	records, err := model.History.All()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, timer.Work, records[0].Type)
+	assert.True(t, records[0].Completed)
+	assert.Equal(t, timer.WorkDuration, records[0].Elapsed)
+	assert.Equal(t, records[0].ID, model.LastRecordID)
+	assert.True(t, model.SessionStart.IsZero(), "start should be cleared for the next session")
+	assert.False(t, model.Reflecting, "reflection is off by default")
+}
+
+func TestHandleKey_Skip_RecordsSkippedSession(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.SessionStart = time.Now().Add(-5 * time.Minute)
+	m.Timer.Remaining = 20 * time.Minute
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	model := result.(Model)
+
+	records, err := model.History.All()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.False(t, records[0].Completed)
+	assert.Equal(t, 5*time.Minute, records[0].Elapsed)
+}
+
+func TestHandleKey_Skip_NotStartedIsNotRecorded(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	model := result.(Model)
+
+	records, err := model.History.All()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func completeWithReflection(t *testing.T) Model {
+	t.Helper()
+	m := newTestModelWithHistory(t)
+	m.Config.Reflection.Enabled = true
+	m = finishSession(t, m)
+	require.True(t, m.Reflecting)
+	return m
+}
+
+func TestReflection_SaveNoteAndFocus(t *testing.T) {
+	m := completeWithReflection(t)
+
+	var result tea.Model = m
+	for _, r := range "shipped q2 report" {
+		result, _ = result.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	for i := 0; i < 5; i++ {
+		result, _ = result.Update(tea.KeyMsg{Type: tea.KeyUp})
+	}
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyDown})
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := result.(Model)
+
+	assert.False(t, model.Reflecting)
+	assert.Equal(t, ViewComplete, model.CurrentView, "saving should not start the next session")
+
+	records, err := model.History.All()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "shipped q2 report", records[0].Note, "q should be typed, not quit")
+	assert.Equal(t, 4, records[0].Focus)
+}
+
+func TestReflection_SkipWithEsc(t *testing.T) {
+	m := completeWithReflection(t)
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model := result.(Model)
+
+	assert.False(t, model.Reflecting)
+	records, err := model.History.All()
+	require.NoError(t, err)
+	assert.Empty(t, records[0].Note)
+
+	// Next key starts the following session as usual
+	result, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace})
+	assert.Equal(t, ViewTimer, result.(Model).CurrentView)
+}
+
+func TestReflection_NotShownAfterBreak(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.Config.Reflection.Enabled = true
+	m.Timer.SessionType = timer.ShortBreak
+	m.SessionStart = time.Now()
+
+	result, _ := m.handleSessionComplete()
+
+	assert.False(t, result.(Model).Reflecting)
+}
+
+func TestReflection_CtrlCQuits(t *testing.T) {
+	m := completeWithReflection(t)
+	config.SetPath(filepath.Join(t.TempDir(), "config.toml"))
+	defer config.SetPath("")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+}
+
+func TestCtrlC_CleansUpFromEveryOverlay(t *testing.T) {
+	overlays := map[string]func(*Model){
+		"reflection": func(m *Model) { m.Reflecting = true },
+		"labels":     func(m *Model) { m.Labeling = true },
+		"tasks":      func(m *Model) { m.PickingTask = true },
+		"profiles":   func(m *Model) { m.PickingProfile = true },
+		"plan":       func(m *Model) { m.Planning = true },
+	}
+	for name, open := range overlays {
+		t.Run(name, func(t *testing.T) {
+			out := recordSounds(t)
+			m := newTestModelWithHistory(t)
+			m.CurrentView = ViewTimer
+			m.Config.Sound.Enabled = true
+			m.Config.Sound.Ticking = true
+			path := filepath.Join(t.TempDir(), "config.toml")
+			config.SetPath(path)
+			defer config.SetPath("")
+
+			result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+			m = result.(Model)
+			require.Len(t, out.Played(), 1)
+			open(&m)
+
+			_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+
+			require.NotNil(t, cmd)
+			assert.Equal(t, tea.Quit(), cmd())
+			assert.True(t, out.Played()[0].Stopped, "the ticking stops")
+			assert.FileExists(t, path, "the config is saved")
+		})
+	}
+}
+
+func TestView_Reflection(t *testing.T) {
+	m := completeWithReflection(t)
+	m.FlashActive = false
+
+	view := m.View()
+
+	assert.Contains(t, view, "What did you get done?")
+	assert.Contains(t, view, "ESC to skip")
+}
+
+func TestRefreshGoals_FromHistory(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	now := time.Now()
+	// Keep the day and week boundaries well away from the sessions
+	m.Config.Goals = config.GoalConfig{
+		DailyPomodoros:  4,
+		WeeklyPomodoros: 20,
+		DayStart:        now.Add(12 * time.Hour).Format("15:04"),
+		WeekStart:       now.AddDate(0, 0, -3).Weekday().String(),
+	}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, m.History.Append(history.Record{
+			ID: history.NewID(now.Add(time.Duration(-i) * time.Minute)), Type: timer.Work,
+			Start: now.Add(time.Duration(-i) * time.Minute), Elapsed: 25 * time.Minute, Completed: true,
+		}))
+	}
+
+	m.refreshGoals()
+
+	assert.Equal(t, 2, m.DailyGoal.Pomodoros)
+	assert.Equal(t, 4, m.DailyGoal.PomodoroTarget)
+	assert.Equal(t, 50*time.Minute, m.DailyGoal.Focus)
+	assert.Equal(t, 2, m.WeeklyGoal.Pomodoros)
+}
+
+func TestCheckGoals_NewDayResetsProgress(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.Config.Goals = config.GoalConfig{DailyPomodoros: 1}
+	m.refreshGoals()
+
+	// Progress counted yesterday, when the goal was met
+	m.DailyGoal.Pomodoros = 1
+	m.checkGoals(time.Now())
+	require.True(t, m.DailyGoal.Met(), "not counted again on the same day")
+	m.GoalDay = m.GoalDay.AddDate(0, 0, -1)
+
+	result, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = result.(Model)
+
+	assert.Zero(t, m.DailyGoal.Pomodoros)
+	assert.False(t, m.DailyGoal.Met())
+}
+
+func TestHandleSessionComplete_CelebratesGoalOnce(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	// Keep the day boundary well away from the sessions
+	dayStart := time.Now().Add(12 * time.Hour).Format("15:04")
+	m.Config.Goals = config.GoalConfig{DailyPomodoros: 1, DayStart: dayStart}
+	m.refreshGoals()
+	m.CurrentView = ViewTimer
+	m.SessionStart = time.Now().Add(-25 * time.Minute)
+	m.Timer.Remaining = 0
+
+	result, cmd := m.handleSessionComplete()
+	model := result.(Model)
+
+	assert.Equal(t, "Daily goal reached!", model.Celebration)
+	assert.NotNil(t, cmd)
+	assert.Contains(t, model.View(), "Daily goal reached!")
+
+	// Any key dismisses the overlay without acting on it
+	result, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace})
+	model = result.(Model)
+	assert.Empty(t, model.Celebration)
+	assert.Equal(t, ViewComplete, model.CurrentView)
+
+	// A second pomodoro on the same day does not celebrate again
+	model.CurrentView = ViewTimer
+	model.Timer.SessionType = timer.Work
+	model.SessionStart = time.Now().Add(-25 * time.Minute)
+	model.Timer.Remaining = 0
+	result, _ = model.handleSessionComplete()
+	assert.Empty(t, result.(Model).Celebration)
+}
+
+func TestUpdate_CelebrationEndMsg(t *testing.T) {
+	m := newTestModel()
+	m.Celebration = "Daily goal reached!"
+
+	result, _ := m.Update(CelebrationEndMsg{})
+
+	assert.Empty(t, result.(Model).Celebration)
+}
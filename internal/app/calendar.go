@@ -0,0 +1,128 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/calendar"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+)
+
+// Limits for fitting sessions around meetings
+const (
+	minShortened = 5 * time.Minute  // Shorter work sessions aren't worth it, warn instead
+	breakStretch = 10 * time.Minute // A break can run this much longer to end as a meeting starts
+)
+
+// calendarPaths expands ~/ and resolves the configured files against dir
+func calendarPaths(cfg config.CalendarConfig, dir string) []string {
+	var paths []string
+	for _, p := range cfg.Files {
+		if rest, ok := strings.CutPrefix(p, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				p = filepath.Join(home, rest)
+			}
+		}
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// newCalendar creates the calendar for the configured files, nil if none
+func newCalendar(cfg config.CalendarConfig, dir string) *calendar.Calendar {
+	if len(cfg.Files) == 0 {
+		return nil
+	}
+	return calendar.New(calendarPaths(cfg, dir))
+}
+
+// refreshCalendar reads calendar files that changed, showing errors
+func (m *Model) refreshCalendar() tea.Cmd {
+	if m.Calendar == nil {
+		return nil
+	}
+	if err := m.Calendar.Refresh(); err != nil {
+		return m.showToast("Calendar: " + err.Error())
+	}
+	return nil
+}
+
+// fitToCalendar adjusts a session that is about to start to the meetings
+// ahead and returns what was done, or "" if nothing
+// A work session that runs into a meeting is shortened to end as it
+// starts, or warned about; a break is stretched or shortened to end as a
+// meeting starts
+func (m *Model) fitToCalendar(now time.Time) string {
+	if m.Calendar == nil {
+		return ""
+	}
+	cfg := m.Config.Calendar
+	end := now.Add(m.Timer.Remaining)
+
+	if m.Timer.SessionType == timer.Work {
+		if cfg.Conflict == "off" {
+			return ""
+		}
+		meeting, ok := m.Calendar.NextStart(now, end)
+		if !ok {
+			return ""
+		}
+		left := meeting.Start.Sub(now).Truncate(time.Second)
+		if cfg.Conflict == "shorten" && left >= minShortened {
+			m.Timer.SetLength(left)
+			return fmt.Sprintf("Shortened to %s for %s", ui.FormatDuration(left.Round(time.Minute)), describeMeeting(meeting))
+		}
+		return fmt.Sprintf("%s starts before this session ends", describeMeeting(meeting))
+	}
+
+	if !cfg.AlignBreaks {
+		return ""
+	}
+	meeting, ok := m.Calendar.NextStart(now, end.Add(breakStretch))
+	if !ok {
+		return ""
+	}
+	length := meeting.Start.Sub(now).Truncate(time.Second)
+	if length < time.Minute {
+		return ""
+	}
+	m.Timer.SetLength(length)
+	return fmt.Sprintf("Break ends with %s", describeMeeting(meeting))
+}
+
+// describeMeeting names a meeting and when it starts, e.g. "Standup at 14:00"
+func describeMeeting(meeting calendar.Meeting) string {
+	summary := meeting.Summary
+	if summary == "" {
+		summary = "A meeting"
+	}
+	return summary + " at " + meeting.Start.Local().Format("15:04")
+}
+
+// upcomingMeeting describes the next meeting within the lookahead for the
+// timer view and reports whether it starts before the session ends
+func (m Model) upcomingMeeting(now time.Time) (string, bool) {
+	lookahead := m.Config.Calendar.Lookahead
+	if m.Calendar == nil || lookahead <= 0 {
+		return "", false
+	}
+	meeting, ok := m.Calendar.NextStart(now, now.Add(lookahead))
+	if !ok {
+		return "", false
+	}
+	in := meeting.Start.Sub(now)
+	when := "starting now"
+	if in >= time.Minute {
+		when = "in " + ui.FormatDuration(in)
+	}
+	return describeMeeting(meeting) + " · " + when, meeting.Start.Before(now.Add(m.Timer.Remaining))
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/calendar"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// meetingCalendar returns a calendar with a 30 minute meeting starting in
+// after
+func meetingCalendar(t *testing.T, summary string, after time.Duration) *calendar.Calendar {
+	t.Helper()
+	start := time.Now().Add(after).UTC()
+	content := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:" + summary + "\r\n" +
+		"DTSTART:" + start.Format("20060102T150405Z") + "\r\nDURATION:PT30M\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+	path := filepath.Join(t.TempDir(), "work.ics")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	cal := calendar.New([]string{path})
+	require.NoError(t, cal.Refresh())
+	return cal
+}
+
+func TestFitToCalendar_WarnsAboutMeeting(t *testing.T) {
+	m := newTestModel()
+	m.Calendar = meetingCalendar(t, "Standup", 10*time.Minute)
+	m = startSession(t, m)
+
+	assert.Contains(t, m.Toast, "Standup at ")
+	assert.Contains(t, m.Toast, "starts before this session ends")
+	assert.Equal(t, 25*time.Minute, m.Timer.Duration, "the session keeps its length")
+}
+
+func TestFitToCalendar_ShortensSession(t *testing.T) {
+	m := newTestModel()
+	m.Calendar = meetingCalendar(t, "Standup", 10*time.Minute)
+	m.Config.Calendar.Conflict = "shorten"
+	m = startSession(t, m)
+
+	assert.InDelta(t, float64(10*time.Minute), float64(m.Timer.Remaining), float64(2*time.Second))
+	assert.Equal(t, m.Timer.Duration, m.Timer.Remaining)
+	assert.True(t, strings.HasPrefix(m.Toast, "Shortened to 10m for Standup at "), m.Toast)
+
+	// Pausing and resuming leaves the length alone
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	result, _ = result.(Model).Update(tea.KeyMsg{Type: tea.KeySpace})
+	assert.Equal(t, m.Timer.Duration, result.(Model).Timer.Duration)
+}
+
+func TestFitToCalendar_WarnsWhenTooShortToShorten(t *testing.T) {
+	m := newTestModel()
+	m.Calendar = meetingCalendar(t, "Standup", 3*time.Minute)
+	m.Config.Calendar.Conflict = "shorten"
+	m = startSession(t, m)
+
+	assert.Equal(t, 25*time.Minute, m.Timer.Duration)
+	assert.Contains(t, m.Toast, "starts before this session ends")
+}
+
+func TestFitToCalendar_Off(t *testing.T) {
+	m := newTestModel()
+	m.Calendar = meetingCalendar(t, "Standup", 10*time.Minute)
+	m.Config.Calendar.Conflict = "off"
+	m = startSession(t, m)
+
+	assert.Empty(t, m.Toast)
+	assert.Equal(t, 25*time.Minute, m.Timer.Duration)
+}
+
+func TestFitToCalendar_AlignsBreaks(t *testing.T) {
+	tests := []struct {
+		name  string
+		after time.Duration
+		want  time.Duration
+	}{
+		{"stretched", 12 * time.Minute, 12 * time.Minute},
+		{"shortened", 3 * time.Minute, 3 * time.Minute},
+		{"too far off", 30 * time.Minute, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel()
+			m.Calendar = meetingCalendar(t, "Planning", tt.after)
+			m.Timer.Skip()
+			require.Equal(t, timer.ShortBreak, m.Timer.SessionType)
+			m = startSession(t, m)
+
+			assert.InDelta(t, float64(tt.want), float64(m.Timer.Duration), float64(2*time.Second))
+		})
+	}
+}
+
+func TestFitToCalendar_BreaksLeftAloneWhenNotAligning(t *testing.T) {
+	m := newTestModel()
+	m.Calendar = meetingCalendar(t, "Planning", 12*time.Minute)
+	m.Config.Calendar.AlignBreaks = false
+	m.Timer.Skip()
+	m = startSession(t, m)
+
+	assert.Equal(t, 5*time.Minute, m.Timer.Duration)
+}
+
+func TestUpcomingMeeting(t *testing.T) {
+	m := newTestModel()
+	m.Calendar = meetingCalendar(t, "Standup", 40*time.Minute+30*time.Second)
+	m.CurrentView = ViewTimer
+
+	meeting, conflict := m.upcomingMeeting(time.Now())
+	assert.True(t, strings.HasPrefix(meeting, "Standup at "), meeting)
+	assert.True(t, strings.HasSuffix(meeting, " · in 40m"), meeting)
+	assert.False(t, conflict, "the meeting starts after the session ends")
+	assert.Contains(t, m.View(), "📅 Standup at ")
+
+	m.Config.Calendar.Lookahead = 30 * time.Minute
+	meeting, _ = m.upcomingMeeting(time.Now())
+	assert.Empty(t, meeting, "beyond the lookahead")
+}
+
+func TestUpcomingMeeting_Conflict(t *testing.T) {
+	m := newTestModel()
+	m.Calendar = meetingCalendar(t, "Standup", 10*time.Minute)
+
+	_, conflict := m.upcomingMeeting(time.Now())
+	assert.True(t, conflict)
+}
+
+func TestCalendarPaths(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	paths := calendarPaths(config.CalendarConfig{Files: []string{"~/cal/work.ics", "team.ics", "/srv/home.ics"}}, "/work")
+
+	assert.Equal(t, []string{filepath.Join(home, "cal/work.ics"), "/work/team.ics", "/srv/home.ics"}, paths)
+	assert.Nil(t, newCalendar(config.CalendarConfig{}, "/work"))
+}
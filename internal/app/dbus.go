@@ -0,0 +1,70 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/dbus"
+)
+
+// DBusConnectedMsg reports the outcome of registering on the session bus
+type DBusConnectedMsg struct {
+	Service *dbus.Service
+	Err     error
+}
+
+// DBusCommandMsg is a method called over the bus
+type DBusCommandMsg struct {
+	Command string
+}
+
+// connectDBus registers the timer on the session bus in the background
+func connectDBus(cfg config.DBusConfig) tea.Cmd {
+	if !cfg.Enabled {
+		return nil
+	}
+	return func() tea.Msg {
+		s, err := dbus.Connect("")
+		return DBusConnectedMsg{Service: s, Err: err}
+	}
+}
+
+// awaitDBusCommand waits for the next method call
+func awaitDBusCommand(s *dbus.Service) tea.Cmd {
+	if s == nil {
+		return nil
+	}
+	commands := s.Commands()
+	return func() tea.Msg {
+		return DBusCommandMsg{Command: <-commands}
+	}
+}
+
+// dbusConnected starts exposing the timer once registered
+func (m Model) dbusConnected(msg DBusConnectedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.showToast(msg.Err.Error())
+	}
+	m.DBus = msg.Service
+	m.publishDBus()
+	return m, awaitDBusCommand(m.DBus)
+}
+
+// dbusCommand toggles, skips or resets for a desktop extension or script
+func (m Model) dbusCommand(msg DBusCommandMsg) (tea.Model, tea.Cmd) {
+	next := awaitDBusCommand(m.DBus)
+	result, cmd := m.remoteCommand(msg.Command)
+	return result, tea.Batch(cmd, next)
+}
+
+// publishDBus updates the properties on the bus
+func (m Model) publishDBus() {
+	if m.DBus == nil {
+		return
+	}
+	m.DBus.Publish(dbus.State{
+		SessionType:   string(m.Timer.SessionType),
+		Remaining:     m.Timer.Remaining,
+		Running:       m.Timer.Running,
+		PomodoroCount: m.Timer.PomodoroCount,
+	})
+}
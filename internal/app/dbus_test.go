@@ -0,0 +1,61 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/dbus"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBusCommand_Toggle(t *testing.T) {
+	m := newTestModel()
+
+	result, _ := m.Update(DBusCommandMsg{Command: dbus.Toggle})
+	model := result.(Model)
+
+	assert.Equal(t, ViewTimer, model.CurrentView, "the splash is dismissed")
+	assert.True(t, model.Timer.Running)
+	assert.False(t, model.SessionStart.IsZero())
+}
+
+func TestDBusCommand_Skip(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+
+	result, _ := m.Update(DBusCommandMsg{Command: dbus.Skip})
+
+	assert.Equal(t, timer.ShortBreak, result.(Model).Timer.SessionType)
+	assert.Equal(t, ViewComplete, result.(Model).CurrentView)
+}
+
+func TestDBusCommand_Reset(t *testing.T) {
+	m := newTestModel()
+	m.CurrentView = ViewTimer
+	result, _ := m.Update(DBusCommandMsg{Command: dbus.Toggle})
+	model := result.(Model)
+	model.Timer.Tick()
+
+	result, _ = model.Update(DBusCommandMsg{Command: dbus.Reset})
+	model = result.(Model)
+
+	assert.False(t, model.Timer.Running)
+	assert.Equal(t, model.Timer.Duration, model.Timer.Remaining)
+	assert.True(t, model.SessionStart.IsZero())
+}
+
+func TestDBusConnected_Error(t *testing.T) {
+	m := newTestModel()
+
+	result, _ := m.Update(DBusConnectedMsg{Err: errors.New("dbus: io.github.pomodoro.Timer is taken, is pomodoro already running?")})
+
+	assert.Equal(t, "dbus: io.github.pomodoro.Timer is taken, is pomodoro already running?", result.(Model).Toast)
+	assert.Nil(t, result.(Model).DBus)
+}
+
+func TestConnectDBus_Off(t *testing.T) {
+	assert.Nil(t, connectDBus(config.DBusConfig{}))
+	assert.Nil(t, awaitDBusCommand(nil))
+}
@@ -1,6 +1,12 @@
 package app
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+)
 
 // KeyMap defines all keyboard bindings
 type KeyMap struct {
@@ -8,8 +14,33 @@ type KeyMap struct {
 	Skip   key.Binding
 	Reset  key.Binding
 	Notify key.Binding
-	Help   key.Binding
-	Quit   key.Binding
+	Labels key.Binding
+
+	PickTask     key.Binding
+	CompleteTask key.Binding
+	PickProfile  key.Binding
+	Plan         key.Binding
+	DoNotDisturb key.Binding
+	Help         key.Binding
+	Quit         key.Binding
+
+	// Reflection prompt
+	SaveReflection key.Binding
+	SkipReflection key.Binding
+	FocusUp        key.Binding
+	FocusDown      key.Binding
+
+	// Pickers
+	Confirm        key.Binding
+	Cancel         key.Binding
+	PickerNext     key.Binding
+	PickerPrev     key.Binding
+	PickerComplete key.Binding
+
+	// Day plan
+	MoveUp   key.Binding
+	MoveDown key.Binding
+	Remove   key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -31,6 +62,30 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("n"),
 			key.WithHelp("n", "toggle notifications"),
 		),
+		Labels: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "set project/tags"),
+		),
+		PickTask: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "pick active task"),
+		),
+		CompleteTask: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "mark task done"),
+		),
+		PickProfile: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "switch profile"),
+		),
+		Plan: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "plan the day"),
+		),
+		DoNotDisturb: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "toggle do not disturb"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "toggle help"),
@@ -39,5 +94,104 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
 		),
+		SaveReflection: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "save note"),
+		),
+		SkipReflection: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "skip note"),
+		),
+		FocusUp: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "raise focus rating"),
+		),
+		FocusDown: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "lower focus rating"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		PickerNext: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "next suggestion"),
+		),
+		PickerPrev: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "previous suggestion"),
+		),
+		PickerComplete: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "complete suggestion"),
+		),
+		MoveUp: key.NewBinding(
+			key.WithKeys("shift+up"),
+			key.WithHelp("shift+↑", "move block up"),
+		),
+		MoveDown: key.NewBinding(
+			key.WithKeys("shift+down"),
+			key.WithHelp("shift+↓", "move block down"),
+		),
+		Remove: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "remove block"),
+		),
+	}
+}
+
+// KeyMapFromConfig returns the default key bindings with the configured
+// overrides applied
+func KeyMapFromConfig(cfg config.KeyConfig) KeyMap {
+	km := DefaultKeyMap()
+	rebind(&km.Toggle, cfg.Toggle)
+	rebind(&km.Skip, cfg.Skip)
+	rebind(&km.Reset, cfg.Reset)
+	rebind(&km.Notify, cfg.Notify)
+	rebind(&km.Labels, cfg.Labels)
+	rebind(&km.PickTask, cfg.PickTask)
+	rebind(&km.CompleteTask, cfg.CompleteTask)
+	rebind(&km.PickProfile, cfg.PickProfile)
+	rebind(&km.Plan, cfg.Plan)
+	rebind(&km.DoNotDisturb, cfg.DoNotDisturb)
+	rebind(&km.Help, cfg.Help)
+	rebind(&km.Quit, cfg.Quit)
+	return km
+}
+
+// rebind replaces a binding's keys, keeping its description
+// "space" stands for the space bar
+func rebind(b *key.Binding, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	bound := make([]string, len(keys))
+	for i, k := range keys {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "space" {
+			k = " "
+		}
+		bound[i] = k
+	}
+	b.SetKeys(bound...)
+	b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+}
+
+// HelpItems lists the main bindings for the help overlay
+func (km KeyMap) HelpItems() []ui.HelpItem {
+	bindings := []key.Binding{
+		km.Toggle, km.Skip, km.Reset, km.Labels, km.PickTask,
+		km.CompleteTask, km.PickProfile, km.Plan, km.Notify, km.DoNotDisturb,
+		km.Help, km.Quit,
+	}
+	items := make([]ui.HelpItem, len(bindings))
+	for i, b := range bindings {
+		items[i] = ui.HelpItem{Key: b.Help().Key, Desc: b.Help().Desc}
 	}
+	return items
 }
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -112,3 +114,53 @@ func TestKeyMap_AllBindingsHaveHelp(t *testing.T) {
 		assert.NotEmpty(t, help.Desc, "binding should have help description")
 	}
 }
+
+func TestDefaultKeyMap_ReflectionKeys(t *testing.T) {
+	km := DefaultKeyMap()
+
+	assert.Contains(t, km.SaveReflection.Keys(), "enter")
+	assert.Contains(t, km.SkipReflection.Keys(), "esc")
+	assert.Contains(t, km.FocusUp.Keys(), "up")
+	assert.Contains(t, km.FocusDown.Keys(), "down")
+}
+
+func TestDefaultKeyMap_PickerKeys(t *testing.T) {
+	km := DefaultKeyMap()
+
+	assert.Contains(t, km.Labels.Keys(), "t")
+	assert.Contains(t, km.Confirm.Keys(), "enter")
+	assert.Contains(t, km.Cancel.Keys(), "esc")
+	assert.Contains(t, km.PickerNext.Keys(), "down")
+	assert.Contains(t, km.PickerPrev.Keys(), "up")
+	assert.Contains(t, km.PickerComplete.Keys(), "tab")
+}
+
+func TestDefaultKeyMap_TaskKeys(t *testing.T) {
+	km := DefaultKeyMap()
+
+	assert.Contains(t, km.PickTask.Keys(), "a")
+	assert.Contains(t, km.CompleteTask.Keys(), "x")
+}
+
+func TestKeyMapFromConfig(t *testing.T) {
+	km := KeyMapFromConfig(config.KeyConfig{
+		Toggle: []string{"space", "P"},
+		Quit:   []string{"ctrl+q"},
+	})
+
+	assert.Equal(t, []string{" ", "p"}, km.Toggle.Keys())
+	assert.Equal(t, "space/P", km.Toggle.Help().Key)
+	assert.Equal(t, "start/pause", km.Toggle.Help().Desc)
+	assert.Equal(t, []string{"ctrl+q"}, km.Quit.Keys())
+	assert.Equal(t, DefaultKeyMap().Skip.Keys(), km.Skip.Keys(), "unset actions keep their defaults")
+}
+
+func TestKeyMap_HelpItems(t *testing.T) {
+	km := KeyMapFromConfig(config.KeyConfig{Skip: []string{"k"}})
+
+	items := km.HelpItems()
+
+	assert.Contains(t, items, ui.HelpItem{Key: "k", Desc: "skip session"})
+	assert.Contains(t, items, ui.HelpItem{Key: "space/enter", Desc: "start/pause"})
+	assert.Contains(t, items, ui.HelpItem{Key: "l", Desc: "plan the day"})
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+)
+
+// ParseLabels splits picker input into a project and tags
+// Words starting with # are tags; any other word names the project
+func ParseLabels(s string) (project string, tags []string) {
+	seen := map[string]bool{}
+	for _, word := range strings.Fields(s) {
+		if tag, ok := strings.CutPrefix(word, "#"); ok {
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+			continue
+		}
+		project = word
+	}
+	return project, tags
+}
+
+// FormatLabels renders a project and tags in picker syntax
+func FormatLabels(project string, tags []string) string {
+	words := make([]string, 0, len(tags)+1)
+	if project != "" {
+		words = append(words, project)
+	}
+	for _, tag := range tags {
+		words = append(words, "#"+tag)
+	}
+	return strings.Join(words, " ")
+}
+
+// openLabelPicker shows the project and tag picker
+func (m Model) openLabelPicker() (tea.Model, tea.Cmd) {
+	var projects, tags []string
+	if m.History != nil {
+		if records, err := m.History.All(); err == nil {
+			projects = history.Projects(records)
+			tags = history.Tags(records)
+		}
+	}
+	m.KnownProjects = mergeUnique([]string{m.Project}, projects, []string{m.Config.Projects.Default})
+	m.KnownTags = mergeUnique(m.Tags, tags, m.Config.Projects.Tags)
+
+	input := textinput.New()
+	input.Placeholder = "project #tag #tag"
+	input.CharLimit = 120
+	input.Width = 40
+	if labels := FormatLabels(m.Project, m.Tags); labels != "" {
+		input.SetValue(labels + " ")
+	}
+	input.Focus()
+
+	m.LabelInput = input
+	m.LabelChoice = 0
+	m.Labeling = true
+	m.LabelSuggestions = m.labelSuggestions()
+	return m, textinput.Blink
+}
+
+// handleLabelKey handles keys while the label picker is open
+func (m Model) handleLabelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, m.quit()
+
+	case key.Matches(msg, m.Keys.Cancel):
+		m.Labeling = false
+		return m, nil
+
+	case key.Matches(msg, m.Keys.Confirm):
+		m.Project, m.Tags = ParseLabels(m.LabelInput.Value())
+		m.Labeling = false
+		return m, nil
+
+	case key.Matches(msg, m.Keys.PickerComplete):
+		if len(m.LabelSuggestions) > 0 {
+			m.LabelInput.SetValue(completeLastWord(m.LabelInput.Value(), m.LabelSuggestions[m.LabelChoice]) + " ")
+			m.LabelInput.CursorEnd()
+			m.LabelChoice = 0
+			m.LabelSuggestions = m.labelSuggestions()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.PickerNext):
+		if m.LabelChoice < len(m.LabelSuggestions)-1 {
+			m.LabelChoice++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.PickerPrev):
+		if m.LabelChoice > 0 {
+			m.LabelChoice--
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.LabelInput, cmd = m.LabelInput.Update(msg)
+	m.LabelChoice = 0
+	m.LabelSuggestions = m.labelSuggestions()
+	return m, cmd
+}
+
+// labelSuggestions fuzzy-filters known labels against the word being typed
+func (m Model) labelSuggestions() []string {
+	value := m.LabelInput.Value()
+	word := ""
+	if !strings.HasSuffix(value, " ") {
+		if fields := strings.Fields(value); len(fields) > 0 {
+			word = fields[len(fields)-1]
+		}
+	}
+
+	if query, ok := strings.CutPrefix(word, "#"); ok {
+		matches := ui.FuzzyFilter(query, m.KnownTags)
+		suggestions := make([]string, len(matches))
+		for i, tag := range matches {
+			suggestions[i] = "#" + tag
+		}
+		return suggestions
+	}
+	return ui.FuzzyFilter(word, m.KnownProjects)
+}
+
+// completeLastWord replaces the word being typed with a suggestion
+func completeLastWord(value, suggestion string) string {
+	if strings.HasSuffix(value, " ") || value == "" {
+		return value + suggestion
+	}
+	i := strings.LastIndex(value, " ")
+	return value[:i+1] + suggestion
+}
+
+// mergeUnique concatenates lists, dropping empty and repeated values
+func mergeUnique(lists ...[]string) []string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, list := range lists {
+		for _, v := range list {
+			if v != "" && !seen[v] {
+				seen[v] = true
+				merged = append(merged, v)
+			}
+		}
+	}
+	return merged
+}
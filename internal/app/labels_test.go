@@ -0,0 +1,145 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		input   string
+		project string
+		tags    []string
+	}{
+		{"", "", nil},
+		{"api", "api", nil},
+		{"api #backend #q2", "api", []string{"backend", "q2"}},
+		{"#q2 api #q2 #", "api", []string{"q2"}},
+		{"old new", "new", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			project, tags := ParseLabels(tt.input)
+			assert.Equal(t, tt.project, project)
+			assert.Equal(t, tt.tags, tags)
+		})
+	}
+}
+
+func TestFormatLabels(t *testing.T) {
+	assert.Equal(t, "api #backend #q2", FormatLabels("api", []string{"backend", "q2"}))
+	assert.Equal(t, "#q2", FormatLabels("", []string{"q2"}))
+	assert.Empty(t, FormatLabels("", nil))
+}
+
+func TestCompleteLastWord(t *testing.T) {
+	assert.Equal(t, "api #backend", completeLastWord("api #ba", "#backend"))
+	assert.Equal(t, "api", completeLastWord("ap", "api"))
+	assert.Equal(t, "api #q2", completeLastWord("api ", "#q2"))
+	assert.Equal(t, "api", completeLastWord("", "api"))
+}
+
+func typeText(m tea.Model, text string) tea.Model {
+	for _, r := range text {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func TestLabelPicker_SuggestsFromHistory(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	require.NoError(t, m.History.Append(history.Record{ID: "a", Project: "website", Tags: []string{"frontend"}}))
+	require.NoError(t, m.History.Append(history.Record{ID: "b", Project: "api-server", Tags: []string{"backend"}}))
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	require.True(t, result.(Model).Labeling)
+
+	result = typeText(result, "srv")
+	assert.Equal(t, []string{"api-server"}, result.(Model).LabelSuggestions)
+
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyTab})
+	result = typeText(result, "#b")
+	assert.Equal(t, []string{"#backend"}, result.(Model).LabelSuggestions)
+
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyTab})
+	result = typeText(result, "#new")
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := result.(Model)
+
+	assert.False(t, model.Labeling)
+	assert.Equal(t, "api-server", model.Project)
+	assert.Equal(t, []string{"backend", "new"}, model.Tags)
+	assert.Contains(t, model.View(), "api-server · #backend #new")
+}
+
+func TestLabelPicker_ChooseWithArrows(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Config.Projects.Default = "misc"
+	require.NoError(t, m.History.Append(history.Record{ID: "a", Project: "docs"}))
+
+	result, _ := m.openLabelPicker()
+	assert.Equal(t, []string{"docs", "misc"}, result.(Model).LabelSuggestions)
+
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyDown})
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyDown})
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyTab})
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, "misc", result.(Model).Project)
+}
+
+func TestLabelPicker_CancelKeepsLabels(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Project = "api"
+
+	result, _ := m.openLabelPicker()
+	assert.Equal(t, "api ", result.(Model).LabelInput.Value(), "input starts with current labels")
+	result = typeText(result, "#q")
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model := result.(Model)
+
+	assert.False(t, model.Labeling)
+	assert.Equal(t, "api", model.Project)
+	assert.Nil(t, model.Tags)
+	assert.Equal(t, ViewTimer, model.CurrentView)
+}
+
+func TestLabelPicker_KeysAreTyped(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+
+	result, _ := m.openLabelPicker()
+	result = typeText(result, "qs r")
+	model := result.(Model)
+
+	assert.True(t, model.Labeling, "q must not quit while typing")
+	assert.Equal(t, "qs r", model.LabelInput.Value())
+	assert.False(t, model.Timer.Running)
+}
+
+func TestRecordSession_IncludesLabels(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.Project = "api"
+	m.Tags = []string{"backend"}
+	m.SessionStart = time.Now().Add(-25 * time.Minute)
+	m.Timer.Remaining = 0
+
+	m.recordSession(true)
+
+	records, err := m.History.All()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "api", records[0].Project)
+	assert.Equal(t, []string{"backend"}, records[0].Tags)
+	assert.Equal(t, timer.Work, records[0].Type)
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/metrics"
+)
+
+// startMetrics serves /metrics when a listen address is configured
+func startMetrics(cfg config.MetricsConfig) (*metrics.Metrics, *metrics.Server, error) {
+	if cfg.Listen == "" {
+		return nil, nil, nil
+	}
+	m := metrics.New()
+	srv, err := metrics.Serve(m, cfg.Listen)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, srv, nil
+}
+
+// recordMetrics counts a session that ended with the given outcome
+func (m Model) recordMetrics(outcome string, start time.Time) {
+	if m.Metrics == nil || start.IsZero() {
+		return
+	}
+	m.Metrics.Record(string(m.Timer.SessionType), outcome, m.Timer.Duration-m.Timer.Remaining)
+}
+
+// updateMetrics sets the timer state and today's pomodoros
+func (m Model) updateMetrics() {
+	if m.Metrics == nil {
+		return
+	}
+	m.Metrics.SetState(string(m.Timer.SessionType), m.Timer.Running, m.Timer.Remaining)
+	m.Metrics.SetToday(m.pomodorosToday())
+}
+
+// pomodorosToday counts the work sessions completed since the day began
+func (m Model) pomodorosToday() int {
+	if m.DailyGoal.Tracked() || m.History == nil {
+		return m.DailyGoal.Pomodoros
+	}
+	records, err := m.History.All()
+	if err != nil {
+		return 0
+	}
+	boundary, _ := m.Config.Goals.DayBoundary()
+	now := time.Now()
+	return history.Sum(records, history.DayStart(now, boundary), now.Add(time.Second)).Pomodoros
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exposition returns the metrics in the Prometheus text format
+func exposition(m Model) string {
+	rec := httptest.NewRecorder()
+	m.Metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	return rec.Body.String()
+}
+
+func TestMetrics_Completed(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Metrics = metrics.New()
+	m.SessionStart = time.Now().Add(-25 * time.Minute)
+	m.Timer.Remaining = 0
+
+	result, _ := m.handleSessionComplete()
+	out := exposition(result.(Model))
+
+	assert.Contains(t, out, `pomodoro_sessions_total{outcome="completed",type="work"} 1`)
+	assert.Contains(t, out, `pomodoro_session_length_seconds_sum{type="work"} 1500`)
+	assert.Contains(t, out, "pomodoro_pomodoros_today 1")
+	assert.Contains(t, out, `pomodoro_state{state="paused",type="short_break"} 1`)
+}
+
+func TestMetrics_Skipped(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Metrics = metrics.New()
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	result, _ = result.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	assert.Contains(t, exposition(result.(Model)), `pomodoro_sessions_total{outcome="skipped",type="work"} 1`)
+}
+
+func TestMetrics_Voided(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Metrics = metrics.New()
+
+	// Resetting a session that never started voids nothing
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	assert.Contains(t, exposition(result.(Model)), `pomodoro_sessions_total{outcome="voided",type="work"} 0`)
+
+	result, _ = result.(Model).Update(tea.KeyMsg{Type: tea.KeySpace})
+	out := exposition(result.(Model))
+	assert.Contains(t, out, `pomodoro_state{state="running",type="work"} 1`)
+
+	result, _ = result.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	out = exposition(result.(Model))
+	assert.Contains(t, out, `pomodoro_sessions_total{outcome="voided",type="work"} 1`)
+	assert.Contains(t, out, "pomodoro_pomodoros_today 0")
+	assert.Contains(t, out, `pomodoro_state{state="paused",type="work"} 1`)
+}
+
+func TestStartMetrics(t *testing.T) {
+	stats, srv, err := startMetrics(config.MetricsConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, stats, "off without a listen address")
+	assert.Nil(t, srv)
+
+	stats, srv, err = startMetrics(config.MetricsConfig{Listen: "127.0.0.1:0"})
+	require.NoError(t, err)
+	defer srv.Close()
+	assert.NotNil(t, stats)
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/mqtt"
+)
+
+// mqttInterval is how often the state is published between transitions
+const mqttInterval = time.Minute
+
+// MQTTConnectedMsg reports the outcome of connecting to the broker
+type MQTTConnectedMsg struct {
+	Publisher *mqtt.Publisher
+	Err       error
+}
+
+// MQTTTickMsg triggers the periodic state update
+type MQTTTickMsg time.Time
+
+// MQTTCommandMsg is a command received on the command topic
+type MQTTCommandMsg struct {
+	Command string
+}
+
+// connectMQTT connects to the configured broker in the background
+func connectMQTT(cfg config.MQTTConfig) tea.Cmd {
+	if cfg.Broker == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		creds, err := config.LoadCredentials()
+		if err != nil {
+			return MQTTConnectedMsg{Err: err}
+		}
+		p, err := mqtt.Connect(cfg, creds.MQTT)
+		return MQTTConnectedMsg{Publisher: p, Err: err}
+	}
+}
+
+// mqttTick schedules the next periodic state update
+func mqttTick() tea.Cmd {
+	return tea.Tick(mqttInterval, func(t time.Time) tea.Msg {
+		return MQTTTickMsg(t)
+	})
+}
+
+// awaitMQTTCommand waits for the next command from the broker
+func awaitMQTTCommand(p *mqtt.Publisher) tea.Cmd {
+	if p == nil || p.Commands() == nil {
+		return nil
+	}
+	commands := p.Commands()
+	return func() tea.Msg {
+		return MQTTCommandMsg{Command: <-commands}
+	}
+}
+
+// mqttConnected starts publishing once connected
+func (m Model) mqttConnected(msg MQTTConnectedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.showToast(msg.Err.Error())
+	}
+	m.MQTT = msg.Publisher
+	m.publishState()
+	return m, tea.Batch(mqttTick(), awaitMQTTCommand(m.MQTT))
+}
+
+// mqttCommand toggles or skips as if the key had been pressed in the
+// timer view, e.g. from a button by the door
+func (m Model) mqttCommand(msg MQTTCommandMsg) (tea.Model, tea.Cmd) {
+	next := awaitMQTTCommand(m.MQTT)
+	result, cmd := m.remoteCommand(msg.Command)
+	return result, tea.Batch(cmd, next)
+}
+
+// publishState sends the timer state to the broker, the bus and the
+// metrics
+func (m Model) publishState() {
+	m.updateMetrics()
+	m.publishDBus()
+	if m.MQTT == nil {
+		return
+	}
+	m.MQTT.Publish(mqtt.State{
+		Session:   string(m.Timer.SessionType),
+		Remaining: m.Timer.Remaining,
+		Duration:  m.Timer.Duration,
+		Running:   m.Timer.Running,
+	})
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/mqtt"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMQTTCommand_Toggle(t *testing.T) {
+	m := newTestModel()
+
+	result, _ := m.Update(MQTTCommandMsg{Command: mqtt.Toggle})
+	model := result.(Model)
+
+	assert.Equal(t, ViewTimer, model.CurrentView, "the splash is dismissed")
+	assert.True(t, model.Timer.Running)
+	assert.False(t, model.SessionStart.IsZero())
+
+	result, _ = model.Update(MQTTCommandMsg{Command: mqtt.Toggle})
+	assert.False(t, result.(Model).Timer.Running)
+}
+
+func TestMQTTCommand_Skip(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+
+	result, _ := m.Update(MQTTCommandMsg{Command: mqtt.Skip})
+	model := result.(Model)
+
+	assert.Equal(t, timer.ShortBreak, model.Timer.SessionType)
+	assert.Equal(t, ViewComplete, model.CurrentView)
+}
+
+func TestMQTTCommand_StartsNextSessionFromCompleteView(t *testing.T) {
+	m := newTestModel()
+	m.CurrentView = ViewComplete
+
+	result, _ := m.Update(MQTTCommandMsg{Command: mqtt.Toggle})
+	model := result.(Model)
+
+	assert.Equal(t, ViewTimer, model.CurrentView)
+	assert.True(t, model.Timer.Running)
+}
+
+func TestMQTTConnected_Error(t *testing.T) {
+	m := newTestModel()
+
+	result, _ := m.Update(MQTTConnectedMsg{Err: errors.New("mqtt tcp://broker:1883: connection refused")})
+
+	assert.Equal(t, "mqtt tcp://broker:1883: connection refused", result.(Model).Toast)
+	assert.Nil(t, result.(Model).MQTT)
+}
+
+func TestConnectMQTT_Off(t *testing.T) {
+	assert.Nil(t, connectMQTT(config.MQTTConfig{}))
+}
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/plan"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+)
+
+// planDay returns when the day containing now began, by the goals' day
+// boundary
+func planDay(goals config.GoalConfig, now time.Time) time.Time {
+	boundary, _ := goals.DayBoundary()
+	return history.DayStart(now, boundary)
+}
+
+// loadPlan reads today's plan from the default location, along with an
+// earlier day's plan still kept there, if it had any blocks
+func loadPlan(day time.Time) (today, past *plan.Plan, path string, err error) {
+	path, err = plan.DefaultPath()
+	if err != nil {
+		return plan.New(day), nil, "", err
+	}
+	today, err = plan.Load(path, day)
+	if err != nil || len(today.Blocks) > 0 {
+		return today, nil, path, err
+	}
+	if stored, _ := plan.Read(path); stored != nil && stored.Before(day) && len(stored.Blocks) > 0 {
+		past = stored
+	}
+	return today, past, path, nil
+}
+
+// todaysPlan returns the plan for the day containing now, starting a new
+// one once the day is over
+func (m *Model) todaysPlan(now time.Time) *plan.Plan {
+	day := planDay(m.Config.Goals, now)
+	if m.Plan == nil || !m.Plan.For(day) {
+		if m.Plan != nil && len(m.Plan.Blocks) > 0 {
+			// Show how the day went, finished or not, before starting afresh
+			m.PastPlan = m.Plan
+		}
+		m.Plan = plan.New(day)
+		m.PlanBlock = -1
+	}
+	return m.Plan
+}
+
+// savePlan writes the plan, returning why it couldn't be
+func (m *Model) savePlan() error {
+	if m.PlanPath == "" {
+		return nil
+	}
+	if err := m.Plan.Save(m.PlanPath); err != nil {
+		return err
+	}
+	if m.PlanWatcher != nil {
+		// Our own write isn't a change to pick up
+		m.PlanWatcher.Changed()
+	}
+	return nil
+}
+
+// reloadPlan picks up changes made to the plan file by another process,
+// such as `pomodoro plan add`, so that the next save doesn't drop them
+func (m *Model) reloadPlan(now time.Time) {
+	if m.PlanWatcher == nil || !m.PlanWatcher.Changed() {
+		return
+	}
+	p, err := plan.Load(m.PlanPath, planDay(m.Config.Goals, now))
+	if err != nil {
+		return
+	}
+	m.Plan = p
+	m.PlanChoice = max(min(m.PlanChoice, len(p.Blocks)-1), 0)
+}
+
+// openPlan opens the day plan editor
+func (m Model) openPlan() (tea.Model, tea.Cmd) {
+	m.reloadPlan(time.Now())
+	p := m.todaysPlan(time.Now())
+
+	input := textinput.New()
+	input.Placeholder = "3 API refactor, long break, 14:00 2 reviews"
+	input.CharLimit = 120
+	input.Width = 44
+	input.Focus()
+
+	m.PlanInput = input
+	m.PlanChoice = max(p.Current(time.Now()), 0)
+	m.PlanMessage = ""
+	m.Planning = true
+	return m, textinput.Blink
+}
+
+// handlePlanKey handles keys while the day plan editor is open
+func (m Model) handlePlanKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.reloadPlan(time.Now())
+	p := m.todaysPlan(time.Now())
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, m.quit()
+
+	case key.Matches(msg, m.Keys.Cancel):
+		m.Planning = false
+		return m, nil
+
+	case key.Matches(msg, m.Keys.Confirm):
+		if m.PlanInput.Value() == "" {
+			m.Planning = false
+			return m, nil
+		}
+		block, err := plan.Parse(m.PlanInput.Value())
+		if err != nil {
+			m.PlanMessage = err.Error()
+			return m, nil
+		}
+		p.Blocks = append(p.Blocks, block)
+		m.PlanChoice = len(p.Blocks) - 1
+		m.PlanInput.Reset()
+		return m.planChanged()
+
+	case key.Matches(msg, m.Keys.PickerNext):
+		if m.PlanChoice < len(p.Blocks)-1 {
+			m.PlanChoice++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.PickerPrev):
+		if m.PlanChoice > 0 {
+			m.PlanChoice--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.MoveUp):
+		if i := m.PlanChoice; i > 0 && i < len(p.Blocks) {
+			p.Blocks[i-1], p.Blocks[i] = p.Blocks[i], p.Blocks[i-1]
+			m.PlanChoice--
+			return m.planChanged()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.MoveDown):
+		if i := m.PlanChoice; i < len(p.Blocks)-1 {
+			p.Blocks[i], p.Blocks[i+1] = p.Blocks[i+1], p.Blocks[i]
+			m.PlanChoice++
+			return m.planChanged()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.Remove):
+		if i := m.PlanChoice; i < len(p.Blocks) {
+			p.Blocks = append(p.Blocks[:i], p.Blocks[i+1:]...)
+			m.PlanChoice = max(min(i, len(p.Blocks)-1), 0)
+			return m.planChanged()
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.PlanInput, cmd = m.PlanInput.Update(msg)
+	m.PlanMessage = ""
+	return m, cmd
+}
+
+// planChanged saves the edited plan and fits the next session to it
+func (m Model) planChanged() (tea.Model, tea.Cmd) {
+	m.PlanMessage = ""
+	if err := m.savePlan(); err != nil {
+		m.PlanMessage = "Couldn't save the plan: " + err.Error()
+	}
+	m.PlanBlock = m.Plan.Current(time.Now())
+	m.followPlan()
+	m.publishState()
+	return m, nil
+}
+
+// currentBlock returns the block being worked on, if the day is planned
+// and not yet done
+func (m Model) currentBlock(now time.Time) (plan.Block, bool) {
+	if m.Plan == nil || !m.Plan.For(planDay(m.Config.Goals, now)) {
+		return plan.Block{}, false
+	}
+	i := m.Plan.Current(now)
+	if i < 0 {
+		return plan.Block{}, false
+	}
+	return m.Plan.Blocks[i], true
+}
+
+// plannedTask returns the task of the current work block, or ""
+func (m Model) plannedTask() string {
+	b, ok := m.currentBlock(time.Now())
+	if !ok || b.Break {
+		return ""
+	}
+	return b.Task
+}
+
+// recordPlan counts a session that is ending towards the current block
+// A work session counts once started and completed; a long break counts
+// however it ends, so that skipping it moves the plan on
+func (m *Model) recordPlan(start time.Time, completed bool) {
+	now := time.Now()
+	m.reloadPlan(now)
+	if m.Plan == nil || !m.Plan.For(planDay(m.Config.Goals, now)) {
+		return
+	}
+	i := m.Plan.Current(now)
+	if i < 0 {
+		return
+	}
+	switch b := m.Plan.Blocks[i]; {
+	case b.Break && m.Timer.SessionType == timer.LongBreak:
+		if start.IsZero() {
+			start = now
+		}
+	case !b.Break && m.Timer.SessionType == timer.Work && completed && !start.IsZero():
+	default:
+		return
+	}
+	m.Plan.Record(i, start)
+	m.PlanBlock = m.Plan.Current(now)
+	_ = m.savePlan()
+}
+
+// followPlan makes the session that is about to start a long break when
+// the plan has one next
+func (m *Model) followPlan() {
+	if b, ok := m.currentBlock(time.Now()); ok && b.Break {
+		m.Timer.UseLongBreak()
+	}
+}
+
+// checkPlan notices a block whose start time has come, moving the plan on
+// to it, and a new day beginning
+func (m *Model) checkPlan(now time.Time) tea.Cmd {
+	m.reloadPlan(now)
+	if m.Plan == nil {
+		return nil
+	}
+	p := m.todaysPlan(now)
+	current := p.Current(now)
+	if current == m.PlanBlock {
+		return nil
+	}
+	m.PlanBlock = current
+	if current < 0 {
+		return nil
+	}
+	m.followPlan()
+	b := p.Blocks[current]
+	if b.Start == "" {
+		return nil
+	}
+	what := b.Task
+	if b.Break {
+		what = "a long break"
+	}
+	return m.showToast(fmt.Sprintf("▤ %s: time for %s", b.Start, what))
+}
+
+// planStatus describes where the day plan stands for the timer view, e.g.
+// "Block 1 of 3 · 2/3 · Next: Long break", or "" without a plan
+func (m Model) planStatus(now time.Time) string {
+	if m.Plan == nil || len(m.Plan.Blocks) == 0 || !m.Plan.For(planDay(m.Config.Goals, now)) {
+		return ""
+	}
+	current := m.Plan.Current(now)
+	if current < 0 {
+		return "Day plan done · " + ui.PlanTotals(m.Plan)
+	}
+	status := fmt.Sprintf("Block %d of %d", current+1, len(m.Plan.Blocks))
+	if b := m.Plan.Blocks[current]; !b.Break {
+		status += fmt.Sprintf(" · %d/%d", b.Done, b.Count)
+	}
+	if next := m.Plan.Next(now); next >= 0 {
+		status += " · Next: " + m.Plan.Blocks[next].Label()
+	}
+	return status
+}
+
+// planFinished returns whether every block of today's plan is done
+func (m Model) planFinished(now time.Time) bool {
+	return m.Plan != nil && len(m.Plan.Blocks) > 0 &&
+		m.Plan.For(planDay(m.Config.Goals, now)) && m.Plan.Current(now) < 0
+}
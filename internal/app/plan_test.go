@@ -0,0 +1,311 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/plan"
+	"github.com/kanishkathakur1/pomodoro/internal/task"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// todayWith returns today's plan for m made of blocks
+func todayWith(m Model, blocks ...plan.Block) *plan.Plan {
+	p := plan.New(planDay(m.Config.Goals, time.Now()))
+	p.Blocks = blocks
+	return p
+}
+
+func TestPlan_AddBlocks(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m)
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m = result.(Model)
+	require.True(t, m.Planning)
+
+	for _, line := range []string{"3 pomodoros on API refactor", "long break", "14:00 2 reviews"} {
+		m = typeText(m, line).(Model)
+		result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = result.(Model)
+	}
+
+	require.Len(t, m.Plan.Blocks, 3)
+	assert.Equal(t, plan.Block{Task: "API refactor", Count: 3}, m.Plan.Blocks[0])
+	assert.True(t, m.Plan.Blocks[1].Break)
+	assert.Equal(t, "14:00", m.Plan.Blocks[2].Start)
+	assert.Equal(t, 2, m.PlanChoice, "the added block is selected")
+	assert.Empty(t, m.PlanInput.Value())
+
+	saved, err := plan.Load(m.PlanPath, planDay(m.Config.Goals, time.Now()))
+	require.NoError(t, err)
+	assert.Len(t, saved.Blocks, 3)
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, result.(Model).Planning, "enter on an empty line closes the plan")
+}
+
+func TestPlan_InvalidLine(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m)
+	result, _ := m.openPlan()
+	m = typeText(result, "25:00 reviews").(Model)
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+
+	assert.Contains(t, m.PlanMessage, "want HH:MM")
+	assert.Empty(t, m.Plan.Blocks)
+	assert.Equal(t, "25:00 reviews", m.PlanInput.Value(), "the line is kept to fix")
+	assert.Contains(t, m.View(), "want HH:MM")
+}
+
+func TestPlan_MoveAndRemove(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m,
+		plan.Block{Task: "A", Count: 1},
+		plan.Block{Task: "B", Count: 1},
+		plan.Block{Task: "C", Count: 1},
+	)
+	result, _ := m.openPlan()
+	m = result.(Model)
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftDown})
+	m = result.(Model)
+	assert.Equal(t, []string{"B", "A", "C"}, blockTasks(m.Plan))
+	assert.Equal(t, 1, m.PlanChoice, "the selection follows the block")
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	result, _ = result.(Model).Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = result.(Model)
+	assert.Equal(t, []string{"B", "A"}, blockTasks(m.Plan))
+	assert.Equal(t, 1, m.PlanChoice)
+
+	saved, err := plan.Load(m.PlanPath, planDay(m.Config.Goals, time.Now()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"B", "A"}, blockTasks(saved))
+}
+
+func blockTasks(p *plan.Plan) []string {
+	var tasks []string
+	for _, b := range p.Blocks {
+		tasks = append(tasks, b.Task)
+	}
+	return tasks
+}
+
+func TestPlan_WalksThroughQueue(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m,
+		plan.Block{Task: "API refactor", Count: 1},
+		plan.Block{Break: true},
+		plan.Block{Task: "Reviews", Count: 2},
+	)
+	assert.Equal(t, "API refactor", m.activeTaskTitle())
+
+	m = finishSession(t, m)
+	assert.Equal(t, 1, m.Plan.Blocks[0].Done)
+	assert.Equal(t, timer.LongBreak, m.Timer.SessionType, "the planned break is a long one")
+	assert.Equal(t, "", m.activeTaskTitle())
+
+	m = finishSession(t, m)
+	assert.Equal(t, 1, m.Plan.Blocks[1].Done)
+	assert.Equal(t, timer.Work, m.Timer.SessionType)
+	assert.Equal(t, "Reviews", m.activeTaskTitle())
+
+	records, err := m.History.All()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "API refactor", records[0].Task, "the session is recorded under the block it was for")
+
+	saved, err := plan.Load(m.PlanPath, planDay(m.Config.Goals, time.Now()))
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved.Blocks[1].Done)
+}
+
+func TestPlan_SkippedWorkDoesNotCount(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m, plan.Block{Task: "API refactor", Count: 2})
+	m.SessionStart = time.Now().Add(-5 * time.Minute)
+
+	result, _ := m.skipSession()
+	m = result.(Model)
+
+	assert.Equal(t, 0, m.Plan.Blocks[0].Done)
+}
+
+func TestPlan_SkippedBreakMovesOn(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m,
+		plan.Block{Task: "API refactor", Count: 1, Done: 1},
+		plan.Block{Break: true},
+		plan.Block{Task: "Reviews", Count: 1},
+	)
+	m.Timer.CompleteSession()
+	m.followPlan()
+	require.Equal(t, timer.LongBreak, m.Timer.SessionType)
+
+	result, _ := m.skipSession()
+	m = result.(Model)
+
+	assert.True(t, m.Plan.Blocks[1].Finished())
+	assert.Equal(t, "Reviews", m.activeTaskTitle())
+}
+
+func TestPlan_ActiveTaskComesFirst(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m, plan.Block{Task: "API refactor", Count: 1})
+	m.ActiveTask = &task.Task{Title: "Fix login"}
+
+	assert.Equal(t, "Fix login", m.activeTaskTitle())
+}
+
+func TestPlan_TimerViewShowsNext(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m,
+		plan.Block{Task: "API refactor", Count: 3, Done: 1},
+		plan.Block{Break: true},
+	)
+
+	view := m.View()
+
+	assert.Contains(t, view, "▸ API refactor")
+	assert.Contains(t, view, "Block 1 of 2 · 1/3 · Next: Long break")
+}
+
+func TestPlan_CompleteViewShowsPlannedVersusActual(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m,
+		plan.Block{Task: "API refactor", Count: 2, Done: 1},
+		plan.Block{Task: "Reviews", Count: 2, Done: 1},
+	)
+	m.Plan.Blocks[0].Done = 2
+
+	m = finishSession(t, m)
+	view := m.View()
+
+	assert.Contains(t, view, "Planned vs actual")
+	assert.Contains(t, view, "4 of 4 planned pomodoros done")
+	assert.Equal(t, "Day plan done · 4 of 4 planned pomodoros done", m.planStatus(time.Now()))
+}
+
+func TestCheckPlan_FixedStartArrives(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m,
+		plan.Block{Task: "API refactor", Count: 3},
+		plan.Block{Task: "Standup notes", Count: 1},
+	)
+	m.Plan.Blocks[1].Start = "00:00"
+	m.PlanBlock = 0
+
+	cmd := m.checkPlan(time.Now())
+
+	assert.NotNil(t, cmd)
+	assert.Equal(t, 1, m.PlanBlock)
+	assert.Equal(t, "▤ 00:00: time for Standup notes", m.Toast)
+	assert.Nil(t, m.checkPlan(time.Now()), "only once")
+}
+
+func TestCheckPlan_NewDay(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m, plan.Block{Task: "API refactor", Count: 3})
+
+	m.checkPlan(time.Now().AddDate(0, 0, 1))
+
+	assert.Empty(t, m.Plan.Blocks, "yesterday's plan is left behind")
+	assert.Equal(t, -1, m.PlanBlock)
+}
+
+func TestCheckPlan_NewDayShowsUnfinishedPlan(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m, plan.Block{Task: "API refactor", Count: 3})
+	require.NoError(t, m.savePlan())
+
+	m.checkPlan(time.Now().AddDate(0, 0, 1))
+
+	require.NotNil(t, m.PastPlan)
+	assert.Contains(t, m.View(), "Planned vs actual")
+	assert.Contains(t, m.View(), "0 of 3 planned pomodoros done")
+	kept, err := plan.Read(m.PlanPath)
+	require.NoError(t, err)
+	assert.Len(t, kept.Blocks, 1, "kept on disk until seen")
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	assert.Nil(t, m.PastPlan)
+	assert.False(t, m.Timer.Running, "the key only dismisses the summary")
+	kept, err = plan.Read(m.PlanPath)
+	require.NoError(t, err)
+	assert.Empty(t, kept.Blocks)
+}
+
+func TestLoadPlan_KeepsEarlierDay(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := plan.DefaultPath()
+	require.NoError(t, err)
+	yesterday := plan.New(time.Now().AddDate(0, 0, -1))
+	yesterday.Blocks = []plan.Block{{Task: "API refactor", Count: 2}}
+	require.NoError(t, yesterday.Save(path))
+
+	today, past, _, err := loadPlan(planDay(config.GoalConfig{}, time.Now()))
+
+	require.NoError(t, err)
+	assert.Empty(t, today.Blocks)
+	require.NotNil(t, past)
+	assert.Equal(t, yesterday.Date, past.Date)
+}
+
+func TestPlan_PicksUpBlocksAddedOutside(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.PlanPath = filepath.Join(t.TempDir(), "plan.json")
+	m.Plan = todayWith(m, plan.Block{Task: "API refactor", Count: 1})
+	require.NoError(t, m.savePlan())
+	m.PlanWatcher = config.NewWatcher(m.PlanPath)
+
+	// As `pomodoro plan add` would while the app runs
+	outside, err := plan.Load(m.PlanPath, planDay(m.Config.Goals, time.Now()))
+	require.NoError(t, err)
+	outside.Blocks = append(outside.Blocks, plan.Block{Task: "Reviews", Count: 2})
+	require.NoError(t, outside.Save(m.PlanPath))
+
+	result, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = result.(Model)
+	require.Len(t, m.Plan.Blocks, 2)
+
+	m = finishSession(t, m)
+	saved, err := plan.Load(m.PlanPath, planDay(m.Config.Goals, time.Now()))
+	require.NoError(t, err)
+	require.Len(t, saved.Blocks, 2, "saving keeps the block added outside")
+	assert.Equal(t, 1, saved.Blocks[0].Done)
+	assert.False(t, m.PlanWatcher.Changed(), "the app's own save isn't a change")
+}
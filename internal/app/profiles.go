@@ -0,0 +1,133 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/hook"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// noProfile is the picker entry that switches profiles off
+const noProfile = "(none)"
+
+// HookMsg reports the outcome of a hook command
+type HookMsg struct{ Err error }
+
+// openProfilePicker lists the configured profiles
+func (m Model) openProfilePicker() (tea.Model, tea.Cmd) {
+	if len(m.Config.Profiles) == 0 {
+		cmd := m.showToast("No profiles configured")
+		return m, cmd
+	}
+	m.PickingProfile = true
+	m.ProfileChoice = 0
+	for i, name := range m.profileNames() {
+		if name == m.Config.ActiveProfile() {
+			m.ProfileChoice = i
+		}
+	}
+	return m, nil
+}
+
+// handleProfileKey handles keys while the profile picker is open
+func (m Model) handleProfileKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, m.quit()
+
+	case key.Matches(msg, m.Keys.Cancel):
+		m.PickingProfile = false
+		return m, nil
+
+	case key.Matches(msg, m.Keys.Confirm):
+		m.PickingProfile = false
+		name := m.profileNames()[m.ProfileChoice]
+		if err := m.Config.UseProfile(name); err != nil {
+			return m, m.showToast(err.Error())
+		}
+		m.applySettings()
+		if name == "" {
+			return m, m.showToast("Profile off")
+		}
+		return m, m.showToast("Profile: " + name)
+
+	case key.Matches(msg, m.Keys.PickerNext):
+		if m.ProfileChoice < len(m.profileNames())-1 {
+			m.ProfileChoice++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.PickerPrev):
+		if m.ProfileChoice > 0 {
+			m.ProfileChoice--
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// profileNames lists the picker's choices, with "" for no profile first
+func (m Model) profileNames() []string {
+	return append([]string{""}, m.Config.ProfileNames()...)
+}
+
+// profileItems renders the picker's choices
+func (m Model) profileItems() []string {
+	names := m.profileNames()
+	items := make([]string, len(names))
+	for i, name := range names {
+		items[i] = name
+		if name == "" {
+			items[i] = noProfile
+		}
+		if name == m.Config.ActiveProfile() {
+			items[i] += " •"
+		}
+	}
+	return items
+}
+
+// profileSummary describes the highlighted profile's timing
+func (m Model) profileSummary() string {
+	name := m.profileNames()[m.ProfileChoice]
+	if name == "" {
+		return "Settings from the rest of the config"
+	}
+	d := timerDurations(m.Config.Profiles[name].Timer)
+	return fmt.Sprintf("%s work • %s break • long break every %d",
+		d.Work, d.ShortBreak, d.Cycle)
+}
+
+// runHook starts the configured hook for the current session
+func (m Model) runHook(event string, completed bool) tea.Cmd {
+	command := m.Config.Hooks.SessionStart
+	if event == hook.SessionEnd {
+		command = m.Config.Hooks.SessionEnd
+	}
+	env := m.hookEnv(event, m.Timer.SessionType)
+	env.Completed = completed
+	return tea.Batch(hookCmd(command, env), m.sendWebhooks(env))
+}
+
+// hookEnv describes a session of the given type to a hook
+func (m Model) hookEnv(event string, session timer.SessionType) hook.Env {
+	return hook.Env{
+		Event:   event,
+		Session: string(session),
+		Profile: m.Config.ActiveProfile(),
+		Project: m.Project,
+		Task:    m.activeTaskTitle(),
+	}
+}
+
+// hookCmd runs a hook command in the background
+func hookCmd(command string, env hook.Env) tea.Cmd {
+	if command == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		return HookMsg{Err: hook.Run(command, env)}
+	}
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// profilesConfig is a config file with two profiles
+const profilesConfig = `[profiles.coding.timer]
+work = "50m"
+short_break = "10m"
+
+[profiles.study]
+theme = "mono"
+
+[profiles.study.timer]
+work = "45m"
+cycle = 2
+`
+
+func pressRune(t *testing.T, m Model, r rune) Model {
+	t.Helper()
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	return result.(Model)
+}
+
+func TestProfilePicker_NoProfiles(t *testing.T) {
+	m := newTestModel()
+	m.CurrentView = ViewTimer
+
+	m = pressRune(t, m, 'p')
+
+	assert.False(t, m.PickingProfile)
+	assert.Equal(t, "No profiles configured", m.Toast)
+}
+
+func TestProfilePicker_Switches(t *testing.T) {
+	defer ui.ApplyTheme("neon")
+	m, _ := newTestModelWithConfigFile(t, profilesConfig)
+
+	m = pressRune(t, m, 'p')
+	require.True(t, m.PickingProfile)
+	assert.Contains(t, m.View(), "(none) •")
+
+	// (none), coding, study
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	result, _ = result.(Model).Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Contains(t, result.(Model).View(), "45m0s work")
+	result, _ = result.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+
+	assert.False(t, m.PickingProfile)
+	assert.Equal(t, "study", m.Config.ActiveProfile())
+	assert.Equal(t, 45*time.Minute, m.Timer.Duration, "idle session picks up the profile")
+	assert.Equal(t, 2, m.Timer.Cycle())
+	assert.Equal(t, ui.Themes["mono"].Work, ui.WorkColor)
+	assert.Equal(t, "Profile: study", m.Toast)
+	assert.Contains(t, m.View(), "◆ study")
+}
+
+func TestProfilePicker_Cancel(t *testing.T) {
+	m, _ := newTestModelWithConfigFile(t, profilesConfig)
+
+	m = pressRune(t, m, 'p')
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(Model)
+
+	assert.False(t, m.PickingProfile)
+	assert.Empty(t, m.Config.ActiveProfile())
+}
+
+func TestRecordSession_Profile(t *testing.T) {
+	m, _ := newTestModelWithConfigFile(t, profilesConfig)
+	m.History = history.NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	require.NoError(t, m.Config.UseProfile("coding"))
+	m.SessionStart = time.Now().Add(-time.Minute)
+
+	m.recordSession(true)
+
+	records, err := m.History.All()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "coding", records[0].Profile)
+}
+
+func TestReloadConfig_KeepsPickedProfile(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, profilesConfig)
+	require.NoError(t, m.Config.UseProfile("coding"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content = []byte(string(content) + "\n[goals]\ndaily_pomodoros = 3\n")
+	require.NoError(t, os.WriteFile(path, content, 0644))
+
+	result, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = result.(Model)
+
+	assert.Equal(t, 3, m.Config.Goals.DailyPomodoros)
+	assert.Equal(t, "coding", m.Config.ActiveProfile())
+	assert.Equal(t, 50*time.Minute, m.Config.Timer.Work)
+}
+
+func TestHooks_RunOnStartAndEnd(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "hooks.log")
+	m, _ := newTestModelWithConfigFile(t, `[hooks]
+session_start = "echo start $POMODORO_SESSION >> `+log+`"
+session_end = "echo end $POMODORO_SESSION $POMODORO_COMPLETED >> `+log+`"
+`)
+
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	require.NotNil(t, findHookMsg(cmd))
+
+	result, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	require.NotNil(t, findHookMsg(cmd))
+
+	content, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "start work\nend work false\n", string(content))
+}
+
+// findHookMsg runs cmd and the commands it batches, returning the first hook result
+func findHookMsg(cmd tea.Cmd) *HookMsg {
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case HookMsg:
+		return &msg
+	case tea.BatchMsg:
+		var found *HookMsg
+		for _, c := range msg {
+			if m := findHookMsg(c); m != nil && found == nil {
+				found = m
+			}
+		}
+		return found
+	}
+	return nil
+}
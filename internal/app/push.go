@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/push"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// pushTimeout bounds a delivery with all its retries
+const pushTimeout = 10 * time.Minute
+
+// PushMsg reports the end of a push notification delivery
+type PushMsg struct {
+	Err error
+}
+
+// push sends the alert for a finished session to the push server in the
+// background, retrying without holding up the UI
+func (m *Model) push(completed timer.SessionType, title, message string) tea.Cmd {
+	cfg := m.Config.Push
+	if !cfg.Enabled || m.Notifier.Muted("push") {
+		return nil
+	}
+	sender, err := push.FromConfig(cfg)
+	if err != nil {
+		return m.showToast(err.Error())
+	}
+
+	template := cfg.Work
+	switch completed {
+	case timer.ShortBreak:
+		template = cfg.ShortBreak
+	case timer.LongBreak:
+		template = cfg.LongBreak
+	}
+	msg := push.Message{
+		Title: title,
+		Body: push.Expand(template, map[string]string{
+			"title":   title,
+			"message": message,
+			"session": sessionNames[completed],
+			"task":    m.activeTaskTitle(),
+			"project": m.Project,
+		}),
+		Priority: cfg.Priority,
+	}
+	retries, backoff := cfg.Retries, cfg.Backoff
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+		defer cancel()
+		return PushMsg{Err: push.Deliver(ctx, sender, msg, retries, backoff)}
+	}
+}
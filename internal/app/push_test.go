@@ -0,0 +1,66 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPush_SendsSessionTemplate(t *testing.T) {
+	bodies := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies <- string(data)
+	}))
+	defer srv.Close()
+
+	m := newTestModel()
+	m.Project = "api"
+	m.Config.Push.Enabled = true
+	m.Config.Push.URL = srv.URL
+	m.Config.Push.Topic = "focus"
+	m.Config.Push.ShortBreak = "{session} over on {project}: {message}"
+
+	cmd := m.push(timer.ShortBreak, "Break Over!", "Ready to focus again?")
+	require.NotNil(t, cmd)
+	assert.Equal(t, PushMsg{}, cmd())
+	assert.Equal(t, "Short break over on api: Ready to focus again?", <-bodies)
+}
+
+func TestPush_FailureShowsToast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	m := newTestModel()
+	m.Config.Push = pushConfig(srv.URL)
+	m.Config.Push.Retries = 1
+	m.Config.Push.Backoff = time.Millisecond
+
+	msg := m.push(timer.Work, "Work Session Complete!", "Time for a break.")()
+	result, _ := m.Update(msg)
+
+	assert.Equal(t, "Push failed: ntfy: 503 down", result.(Model).Toast)
+}
+
+func TestPush_DisabledOrMuted(t *testing.T) {
+	m := newTestModel()
+	assert.Nil(t, m.push(timer.Work, "t", "m"), "off by default")
+
+	m.Config.Push = pushConfig("http://127.0.0.1:0")
+	m.Notifier.ToggleDoNotDisturb()
+	assert.Nil(t, m.push(timer.Work, "t", "m"), "muted by do not disturb")
+}
+
+// pushConfig enables ntfy pushes to url
+func pushConfig(url string) config.PushConfig {
+	return config.PushConfig{Enabled: true, Service: "ntfy", URL: url, Topic: "focus"}
+}
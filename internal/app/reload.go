@@ -0,0 +1,190 @@
+package app
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/task"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+)
+
+// reloadConfig applies the config file if it changed on disk
+// A file with errors leaves the running configuration untouched
+func (m Model) reloadConfig() (tea.Model, tea.Cmd) {
+	if m.ConfigWatcher == nil || !m.ConfigWatcher.Changed() {
+		return m, configTick()
+	}
+
+	path, err := config.Path()
+	if err != nil {
+		return m, configTick()
+	}
+	before := *m.Config
+	cfg, err := config.LoadFile(path)
+	if cfg != nil {
+		if applyErr := m.applyConfig(cfg); applyErr != nil {
+			err = applyErr
+			cfg = nil
+		}
+	}
+
+	m.ConfigWarning = ""
+	if err != nil {
+		m.ConfigWarning = configWarning(err)
+	}
+	if cfg == nil {
+		return m, configTick()
+	}
+	reconnected, restart := m.reconnect(&before)
+	toast := "Config reloaded"
+	if len(restart) > 0 {
+		toast += ", restart to apply " + strings.Join(restart, " and ")
+	}
+	return m, tea.Batch(configTick(), reconnected, m.showToast(toast))
+}
+
+// reconnect rebuilds the chat status syncer and the metrics server when
+// their sections changed, and names the changed sections that only apply
+// on restart
+func (m *Model) reconnect(before *config.Config) (tea.Cmd, []string) {
+	var cmds []tea.Cmd
+	if before.Status != m.Config.Status {
+		cleared := m.clearStatus()
+		syncer, err := newStatusSyncer(m.Config.Status)
+		if err != nil {
+			m.setWarning("status", err.Error())
+		} else {
+			m.setWarning("status", "")
+		}
+		m.Status = syncer
+		if synced := m.syncStatus(); synced != nil {
+			cleared = tea.Sequence(cleared, synced)
+		}
+		cmds = append(cmds, cleared)
+	}
+	if before.Metrics != m.Config.Metrics {
+		if m.MetricsServer != nil {
+			_ = m.MetricsServer.Close()
+		}
+		stats, srv, err := startMetrics(m.Config.Metrics)
+		if err != nil {
+			m.setWarning("metrics", "Metrics: "+err.Error())
+		} else {
+			m.setWarning("metrics", "")
+		}
+		m.Metrics, m.MetricsServer = stats, srv
+		m.updateMetrics()
+	}
+
+	var restart []string
+	if !reflect.DeepEqual(before.MQTT, m.Config.MQTT) {
+		restart = append(restart, "[mqtt]")
+	}
+	if before.DBus != m.Config.DBus {
+		restart = append(restart, "[dbus]")
+	}
+	return tea.Batch(cmds...), restart
+}
+
+// configWarning turns a config loading error into banner text
+func configWarning(err error) string {
+	var fileErr *config.FileError
+	if errors.As(err, &fileErr) {
+		short := *fileErr
+		short.Path = filepath.Base(short.Path)
+		msg := short.Error()
+		if !fileErr.Applied() {
+			msg += " — file not applied"
+		}
+		return msg
+	}
+	return "Config not applied: " + err.Error()
+}
+
+// setWarning shows why the named integration couldn't be set up, or with
+// an empty message takes that away, leaving the other warnings be
+func (m *Model) setWarning(name, msg string) {
+	if msg == "" {
+		delete(m.Warnings, name)
+		return
+	}
+	if m.Warnings == nil {
+		m.Warnings = map[string]string{}
+	}
+	m.Warnings[name] = msg
+}
+
+// warning returns the banner text: the config file's problems, then the
+// integrations' in a stable order
+func (m Model) warning() string {
+	var parts []string
+	if m.ConfigWarning != "" {
+		parts = append(parts, m.ConfigWarning)
+	}
+	names := slices.Sorted(maps.Keys(m.Warnings))
+	for _, name := range names {
+		parts = append(parts, m.Warnings[name])
+	}
+	return strings.Join(parts, " · ")
+}
+
+// applyConfig switches the running app over to cfg, keeping the profile
+// picked while running
+// The config is copied into the existing one so the notifier sees it too
+func (m *Model) applyConfig(cfg *config.Config) error {
+	next := *cfg
+	if active := m.Config.ActiveProfile(); active != next.ActiveProfile() {
+		// A profile removed from the file falls back to the launch profile
+		_ = next.UseProfile(active)
+	}
+	if _, ok := ui.Themes[next.UI.Theme]; next.UI.Theme != "" && !ok {
+		return ui.ApplyTheme(next.UI.Theme)
+	}
+
+	*m.Config = next
+	m.applySettings()
+	return nil
+}
+
+// applySettings brings the theme, keys, timer, sounds, task sources,
+// calendar and goals in line with the current config
+// The calendar files are read on the next refresh
+func (m *Model) applySettings() {
+	_ = ui.ApplyTheme(m.Config.UI.Theme)
+	m.Keys = KeyMapFromConfig(m.Config.Keys)
+	m.Timer.SetDurations(timerDurations(m.Config.Timer))
+	m.Notifier.ReloadSounds()
+	_ = m.Notifier.SetTicking(m.working())
+	if dir, err := os.Getwd(); err == nil {
+		m.TaskSources = task.FromConfig(m.Config.Tasks, dir)
+		m.Calendar = newCalendar(m.Config.Calendar, dir)
+	}
+	m.refreshGoals()
+}
+
+// timerDurations converts the timer settings, keeping the standard
+// timing for anything left unset
+func timerDurations(cfg config.TimerConfig) timer.Durations {
+	d := timer.DefaultDurations()
+	if cfg.Work > 0 {
+		d.Work = cfg.Work
+	}
+	if cfg.ShortBreak > 0 {
+		d.ShortBreak = cfg.ShortBreak
+	}
+	if cfg.LongBreak > 0 {
+		d.LongBreak = cfg.LongBreak
+	}
+	if cfg.Cycle > 0 {
+		d.Cycle = cfg.Cycle
+	}
+	return d
+}
@@ -0,0 +1,213 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/notify"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestModelWithConfigFile(t *testing.T, content string) (Model, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	config.SetPath(path)
+	t.Cleanup(func() { config.SetPath("") })
+
+	cfg, err := config.LoadFile(path)
+	require.NoError(t, err)
+	m := newTestModel()
+	m.Config = cfg
+	m.Notifier = notify.New(cfg)
+	m.CurrentView = ViewTimer
+	m.ConfigWatcher = config.NewWatcher(path)
+	return m, path
+}
+
+func TestReloadConfig_AppliesChanges(t *testing.T) {
+	defer ui.ApplyTheme("neon")
+	m, path := newTestModelWithConfigFile(t, "[notifications]\nterminal_bell = true\n")
+	notifier := m.Notifier
+
+	content := `[notifications]
+terminal_bell = false
+
+[timer]
+work = "50m"
+
+[ui]
+theme = "solarized"
+
+[keys]
+skip = ["k"]
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	updated, cmd := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+
+	assert.NotNil(t, cmd)
+	assert.Equal(t, "Config reloaded", m.Toast)
+	assert.False(t, m.Config.Notifications.TerminalBell)
+	assert.Same(t, notifier, m.Notifier)
+	assert.Equal(t, 50*time.Minute, m.Timer.Remaining, "idle session should pick up the new length")
+	assert.Equal(t, []string{"k"}, m.Keys.Skip.Keys())
+	assert.Equal(t, ui.Themes["solarized"].Work, ui.WorkColor)
+}
+
+func TestReloadConfig_InvalidFileKeepsConfig(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, "[goals]\ndaily_pomodoros = 8\n")
+	m.Config.Goals.DailyPomodoros = 8
+
+	require.NoError(t, os.WriteFile(path, []byte("[goals\ndaily_pomodoros = 12\n"), 0644))
+
+	updated, cmd := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+
+	assert.NotNil(t, cmd)
+	assert.Empty(t, m.Toast)
+	assert.Contains(t, m.ConfigWarning, "config.toml:1:")
+	assert.Contains(t, m.ConfigWarning, "not applied")
+	assert.Equal(t, 8, m.Config.Goals.DailyPomodoros)
+}
+
+func TestReloadConfig_InvalidEditIsNotSavedOver(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, "[goals]\ndaily_pomodoros = 8\n")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	m.Config = cfg
+
+	broken := []byte("[timer]\nshort_break = \"7m\"\n\n[goals]\ndaily_pomodoros = 10\nweek_start = \"someday\"\n")
+	require.NoError(t, os.WriteFile(path, broken, 0644))
+	updated, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+	require.Contains(t, m.ConfigWarning, "not applied")
+
+	assert.Error(t, m.Config.Save())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, broken, content)
+}
+
+func TestReloadConfig_UnknownThemeKeepsConfig(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, "")
+
+	require.NoError(t, os.WriteFile(path, []byte("[ui]\ntheme = \"vaporwave\"\n[timer]\nwork = \"50m\"\n"), 0644))
+
+	updated, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+
+	assert.Contains(t, m.ConfigWarning, "unknown theme")
+	assert.Equal(t, 25*time.Minute, m.Timer.Duration)
+}
+
+func TestReloadConfig_RunningSessionKeepsLength(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, "")
+	m.Timer.Start()
+	m.Timer.Tick()
+
+	require.NoError(t, os.WriteFile(path, []byte("[timer]\nwork = \"50m\"\nshort_break = \"10m\"\n"), 0644))
+
+	updated, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+
+	assert.Equal(t, 25*time.Minute, m.Timer.Duration)
+	m.Timer.CompleteSession()
+	assert.Equal(t, 10*time.Minute, m.Timer.Duration)
+}
+
+func TestReloadConfig_Unchanged(t *testing.T) {
+	m, _ := newTestModelWithConfigFile(t, "")
+
+	updated, cmd := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+
+	assert.NotNil(t, cmd, "checking should continue")
+	assert.Empty(t, m.Toast)
+}
+
+func TestReloadConfig_UnknownKeyStillApplies(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, "")
+
+	require.NoError(t, os.WriteFile(path, []byte("[timer]\nwork = \"40m\"\nwrok = \"50m\"\n"), 0644))
+
+	updated, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+
+	assert.Equal(t, 40*time.Minute, m.Timer.Duration)
+	assert.Equal(t, "config.toml:3:1: warning: timer.wrok: unknown key", m.ConfigWarning)
+}
+
+func TestReloadConfig_FixedFileClearsWarning(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, "")
+	m.ConfigWarning = "config.toml:1:1: broken"
+
+	require.NoError(t, os.WriteFile(path, []byte("[timer]\nwork = \"40m\"\n"), 0644))
+
+	updated, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+
+	assert.Empty(t, m.ConfigWarning)
+	assert.Equal(t, "Config reloaded", m.Toast)
+}
+
+func TestReconnect_RebuildsStatusAndMetrics(t *testing.T) {
+	syncer, rec := slackSyncer(t)
+	m := newTestModel()
+	m.Status = syncer
+	m.Config.Status.DND = false
+	m.Config.Status.Service = "slack"
+	m.Timer.Start()
+	require.Equal(t, StatusMsg{}, m.syncStatus()())
+	before := *m.Config
+
+	m.Config.Status = config.StatusConfig{}
+	m.Config.Metrics.Listen = "127.0.0.1:0"
+	cmd, restart := m.reconnect(&before)
+	t.Cleanup(func() { _ = m.MetricsServer.Close() })
+
+	assert.Empty(t, restart)
+	assert.Nil(t, m.Status, "status sync is off in the new config")
+	assert.NotNil(t, m.MetricsServer, "the metrics server is started")
+	require.NotNil(t, cmd)
+	assert.Equal(t, StatusMsg{}, cmd())
+	require.Len(t, rec.calls, 2)
+	assert.Contains(t, rec.calls[1], `"status_text":""`, "the old status is cleared")
+}
+
+func TestReloadConfig_NamesSectionsNeedingRestart(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, "")
+
+	require.NoError(t, os.WriteFile(path, []byte("[mqtt]\nbroker = \"tcp://localhost:1883\"\n\n[dbus]\nenabled = true\n"), 0644))
+	updated, _ := m.Update(ConfigCheckMsg(time.Now()))
+
+	assert.Equal(t, "Config reloaded, restart to apply [mqtt] and [dbus]", updated.(Model).Toast)
+}
+
+func TestReloadConfig_KeepsIntegrationWarnings(t *testing.T) {
+	m, path := newTestModelWithConfigFile(t, "[goals]\ndaily_pomodoros = 8\n")
+	m.setWarning("plan", "Plan: plan.json: unexpected end of JSON input")
+	m.setWarning("status", "status: no slack token in credentials.toml")
+	m.Config.Status.Service = "slack"
+
+	require.NoError(t, os.WriteFile(path, []byte("[goals]\ndaily_pomodoros = 9\n"), 0644))
+	updated, _ := m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+
+	assert.Equal(t, 9, m.Config.Goals.DailyPomodoros)
+	assert.Equal(t, "Plan: plan.json: unexpected end of JSON input", m.warning(),
+		"the plan warning stays and turning status sync off clears its warning")
+
+	require.NoError(t, os.WriteFile(path, []byte("[goals\n"), 0644))
+	updated, _ = m.Update(ConfigCheckMsg(time.Now()))
+	m = updated.(Model)
+	assert.Contains(t, m.warning(), "not applied · Plan: ")
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/hook"
+	"github.com/kanishkathakur1/pomodoro/internal/notify"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// ReminderMsg asks for the next reminder of an unacknowledged session
+type ReminderMsg struct{ ID int }
+
+// Reminder urgency levels, each adding a channel to the one before
+const (
+	remindBell   = 1
+	remindSystem = 2
+	remindHook   = 3
+)
+
+// awaitAcknowledgement starts the reminder schedule for a finished session
+func (m *Model) awaitAcknowledgement() tea.Cmd {
+	m.CompletedAt = time.Now()
+	m.Reminders = 0
+	m.ReminderID++
+	return m.reminderTick()
+}
+
+// reminderTick schedules the next reminder, if any are left
+func (m Model) reminderTick() tea.Cmd {
+	cfg := m.Config.Reminders
+	if cfg.Interval <= 0 || m.Reminders >= cfg.Max {
+		return nil
+	}
+	id := m.ReminderID
+	return tea.Tick(cfg.Interval, func(time.Time) tea.Msg {
+		return ReminderMsg{ID: id}
+	})
+}
+
+// remind repeats the completion notification more urgently each time
+func (m Model) remind(msg ReminderMsg) (tea.Model, tea.Cmd) {
+	if msg.ID != m.ReminderID || m.CompletedAt.IsZero() {
+		return m, nil
+	}
+	m.Reminders++
+	level := min(m.Reminders, remindHook)
+	completed := getCompletedSession(m.Timer)
+
+	title, message := completionMessage(completed)
+	waited := time.Since(m.CompletedAt).Round(time.Minute)
+	message = fmt.Sprintf("%s (waiting %s)", message, shortDuration(waited))
+	_ = m.Notifier.Remind(level, title, message)
+
+	event := notify.BreakEnd
+	if completed == timer.Work {
+		event = notify.WorkEnd
+	}
+	env := m.hookEnv(hook.Reminder, completed)
+	env.Completed = true
+	env.Reminder = m.Reminders
+	cmds := []tea.Cmd{m.playSound(event), m.reminderTick(), m.sendWebhooks(env)}
+	if level >= remindHook {
+		cmds = append(cmds, hookCmd(m.Config.Hooks.Reminder, env))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// acknowledge stops the reminders and notes how long the finished session
+// waited in its history record
+func (m *Model) acknowledge() {
+	if m.CompletedAt.IsZero() {
+		return
+	}
+	waited := time.Since(m.CompletedAt).Round(time.Second)
+	m.CompletedAt = time.Time{}
+	m.ReminderID++
+	if m.History == nil || m.LastRecordID == "" || waited <= 0 {
+		return
+	}
+	_ = m.History.Acknowledge(m.LastRecordID, waited)
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminders_Escalate(t *testing.T) {
+	var bells, systems []string
+	notify.SetNotifyFuncsForTesting(func(title, message string, _ any) error {
+		systems = append(systems, message)
+		return nil
+	}, func() { bells = append(bells, "bell") })
+	defer notify.ResetNotifyFuncsForTesting()
+
+	log := filepath.Join(t.TempDir(), "reminders.log")
+	m := newTestModelWithHistory(t)
+	m.Config.Reminders = config.ReminderConfig{Interval: 2 * time.Minute, Max: 3}
+	m.Config.Hooks.Reminder = "echo $POMODORO_EVENT $POMODORO_SESSION $POMODORO_REMINDER >> " + log
+	m = finishSession(t, m)
+	bells, systems = nil, nil
+
+	remind := func() tea.Cmd {
+		result, cmd := m.Update(ReminderMsg{ID: m.ReminderID})
+		m = result.(Model)
+		return cmd
+	}
+
+	remind()
+	assert.Len(t, bells, 1)
+	assert.Empty(t, systems, "the first reminder only rings the bell")
+
+	m.CompletedAt = m.CompletedAt.Add(-4 * time.Minute)
+	remind()
+	assert.Len(t, bells, 2)
+	require.Len(t, systems, 1)
+	assert.Equal(t, "Time for a break. (waiting 4m)", systems[0])
+
+	cmd := remind()
+	assert.Len(t, systems, 2)
+	require.NotNil(t, findHookMsg(cmd), "the third reminder runs the hook")
+	content, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "reminder work 3\n", string(content))
+
+	assert.Nil(t, m.reminderTick(), "no more than reminders.max")
+}
+
+func TestReminders_StopOnKeyPress(t *testing.T) {
+	bells := 0
+	notify.SetNotifyFuncsForTesting(func(string, string, any) error { return nil }, func() { bells++ })
+	defer notify.ResetNotifyFuncsForTesting()
+
+	m := newTestModelWithHistory(t)
+	m.Config.Reminders = config.ReminderConfig{Interval: 2 * time.Minute, Max: 3}
+	m = finishSession(t, m)
+	pending := ReminderMsg{ID: m.ReminderID}
+	m.CompletedAt = m.CompletedAt.Add(-5 * time.Minute)
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	bells = 0
+	result, cmd := m.Update(pending)
+	m = result.(Model)
+
+	assert.Equal(t, ViewTimer, m.CurrentView, "the key still does its job")
+	assert.Zero(t, bells, "acknowledged sessions aren't reminded")
+	assert.Nil(t, cmd)
+	assert.Zero(t, m.Reminders)
+
+	records, err := m.History.All()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.InDelta(t, 5*time.Minute, records[0].Unacknowledged, float64(time.Second))
+}
+
+func TestReminders_Disabled(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	assert.Nil(t, m.awaitAcknowledgement(), "reminders are off by default")
+
+	m.Config.Reminders = config.ReminderConfig{Interval: 0, Max: 3}
+	assert.Nil(t, m.awaitAcknowledgement())
+}
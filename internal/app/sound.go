@@ -0,0 +1,40 @@
+package app
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/notify"
+	"github.com/kanishkathakur1/pomodoro/internal/sound"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// playSound plays the sound for event, showing a toast if it can't
+func (m *Model) playSound(event notify.Event) tea.Cmd {
+	return m.soundFailed(m.Notifier.PlaySound(event))
+}
+
+// syncTicking ticks while a work session runs in the timer view
+func (m *Model) syncTicking() tea.Cmd {
+	return m.soundFailed(m.Notifier.SetTicking(m.working()))
+}
+
+// soundFailed shows why a sound couldn't be played
+// A build without audio is only pointed out once, not for every session
+func (m *Model) soundFailed(err error) tea.Cmd {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sound.ErrNoDevice) {
+		if m.NoAudioShown {
+			return nil
+		}
+		m.NoAudioShown = true
+	}
+	return m.showToast(err.Error())
+}
+
+// working returns whether a work session is counting down on screen
+func (m Model) working() bool {
+	return m.CurrentView == ViewTimer && m.Timer.Running && m.Timer.SessionType == timer.Work
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/notify"
+	"github.com/kanishkathakur1/pomodoro/internal/sound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordSounds sends the sounds of notifiers made from now on to a
+// recorder
+func recordSounds(t *testing.T) *sound.Recorder {
+	t.Helper()
+	out := &sound.Recorder{}
+	notify.SetSoundOutputForTesting(out)
+	notify.SetNotifyFuncsForTesting(func(string, string, any) error { return nil }, func() {})
+	t.Cleanup(notify.ResetNotifyFuncsForTesting)
+	return out
+}
+
+func TestSound_TicksWhileWorking(t *testing.T) {
+	out := recordSounds(t)
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Config.Sound.Enabled = true
+	m.Config.Sound.Ticking = true
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	require.Equal(t, []sound.Played{{Name: "tick", Volume: 0.7, Loop: true}}, out.Played())
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	assert.True(t, out.Played()[0].Stopped, "pausing stops the ticking")
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	assert.True(t, out.Played()[1].Stopped, "skipping stops the ticking")
+}
+
+func TestSound_WarningAndWorkEnd(t *testing.T) {
+	out := recordSounds(t)
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Config.Sound.Enabled = true
+	m.Config.Warnings.Work = time.Minute
+	m.Timer.Start()
+	m.Timer.Remaining = time.Minute + time.Second
+
+	for i := 0; i < 61; i++ {
+		result, _ := m.Update(TickMsg(time.Now()))
+		m = result.(Model)
+	}
+
+	assert.Equal(t, []string{"ding", "chime"}, out.Names(), "the warning plays once")
+	assert.Equal(t, ViewComplete, m.CurrentView)
+}
+
+func TestSound_BreakEnd(t *testing.T) {
+	out := recordSounds(t)
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Config.Sound.Enabled = true
+	m.Timer.CompleteSession()
+	m.Timer.Start()
+	m.Timer.Remaining = time.Second
+
+	result, _ := m.Update(TickMsg(time.Now()))
+	m = result.(Model)
+
+	assert.Equal(t, []string{"bell"}, out.Names(), "no warning for breaks")
+}
+
+func TestSound_ErrorShowsToast(t *testing.T) {
+	recordSounds(t)
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Config.Sound.Enabled = true
+	m.Config.Sound.Ticking = true
+	m.Config.Sound.Tick = "/no/such/tick.wav"
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+
+	assert.Contains(t, m.Toast, "/no/such/tick.wav")
+	assert.True(t, m.Timer.Running)
+}
+
+// noAudio is the output of a build without audio support
+type noAudio struct{}
+
+func (noAudio) Play(*sound.Clip, float64, bool) (sound.Voice, error) {
+	return nil, sound.ErrNoDevice
+}
+
+func TestSound_NoAudioToastShownOnce(t *testing.T) {
+	recordSounds(t)
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Config.Sound.Enabled = true
+	m.Config.Sound.Ticking = true
+	notify.SetSoundOutputForTesting(noAudio{})
+	m.Notifier = notify.New(m.Config)
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	assert.Equal(t, sound.ErrNoDevice.Error(), m.Toast)
+
+	m.Toast = ""
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = result.(Model)
+	assert.Empty(t, m.Toast, "the missing audio is only pointed out once")
+	assert.True(t, m.Timer.Running)
+}
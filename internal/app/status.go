@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/status"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// statusTimeout bounds one round of chat status updates
+const statusTimeout = 15 * time.Second
+
+// StatusMsg reports the end of a chat status update
+type StatusMsg struct {
+	Err error
+}
+
+// newStatusSyncer creates the chat status syncer from the config and the
+// credentials file, nil when status sync is off
+func newStatusSyncer(cfg config.StatusConfig) (*status.Syncer, error) {
+	if cfg.Service == "" {
+		return nil, nil
+	}
+	creds, err := config.LoadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	svc, err := status.FromConfig(cfg, creds)
+	if err != nil {
+		return nil, err
+	}
+	return status.NewSyncer(svc), nil
+}
+
+// syncStatus shows the focus status while a work session runs and clears
+// it on breaks, pauses, skips and resets
+func (m *Model) syncStatus() tea.Cmd {
+	if m.Status == nil {
+		return nil
+	}
+	if m.Timer.Running && m.Timer.SessionType == timer.Work {
+		cfg := m.Config.Status
+		end := time.Now().Add(m.Timer.Remaining).Truncate(time.Second)
+		m.Status.Focus(status.StatusText(cfg.Text, end, m.activeTaskTitle()), cfg.Emoji, end, cfg.DND)
+	} else {
+		m.Status.Clear()
+	}
+	return applyStatus(m.Status)
+}
+
+// clearStatus removes the focus status, for quitting
+func (m *Model) clearStatus() tea.Cmd {
+	if m.Status == nil || !m.Status.Focused() {
+		return nil
+	}
+	m.Status.Clear()
+	return applyStatus(m.Status)
+}
+
+// applyStatus pushes the wanted status to the chat server in the background
+func applyStatus(s *status.Syncer) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+		defer cancel()
+		return StatusMsg{Err: s.Apply(ctx)}
+	}
+}
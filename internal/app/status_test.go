@@ -0,0 +1,105 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slackRecorder is a mock Slack Web API that records method and body
+type slackRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (s *slackRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.calls = append(s.calls, strings.TrimPrefix(r.URL.Path, "/")+" "+string(body))
+	s.mu.Unlock()
+	_, _ = w.Write([]byte(`{"ok":true}`))
+}
+
+// slackSyncer returns a status syncer talking to a mock Slack
+func slackSyncer(t *testing.T) (*status.Syncer, *slackRecorder) {
+	t.Helper()
+	rec := &slackRecorder{}
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+	return status.NewSyncer(status.NewSlack(srv.URL, "xoxp-test")), rec
+}
+
+func TestSyncStatus_WorkThenPause(t *testing.T) {
+	syncer, rec := slackSyncer(t)
+	m := newTestModel()
+	m.Status = syncer
+	m.Config.Status.DND = false
+
+	m.Timer.Start()
+	require.Equal(t, StatusMsg{}, m.syncStatus()())
+	m.Timer.Pause()
+	require.Equal(t, StatusMsg{}, m.syncStatus()())
+
+	require.Len(t, rec.calls, 2)
+	assert.Contains(t, rec.calls[0], `"status_text":"Focusing until `)
+	assert.Contains(t, rec.calls[0], `"status_emoji":":tomato:"`)
+	assert.Contains(t, rec.calls[1], `"status_text":""`)
+}
+
+func TestSyncStatus_Skip(t *testing.T) {
+	syncer, rec := slackSyncer(t)
+	m := newTestModel()
+	m.Status = syncer
+	m.Config.Status.DND = false
+	m.Timer.Start()
+	require.Equal(t, StatusMsg{}, m.syncStatus()())
+
+	m.Timer.Skip()
+	require.Equal(t, StatusMsg{}, m.syncStatus()())
+
+	assert.Len(t, rec.calls, 2, "breaks clear the status")
+}
+
+func TestClearStatus_OnlyWhenFocused(t *testing.T) {
+	syncer, rec := slackSyncer(t)
+	m := newTestModel()
+	m.Status = syncer
+	m.Config.Status.DND = false
+	assert.Nil(t, m.clearStatus())
+
+	m.Timer.Start()
+	require.Equal(t, StatusMsg{}, m.syncStatus()())
+	cmd := m.clearStatus()
+	require.NotNil(t, cmd)
+	require.Equal(t, StatusMsg{}, cmd())
+
+	assert.Len(t, rec.calls, 2)
+}
+
+func TestNewStatusSyncer_ReadsCredentials(t *testing.T) {
+	dir := t.TempDir()
+	config.SetPath(filepath.Join(dir, "config.toml"))
+	defer config.SetPath("")
+
+	_, err := newStatusSyncer(config.StatusConfig{Service: "slack"})
+	assert.EqualError(t, err, "status: no slack token in credentials.toml")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "credentials.toml"), []byte("[slack]\ntoken = \"xoxp-1\"\n"), 0600))
+	syncer, err := newStatusSyncer(config.StatusConfig{Service: "slack"})
+	require.NoError(t, err)
+	assert.NotNil(t, syncer)
+
+	syncer, err = newStatusSyncer(config.StatusConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, syncer)
+}
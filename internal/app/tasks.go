@@ -0,0 +1,273 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/task"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+)
+
+// ToastEndMsg hides the toast with the given ID
+type ToastEndMsg struct{ ID int }
+
+// showToast displays a short status message in the timer view
+func (m *Model) showToast(message string) tea.Cmd {
+	m.ToastID++
+	m.Toast = message
+	id := m.ToastID
+	return tea.Tick(4*time.Second, func(time.Time) tea.Msg {
+		return ToastEndMsg{ID: id}
+	})
+}
+
+// TaskListMsg delivers the pending tasks read for the task picker
+type TaskListMsg struct {
+	Tasks []task.Task
+	Err   error
+}
+
+// openTaskPicker lists pending tasks from every source in the background,
+// since a source such as Taskwarrior runs a command; the picker opens once
+// they are read
+func (m Model) openTaskPicker() (tea.Model, tea.Cmd) {
+	if len(m.TaskSources) == 0 {
+		cmd := m.showToast("No task sources configured")
+		return m, cmd
+	}
+
+	sources := m.TaskSources
+	return m, func() tea.Msg {
+		tasks, err := task.ListAll(sources)
+		return TaskListMsg{Tasks: tasks, Err: err}
+	}
+}
+
+// showTaskPicker opens the picker on the tasks that were read, unless the
+// timer view was left or another prompt opened meanwhile
+func (m Model) showTaskPicker(msg TaskListMsg) (tea.Model, tea.Cmd) {
+	if m.CurrentView != ViewTimer || m.Reflecting || m.Labeling || m.PickingTask || m.PickingProfile || m.Planning {
+		return m, nil
+	}
+
+	tasks := msg.Tasks
+	var cmds []tea.Cmd
+	if msg.Err != nil {
+		cmds = append(cmds, m.showToast("Some tasks couldn't be read: "+msg.Err.Error()))
+	}
+
+	input := textinput.New()
+	input.Placeholder = "filter tasks"
+	input.CharLimit = 120
+	input.Width = 40
+	input.Focus()
+
+	m.TaskInput = input
+	m.TaskChoices = tasks
+	m.TaskChoice = 0
+	m.TaskMatches = ui.FuzzyIndices("", taskTitles(tasks))
+	m.PickingTask = true
+	cmds = append(cmds, textinput.Blink)
+	return m, tea.Batch(cmds...)
+}
+
+// handleTaskKey handles keys while the task picker is open
+func (m Model) handleTaskKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, m.quit()
+
+	case key.Matches(msg, m.Keys.Cancel):
+		m.PickingTask = false
+		return m, nil
+
+	case key.Matches(msg, m.Keys.Confirm):
+		m.PickingTask = false
+		if len(m.TaskMatches) == 0 {
+			return m, nil
+		}
+		chosen := m.TaskChoices[m.TaskMatches[m.TaskChoice]]
+		stop := m.stopTaskTracking()
+		m.ActiveTask = &chosen
+		if chosen.Project != "" {
+			m.Project = chosen.Project
+		}
+		return m, tea.Sequence(stop, m.syncTaskTracking())
+
+	case key.Matches(msg, m.Keys.PickerNext):
+		if m.TaskChoice < len(m.TaskMatches)-1 {
+			m.TaskChoice++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.Keys.PickerPrev):
+		if m.TaskChoice > 0 {
+			m.TaskChoice--
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.TaskInput, cmd = m.TaskInput.Update(msg)
+	m.TaskChoice = 0
+	m.TaskMatches = ui.FuzzyIndices(m.TaskInput.Value(), taskTitles(m.TaskChoices))
+	return m, cmd
+}
+
+// TaskDoneMsg reports whether a task was marked done in its source
+type TaskDoneMsg struct {
+	Task task.Task
+	Err  error
+}
+
+// completeActiveTask marks the active task done in its source in the
+// background, since a source such as Taskwarrior runs a command
+func (m Model) completeActiveTask() (tea.Model, tea.Cmd) {
+	if m.ActiveTask == nil {
+		cmd := m.showToast("No active task")
+		return m, cmd
+	}
+
+	source, ok := task.Find(m.TaskSources, *m.ActiveTask)
+	if !ok {
+		cmd := m.showToast("Task source is no longer configured")
+		return m, cmd
+	}
+
+	t := *m.ActiveTask
+	return m, func() tea.Msg {
+		return TaskDoneMsg{Task: t, Err: source.Complete(t)}
+	}
+}
+
+// taskDone clears the task once it has been completed, unless another task
+// was picked meanwhile
+func (m Model) taskDone(msg TaskDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		message := "Couldn't complete task: " + msg.Err.Error()
+		if errors.Is(msg.Err, task.ErrModified) {
+			message = "Task file changed on disk, pick the task again"
+		}
+		cmd := m.showToast(message)
+		return m, cmd
+	}
+
+	cmd := m.showToast("✓ Done: " + msg.Task.Title)
+	if m.ActiveTask != nil && m.ActiveTask.ID == msg.Task.ID {
+		// Completing a task in Taskwarrior also stops it
+		m.TaskTracking = false
+		m.ActiveTask = nil
+	}
+	return m, cmd
+}
+
+// activeTaskTitle returns the active task's title, falling back to the
+// day plan's current block, or "" if none
+func (m Model) activeTaskTitle() string {
+	if m.ActiveTask == nil {
+		return m.plannedTask()
+	}
+	return m.ActiveTask.Title
+}
+
+// taskPickerItems returns the titles of the tasks matching the filter
+func (m Model) taskPickerItems() []string {
+	items := make([]string, len(m.TaskMatches))
+	for i, index := range m.TaskMatches {
+		items[i] = m.TaskChoices[index].Title
+	}
+	return items
+}
+
+// taskTitles returns the title of each task
+func taskTitles(tasks []task.Task) []string {
+	titles := make([]string, len(tasks))
+	for i, t := range tasks {
+		titles[i] = t.Title
+	}
+	return titles
+}
+
+// TaskSyncMsg reports the result of updating a tracking task source
+type TaskSyncMsg struct{ Err error }
+
+// activeTracker returns the tracker behind the active task, if it has one
+func (m Model) activeTracker() (task.Tracker, bool) {
+	if m.ActiveTask == nil {
+		return nil, false
+	}
+	source, ok := task.Find(m.TaskSources, *m.ActiveTask)
+	if !ok {
+		return nil, false
+	}
+	tracker, ok := source.(task.Tracker)
+	return tracker, ok
+}
+
+// syncTaskTracking starts or stops the active task to match the timer
+// Only work sessions count as working on a task
+func (m *Model) syncTaskTracking() tea.Cmd {
+	tracker, ok := m.activeTracker()
+	if !ok {
+		m.TaskTracking = false
+		return nil
+	}
+
+	working := m.Timer.Running && m.Timer.SessionType == timer.Work
+	t := *m.ActiveTask
+	switch {
+	case working && !m.TaskTracking:
+		m.TaskTracking = true
+		return runTaskSync(func() error { return tracker.Start(t) })
+	case !working && m.TaskTracking:
+		m.TaskTracking = false
+		return runTaskSync(func() error { return tracker.Stop(t) })
+	}
+	return nil
+}
+
+// stopTaskTracking stops the active task if it was started
+func (m *Model) stopTaskTracking() tea.Cmd {
+	tracker, ok := m.activeTracker()
+	if !ok || !m.TaskTracking {
+		m.TaskTracking = false
+		return nil
+	}
+	m.TaskTracking = false
+	t := *m.ActiveTask
+	return runTaskSync(func() error { return tracker.Stop(t) })
+}
+
+// annotateTaskPomodoro notes a completed pomodoro on the active task
+func (m Model) annotateTaskPomodoro(elapsed time.Duration) tea.Cmd {
+	tracker, ok := m.activeTracker()
+	if !ok {
+		return nil
+	}
+
+	count := 0
+	if m.History != nil {
+		if records, err := m.History.All(); err == nil {
+			for _, r := range records {
+				if r.Type == timer.Work && r.Completed && r.Task == m.ActiveTask.Title {
+					count++
+				}
+			}
+		}
+	}
+	note := fmt.Sprintf("Pomodoro #%d completed (%s)", count, ui.FormatDuration(elapsed))
+	t := *m.ActiveTask
+	return runTaskSync(func() error { return tracker.Annotate(t, note) })
+}
+
+// runTaskSync runs a tracker call off the UI goroutine
+func runTaskSync(fn func() error) tea.Cmd {
+	return func() tea.Msg {
+		return TaskSyncMsg{Err: fn()}
+	}
+}
@@ -0,0 +1,344 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/task"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// todoFile writes a Markdown task list
+func todoFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "TODO.md")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// openTasks opens the task picker, reading the tasks as the app would in
+// the background
+func openTasks(t *testing.T, m Model) tea.Model {
+	t.Helper()
+	result, cmd := m.openTaskPicker()
+	require.NotNil(t, cmd)
+	msg := cmd()
+	require.IsType(t, TaskListMsg{}, msg)
+	result, _ = result.Update(msg)
+	return result
+}
+
+func TestTaskPicker_SelectAndRecord(t *testing.T) {
+	path := todoFile(t, "- [ ] Write docs\n- [ ] Fix flaky test\n")
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.TaskSources = []task.Source{task.NewMarkdown(path)}
+
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	require.False(t, result.(Model).PickingTask, "opens once the tasks are read")
+	require.NotNil(t, cmd)
+	result, _ = result.Update(cmd())
+	require.True(t, result.(Model).PickingTask)
+	assert.Equal(t, []string{"Write docs", "Fix flaky test"}, result.(Model).taskPickerItems())
+
+	result = typeText(result, "flaky")
+	assert.Equal(t, []string{"Fix flaky test"}, result.(Model).taskPickerItems())
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := result.(Model)
+
+	require.NotNil(t, model.ActiveTask)
+	assert.Equal(t, "Fix flaky test", model.ActiveTask.Title)
+	assert.Contains(t, model.View(), "Fix flaky test")
+
+	model.SessionStart = time.Now().Add(-time.Minute)
+	model.recordSession(true)
+	records, err := model.History.All()
+	require.NoError(t, err)
+	assert.Equal(t, "Fix flaky test", records[0].Task)
+}
+
+func TestTaskPicker_ProjectFromTask(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.Project = "misc"
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Write report +work\n"), 0644))
+	m.TaskSources = []task.Source{task.NewTodoTxt(path)}
+
+	result := openTasks(t, m)
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, "work", result.(Model).Project)
+}
+
+func TestTaskPicker_CancelAndEmptySelection(t *testing.T) {
+	path := todoFile(t, "- [ ] Write docs\n")
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.TaskSources = []task.Source{task.NewMarkdown(path)}
+
+	result := openTasks(t, m)
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, result.(Model).PickingTask)
+	assert.Nil(t, result.(Model).ActiveTask)
+
+	result = openTasks(t, result.(Model))
+	result = typeText(result, "zzz")
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, result.(Model).PickingTask)
+	assert.Nil(t, result.(Model).ActiveTask)
+}
+
+func TestTaskPicker_NotOpenedAfterLeavingTimer(t *testing.T) {
+	path := todoFile(t, "- [ ] Write docs\n")
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.TaskSources = []task.Source{task.NewMarkdown(path)}
+	_, cmd := m.openTaskPicker()
+	m.CurrentView = ViewComplete
+
+	result, _ := m.Update(cmd())
+
+	assert.False(t, result.(Model).PickingTask)
+}
+
+func TestTaskPicker_NoSources(t *testing.T) {
+	m := newTestModel()
+	m.CurrentView = ViewTimer
+
+	result, cmd := m.openTaskPicker()
+
+	assert.False(t, result.(Model).PickingTask)
+	assert.Equal(t, "No task sources configured", result.(Model).Toast)
+	assert.NotNil(t, cmd)
+}
+
+func TestCompleteActiveTask(t *testing.T) {
+	path := todoFile(t, "- [ ] Write docs\n")
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.TaskSources = []task.Source{task.NewMarkdown(path)}
+	result := openTasks(t, m)
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	result, cmd := result.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	require.NotNil(t, cmd)
+	require.NotNil(t, result.(Model).ActiveTask, "stays active until the task is done")
+	result, cmd = result.Update(cmd())
+	model := result.(Model)
+
+	assert.NotNil(t, cmd)
+	assert.Nil(t, model.ActiveTask)
+	assert.Equal(t, "✓ Done: Write docs", model.Toast)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "- [x] Write docs\n", string(data))
+}
+
+func TestCompleteActiveTask_FileChanged(t *testing.T) {
+	path := todoFile(t, "- [ ] Write docs\n")
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.TaskSources = []task.Source{task.NewMarkdown(path)}
+	result := openTasks(t, m)
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NoError(t, os.WriteFile(path, []byte("- [ ] Write the docs\n"), 0644))
+
+	result = completeTask(t, result.(Model))
+	model := result.(Model)
+
+	assert.NotNil(t, model.ActiveTask, "task stays active when it couldn't be completed")
+	assert.Equal(t, "Task file changed on disk, pick the task again", model.Toast)
+}
+
+func TestCompleteActiveTask_OtherTaskPickedMeanwhile(t *testing.T) {
+	path := todoFile(t, "- [ ] Write docs\n- [ ] Review PR\n")
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.TaskSources = []task.Source{task.NewMarkdown(path)}
+	result := openTasks(t, m)
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd := result.(Model).completeActiveTask()
+	result = openTasks(t, result.(Model))
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyDown})
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	result, _ = result.Update(cmd())
+	model := result.(Model)
+
+	require.NotNil(t, model.ActiveTask)
+	assert.Equal(t, "Review PR", model.ActiveTask.Title)
+	assert.Equal(t, "✓ Done: Write docs", model.Toast)
+}
+
+func TestCompleteActiveTask_NoTask(t *testing.T) {
+	m := newTestModel()
+
+	result, _ := m.completeActiveTask()
+
+	assert.Equal(t, "No active task", result.(Model).Toast)
+}
+
+// completeTask completes the active task, running the completion as the
+// app would in the background
+func completeTask(t *testing.T, m Model) tea.Model {
+	t.Helper()
+	result, cmd := m.completeActiveTask()
+	require.NotNil(t, cmd)
+	msg := cmd()
+	require.IsType(t, TaskDoneMsg{}, msg)
+	result, _ = result.Update(msg)
+	return result
+}
+
+func TestToastEndMsg_OnlyClearsLatest(t *testing.T) {
+	m := newTestModel()
+	m.showToast("first")
+	m.showToast("second")
+
+	result, _ := m.Update(ToastEndMsg{ID: 1})
+	assert.Equal(t, "second", result.(Model).Toast)
+
+	result, _ = result.Update(ToastEndMsg{ID: 2})
+	assert.Empty(t, result.(Model).Toast)
+}
+
+// fakeTaskBinary writes a task stand-in that logs its arguments
+func fakeTaskBinary(t *testing.T) (command, log string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake task binary is a shell script")
+	}
+	dir := t.TempDir()
+	log = filepath.Join(dir, "calls.log")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %q\n", log)
+	command = filepath.Join(dir, "task")
+	require.NoError(t, os.WriteFile(command, []byte(script), 0755))
+	return command, log
+}
+
+func loggedCalls(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	var calls []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		calls = append(calls, strings.TrimPrefix(line, "rc.confirmation=off rc.verbose=nothing "))
+	}
+	return calls
+}
+
+func runSync(t *testing.T, cmd tea.Cmd) {
+	t.Helper()
+	require.NotNil(t, cmd)
+	msg := cmd()
+	require.IsType(t, TaskSyncMsg{}, msg)
+	require.NoError(t, msg.(TaskSyncMsg).Err)
+}
+
+func TestTaskTracking_StartAndStopWithTimer(t *testing.T) {
+	command, log := fakeTaskBinary(t)
+	m := newTestModelWithHistory(t)
+	m.TaskSources = []task.Source{task.NewTaskwarrior(command)}
+	m.ActiveTask = &task.Task{Title: "Refactor parser", Source: "taskwarrior", Ref: "a1b2"}
+
+	m.Timer.Start()
+	runSync(t, m.syncTaskTracking())
+	assert.True(t, m.TaskTracking)
+	assert.Nil(t, m.syncTaskTracking(), "already started")
+
+	m.Timer.Pause()
+	runSync(t, m.syncTaskTracking())
+	assert.False(t, m.TaskTracking)
+
+	assert.Equal(t, []string{"a1b2 start", "a1b2 stop"}, loggedCalls(t, log))
+}
+
+func TestTaskTracking_BreaksDoNotStartTask(t *testing.T) {
+	command, log := fakeTaskBinary(t)
+	m := newTestModelWithHistory(t)
+	m.TaskSources = []task.Source{task.NewTaskwarrior(command)}
+	m.ActiveTask = &task.Task{Title: "Refactor parser", Source: "taskwarrior", Ref: "a1b2"}
+	m.Timer.SessionType = timer.ShortBreak
+	m.Timer.Start()
+
+	assert.Nil(t, m.syncTaskTracking())
+	assert.Empty(t, loggedCalls(t, log))
+}
+
+func TestTaskTracking_AnnotatesCompletedPomodoro(t *testing.T) {
+	command, log := fakeTaskBinary(t)
+	m := newTestModelWithHistory(t)
+	m.TaskSources = []task.Source{task.NewTaskwarrior(command)}
+	m.ActiveTask = &task.Task{Title: "Refactor parser", Source: "taskwarrior", Ref: "a1b2"}
+	m.Timer.Start()
+	runSync(t, m.syncTaskTracking())
+	m.SessionStart = time.Now().Add(-25 * time.Minute)
+	m.Timer.Remaining = 0
+
+	result, cmd := m.handleSessionComplete()
+	require.NotNil(t, cmd)
+	assert.False(t, result.(Model).TaskTracking, "completing work stops the task")
+
+	// The returned batch wraps these two commands in sequence
+	runSync(t, m.stopTaskTracking())
+	runSync(t, m.annotateTaskPomodoro(25*time.Minute))
+
+	assert.Equal(t, []string{
+		"a1b2 start",
+		"a1b2 stop",
+		"a1b2 annotate Pomodoro #1 completed (25m)",
+	}, loggedCalls(t, log))
+}
+
+func TestTaskSyncMsg_ErrorShowsToast(t *testing.T) {
+	m := newTestModel()
+
+	result, cmd := m.Update(TaskSyncMsg{Err: fmt.Errorf("task a1b2 start: boom")})
+
+	assert.Equal(t, "Task sync failed: task a1b2 start: boom", result.(Model).Toast)
+	assert.NotNil(t, cmd)
+}
+
+func TestTaskTracking_FileSourcesAreNotTracked(t *testing.T) {
+	path := todoFile(t, "- [ ] Write docs\n")
+	m := newTestModelWithHistory(t)
+	m.CurrentView = ViewTimer
+	m.TaskSources = []task.Source{task.NewMarkdown(path)}
+	result := openTasks(t, m)
+	result, _ = result.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := result.(Model)
+	model.Timer.Start()
+
+	assert.Nil(t, model.syncTaskTracking())
+	assert.False(t, model.TaskTracking)
+}
+
+func TestCompleteActiveTask_FailureKeepsTracking(t *testing.T) {
+	command, log := fakeTaskBinary(t)
+	m := newTestModelWithHistory(t)
+	m.TaskSources = []task.Source{task.NewTaskwarrior(command)}
+	m.ActiveTask = &task.Task{Title: "Refactor parser", Source: "taskwarrior", Ref: "a1b2"}
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %q\ncase \"$*\" in *done*) exit 1;; esac\n", log)
+	require.NoError(t, os.WriteFile(command, []byte(script), 0755))
+	m.Timer.Start()
+	runSync(t, m.syncTaskTracking())
+
+	m = completeTask(t, m).(Model)
+	assert.True(t, m.TaskTracking, "the task is still started")
+
+	m.Timer.Pause()
+	runSync(t, m.syncTaskTracking())
+	assert.Equal(t, []string{"a1b2 start", "a1b2 done", "a1b2 stop"}, loggedCalls(t, log))
+}
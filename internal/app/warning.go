@@ -0,0 +1,66 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/notify"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// warningTitle is the notification title of pre-end warnings
+const warningTitle = "Heads up"
+
+// sessionNames fill in {session} in warning messages
+var sessionNames = map[timer.SessionType]string{
+	timer.Work:       "Work session",
+	timer.ShortBreak: "Short break",
+	timer.LongBreak:  "Long break",
+}
+
+// warningLead returns how long before the current session ends its
+// warning is due, zero for none
+func (m Model) warningLead() time.Duration {
+	w := m.Config.Warnings
+	switch m.Timer.SessionType {
+	case timer.ShortBreak:
+		return w.ShortBreak
+	case timer.LongBreak:
+		return w.LongBreak
+	default:
+		return w.Work
+	}
+}
+
+// warn gives the pre-end warning through the notification channels and
+// as a toast
+func (m *Model) warn() tea.Cmd {
+	message := warningMessage(m.Config.Warnings.Message, m.Timer)
+	_ = m.Notifier.Notify(warningTitle, message)
+	toast := m.showToast(message)
+	return tea.Batch(toast, m.playSound(notify.Warning))
+}
+
+// warningMessage fills in the session name and time left
+func warningMessage(format string, t *timer.Timer) string {
+	if format == "" {
+		format = "{session} ends in {remaining}"
+	}
+	return strings.NewReplacer(
+		"{session}", sessionNames[t.SessionType],
+		"{remaining}", shortDuration(t.Remaining.Round(time.Second)),
+	).Replace(format)
+}
+
+// shortDuration drops zero seconds and minutes, e.g. "1m" rather than "1m0s"
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
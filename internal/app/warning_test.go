@@ -0,0 +1,76 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/notify"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarning_FiresOnceAcrossPauses(t *testing.T) {
+	var messages []string
+	notify.SetNotifyFuncsForTesting(func(title, message string, _ any) error {
+		messages = append(messages, title+": "+message)
+		return nil
+	}, func() {})
+	defer notify.ResetNotifyFuncsForTesting()
+
+	m := newTestModelWithHistory(t)
+	m.Config.Warnings.Work = time.Minute
+	m.CurrentView = ViewTimer
+	m.Timer.Start()
+	m.Timer.Remaining = time.Minute + time.Second
+
+	tick := func() {
+		result, _ := m.Update(TickMsg(time.Now()))
+		m = result.(Model)
+	}
+	toggle := func() {
+		result, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+		m = result.(Model)
+	}
+
+	tick()
+	require.Equal(t, []string{"Heads up: Work session ends in 1m"}, messages)
+	assert.Equal(t, "Work session ends in 1m", m.Toast)
+	assert.True(t, m.Timer.InWarning(m.warningLead()))
+
+	toggle()
+	toggle()
+	tick()
+	tick()
+	assert.Len(t, messages, 1, "resuming doesn't warn again")
+}
+
+func TestWarning_PerSessionType(t *testing.T) {
+	m := newTestModelWithHistory(t)
+	m.Config.Warnings.ShortBreak = 30 * time.Second
+	assert.Zero(t, m.warningLead(), "work sessions don't warn by default")
+	m.Timer.CompleteSession()
+
+	assert.Equal(t, 30*time.Second, m.warningLead())
+	m.Timer.SessionType = timer.LongBreak
+	assert.Zero(t, m.warningLead(), "long breaks don't warn by default")
+}
+
+func TestWarningMessage(t *testing.T) {
+	tm := timer.New()
+	tests := []struct {
+		format    string
+		remaining time.Duration
+		want      string
+	}{
+		{"", time.Minute, "Work session ends in 1m"},
+		{"{remaining} left", 2 * time.Minute, "2m left"},
+		{"{remaining} left", 90 * time.Second, "1m30s left"},
+		{"{session}: {remaining}", 30*time.Second + 400*time.Millisecond, "Work session: 30s"},
+	}
+	for _, tt := range tests {
+		tm.Remaining = tt.remaining
+		assert.Equal(t, tt.want, warningMessage(tt.format, tm))
+	}
+}
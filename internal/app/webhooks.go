@@ -0,0 +1,129 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kanishkathakur1/pomodoro/internal/hook"
+	"github.com/kanishkathakur1/pomodoro/internal/webhook"
+)
+
+// outboxInterval is how often queued webhook deliveries are looked at
+const outboxInterval = time.Minute
+
+// webhookClient sends webhook requests; each delivery sets its own timeout
+var webhookClient = &http.Client{}
+
+// WebhookMsg reports webhook events that could be neither delivered nor
+// queued for a retry
+type WebhookMsg struct {
+	Err error
+}
+
+// OutboxTickMsg triggers a retry of queued webhook deliveries
+type OutboxTickMsg time.Time
+
+// outboxTick schedules the next outbox retry
+func outboxTick() tea.Cmd {
+	return tea.Tick(outboxInterval, func(t time.Time) tea.Msg {
+		return OutboxTickMsg(t)
+	})
+}
+
+// sendWebhooks posts an event to the webhooks subscribed to it in the
+// background, keeping each delivery in the outbox until it is accepted
+func (m Model) sendWebhooks(env hook.Env) tea.Cmd {
+	if len(m.Config.Webhooks) == 0 {
+		return nil
+	}
+	ev := webhook.Event{
+		Event:     env.Event,
+		Time:      time.Now(),
+		Session:   env.Session,
+		Profile:   env.Profile,
+		Project:   env.Project,
+		Tags:      m.Tags,
+		Task:      env.Task,
+		Completed: env.Completed,
+		Reminder:  env.Reminder,
+		Stats: webhook.Stats{
+			TodayPomodoros: m.DailyGoal.Pomodoros,
+			TodayFocus:     m.DailyGoal.Focus,
+			WeekPomodoros:  m.WeeklyGoal.Pomodoros,
+			WeekFocus:      m.WeeklyGoal.Focus,
+		},
+	}
+	if env.Event != hook.Reminder {
+		ev.Elapsed = m.Timer.Duration - m.Timer.Remaining
+	}
+
+	// Render now, while the model describes the event
+	var deliveries []*webhook.Delivery
+	var errs []error
+	for _, cfg := range m.Config.Webhooks {
+		target, err := webhook.NewTarget(cfg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !target.Wants(env.Event) {
+			continue
+		}
+		d, err := target.Delivery(ev)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deliveries = append(deliveries, d)
+	}
+	if len(deliveries) == 0 && len(errs) == 0 {
+		return nil
+	}
+
+	outbox := m.Outbox
+	return func() tea.Msg {
+		for _, d := range deliveries {
+			if err := deliver(d, outbox); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return WebhookMsg{Err: errors.Join(errs...)}
+	}
+}
+
+// deliver sends d, writing it to the outbox before the attempt and removing
+// it once accepted, so that neither a failure nor a crash loses the event
+// It returns an error only if the delivery failed and couldn't be kept
+func deliver(d *webhook.Delivery, outbox *webhook.Outbox) error {
+	if outbox == nil {
+		return d.Send(webhookClient)
+	}
+	if err := outbox.Hold(d, time.Now()); err != nil {
+		// Not kept, but still worth sending
+		if sendErr := d.Send(webhookClient); sendErr != nil {
+			return errors.Join(sendErr, err)
+		}
+		return nil
+	}
+	if sendErr := d.Send(webhookClient); sendErr != nil {
+		if err := outbox.Add(d, sendErr, time.Now()); err != nil {
+			return errors.Join(sendErr, err)
+		}
+		return nil
+	}
+	return outbox.Remove(d)
+}
+
+// flushOutbox retries queued webhook deliveries in the background
+func (m Model) flushOutbox() tea.Cmd {
+	outbox := m.Outbox
+	if outbox == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_, err := outbox.Flush(webhookClient, time.Now())
+		return WebhookMsg{Err: err}
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/hook"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/kanishkathakur1/pomodoro/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendWebhooks_PostsSubscribedEvents(t *testing.T) {
+	events := make(chan webhook.Event, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev webhook.Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&ev))
+		events <- ev
+	}))
+	defer srv.Close()
+
+	m := newTestModel()
+	m.Project = "api"
+	m.DailyGoal.Pomodoros = 2
+	m.Config.Webhooks = []config.WebhookConfig{{URL: srv.URL, Events: []string{hook.SessionEnd}}}
+	m.Timer.Remaining = m.Timer.Duration - 10*time.Minute
+
+	assert.Nil(t, m.sendWebhooks(m.hookEnv(hook.SessionStart, timer.Work)), "not subscribed")
+
+	env := m.hookEnv(hook.SessionEnd, timer.Work)
+	env.Completed = true
+	assert.Equal(t, WebhookMsg{}, m.sendWebhooks(env)())
+
+	ev := <-events
+	assert.Equal(t, "session_end", ev.Event)
+	assert.Equal(t, "work", ev.Session)
+	assert.Equal(t, "api", ev.Project)
+	assert.True(t, ev.Completed)
+	assert.Equal(t, 10*time.Minute, ev.Elapsed)
+	assert.Equal(t, 2, ev.Stats.TodayPomodoros)
+}
+
+func TestSendWebhooks_QueuesFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	m := newTestModel()
+	m.Outbox = webhook.NewOutbox(filepath.Join(t.TempDir(), "outbox"))
+	m.Config.Webhooks = []config.WebhookConfig{{URL: srv.URL}}
+
+	msg := m.sendWebhooks(m.hookEnv(hook.SessionStart, timer.Work))()
+	assert.Equal(t, WebhookMsg{}, msg, "a queued delivery is not an error")
+
+	pending, err := m.Outbox.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, srv.URL, pending[0].URL)
+}
+
+func TestSendWebhooks_KeptUntilAccepted(t *testing.T) {
+	outbox := webhook.NewOutbox(filepath.Join(t.TempDir(), "outbox"))
+	held := make(chan int, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pending, _ := outbox.Pending()
+		held <- len(pending)
+	}))
+	defer srv.Close()
+
+	m := newTestModel()
+	m.Outbox = outbox
+	m.Config.Webhooks = []config.WebhookConfig{{URL: srv.URL}}
+
+	assert.Equal(t, WebhookMsg{}, m.sendWebhooks(m.hookEnv(hook.SessionStart, timer.Work))())
+	assert.Equal(t, 1, <-held, "written to the outbox before it is sent")
+
+	pending, err := outbox.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, pending, "removed once accepted")
+}
+
+func TestSendWebhooks_BadTemplateShowsToast(t *testing.T) {
+	m := newTestModel()
+	m.Config.Webhooks = []config.WebhookConfig{{URL: "https://example.test", Body: "{{.Session"}}
+
+	result, _ := m.Update(m.sendWebhooks(m.hookEnv(hook.SessionStart, timer.Work))())
+
+	assert.Contains(t, result.(Model).Toast, "webhook https://example.test")
+}
@@ -0,0 +1,128 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Meeting is one occurrence of a calendar event
+type Meeting struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// cacheSpan is how far ahead occurrences are worked out at a time
+const cacheSpan = 48 * time.Hour
+
+// file is a calendar file as last read
+type file struct {
+	modTime time.Time
+	size    int64
+	events  []Event
+}
+
+// Calendar holds the meetings from local .ics files, such as exported or
+// synced calendars, and reads files again once they change
+type Calendar struct {
+	paths   []string
+	files   map[string]*file
+	missing map[string]bool // Reported as missing already
+
+	// Occurrences from from to to, worked out on demand
+	from, to time.Time
+	cached   []Meeting
+}
+
+// New creates a calendar from files; call Refresh to read them
+func New(paths []string) *Calendar {
+	return &Calendar{paths: paths, files: map[string]*file{}, missing: map[string]bool{}}
+}
+
+// Open reads the calendar files
+// Files that can't be read are reported and left out
+func Open(paths []string) (*Calendar, error) {
+	c := New(paths)
+	return c, c.Refresh()
+}
+
+// Refresh reads the files that changed since they were last read
+// Errors are only reported when a file is read, not on every refresh
+func (c *Calendar) Refresh() error {
+	var errs []error
+	changed := false
+	for _, path := range c.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if !c.missing[path] {
+				errs = append(errs, err)
+				c.missing[path] = true
+			}
+			if _, ok := c.files[path]; ok {
+				delete(c.files, path)
+				changed = true
+			}
+			continue
+		}
+		delete(c.missing, path)
+		if f, ok := c.files[path]; ok && f.modTime.Equal(info.ModTime()) && f.size == info.Size() {
+			continue
+		}
+		events, err := readFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(path), err))
+		}
+		c.files[path] = &file{modTime: info.ModTime(), size: info.Size(), events: events}
+		changed = true
+	}
+	if changed {
+		c.from, c.to, c.cached = time.Time{}, time.Time{}, nil
+	}
+	return errors.Join(errs...)
+}
+
+// readFile parses a calendar file, keeping the events that could be read
+func readFile(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Between returns the meetings that overlap from to to, by start time
+func (c *Calendar) Between(from, to time.Time) []Meeting {
+	if from.Before(c.from) || to.After(c.to) {
+		c.from, c.to = from.Add(-time.Hour), to.Add(cacheSpan)
+		c.cached = nil
+		for _, f := range c.files {
+			for _, event := range f.events {
+				c.cached = append(c.cached, event.Meetings(c.from, c.to)...)
+			}
+		}
+		sortMeetings(c.cached)
+	}
+
+	var meetings []Meeting
+	for _, m := range c.cached {
+		if m.Start.Before(to) && m.End.After(from) {
+			meetings = append(meetings, m)
+		}
+	}
+	return meetings
+}
+
+// NextStart returns the first meeting that starts after from and before
+// to, ignoring any already going on
+func (c *Calendar) NextStart(from, to time.Time) (Meeting, bool) {
+	for _, m := range c.Between(from, to) {
+		if m.Start.After(from) {
+			return m, true
+		}
+	}
+	return Meeting{}, false
+}
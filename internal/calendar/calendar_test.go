@@ -0,0 +1,85 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// at returns a time on 10 March 2025 in UTC
+func at(hour, minute int) time.Time {
+	return time.Date(2025, 3, 10, hour, minute, 0, 0, time.UTC)
+}
+
+// writeCalendar writes events to an .ics file
+func writeCalendar(t *testing.T, path string, events ...string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(vcalendar(events...)), 0o644))
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work.ics")
+	home := filepath.Join(dir, "home.ics")
+	writeCalendar(t, work, vevent("UID:1", "SUMMARY:Standup", "DTSTART:20250310T093000Z", "DURATION:PT15M"))
+	writeCalendar(t, home, vevent("UID:2", "SUMMARY:Dentist", "DTSTART:20250310T090000Z", "DURATION:PT1H"))
+
+	c, err := Open([]string{work, home})
+	require.NoError(t, err)
+
+	meetings := c.Between(at(8, 0), at(12, 0))
+	require.Len(t, meetings, 2)
+	assert.Equal(t, "Dentist", meetings[0].Summary, "ordered by start across files")
+	assert.Equal(t, "Standup", meetings[1].Summary)
+}
+
+func TestOpen_MissingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "work.ics")
+	writeCalendar(t, path, vevent("UID:1", "SUMMARY:Standup", "DTSTART:20250310T093000Z"))
+
+	c, err := Open([]string{path, filepath.Join(dir, "missing.ics")})
+
+	assert.ErrorContains(t, err, "missing.ics")
+	assert.Len(t, c.Between(at(8, 0), at(12, 0)), 1, "other files are still read")
+	assert.NoError(t, c.Refresh(), "a missing file is reported once")
+}
+
+func TestRefresh_RereadsChangedFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.ics")
+	writeCalendar(t, path, vevent("UID:1", "SUMMARY:Standup", "DTSTART:20250310T093000Z", "DURATION:PT15M"))
+	c, err := Open([]string{path})
+	require.NoError(t, err)
+	require.Len(t, c.Between(at(8, 0), at(12, 0)), 1)
+
+	writeCalendar(t, path,
+		vevent("UID:1", "SUMMARY:Standup", "DTSTART:20250310T093000Z", "DURATION:PT15M"),
+		vevent("UID:2", "SUMMARY:Planning", "DTSTART:20250310T110000Z", "DURATION:PT1H"),
+	)
+	// Make sure the change shows even on coarse file system clocks
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	require.NoError(t, c.Refresh())
+
+	assert.Len(t, c.Between(at(8, 0), at(12, 0)), 2)
+}
+
+func TestNextStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.ics")
+	writeCalendar(t, path,
+		vevent("UID:1", "SUMMARY:Workshop", "DTSTART:20250310T090000Z", "DURATION:PT2H"),
+		vevent("UID:2", "SUMMARY:Standup", "DTSTART:20250310T093000Z", "DURATION:PT15M"),
+	)
+	c, err := Open([]string{path})
+	require.NoError(t, err)
+
+	m, ok := c.NextStart(at(9, 10), at(9, 35))
+	require.True(t, ok)
+	assert.Equal(t, "Standup", m.Summary, "the workshop going on is passed over")
+
+	_, ok = c.NextStart(at(9, 40), at(10, 5))
+	assert.False(t, ok)
+}
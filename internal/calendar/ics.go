@@ -0,0 +1,327 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/teambition/rrule-go"
+)
+
+// Event is a timed calendar event, repeating if it has a recurrence set
+type Event struct {
+	Summary  string
+	Start    time.Time
+	Duration time.Duration
+	repeats  *rrule.Set // nil for a single occurrence
+}
+
+// Meetings returns the occurrences of e that overlap from to to
+func (e Event) Meetings(from, to time.Time) []Meeting {
+	if e.repeats == nil {
+		if e.Start.Before(to) && e.Start.Add(e.Duration).After(from) {
+			return []Meeting{{Summary: e.Summary, Start: e.Start, End: e.Start.Add(e.Duration)}}
+		}
+		return nil
+	}
+	var meetings []Meeting
+	// Occurrences that started earlier may still be going on
+	for _, start := range e.repeats.Between(from.Add(-e.Duration), to, true) {
+		end := start.Add(e.Duration)
+		if start.Before(to) && end.After(from) {
+			meetings = append(meetings, Meeting{Summary: e.Summary, Start: start, End: end})
+		}
+	}
+	return meetings
+}
+
+// Parse reads the timed events in an iCalendar file
+// All-day, cancelled and free (transparent) events are left out, as they
+// don't take up working time
+func Parse(r io.Reader) ([]Event, error) {
+	cal, err := ics.ParseCalendar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	masters := map[string]int{} // UID to index in events
+	overrides := map[string][]time.Time{}
+	var errs []error
+	for _, vevent := range cal.Events() {
+		uid := value(vevent, ics.ComponentPropertyUniqueId)
+		if id := vevent.GetProperty(ics.ComponentPropertyRecurrenceId); id != nil {
+			// A changed or cancelled occurrence of a repeating event
+			if at, _, err := parseTimes(id); err == nil && len(at) == 1 {
+				overrides[uid] = append(overrides[uid], at[0])
+			}
+		}
+		event, ok, err := parseEvent(vevent)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", describe(vevent), err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if event.repeats != nil && uid != "" {
+			masters[uid] = len(events)
+		}
+		events = append(events, event)
+	}
+	for uid, times := range overrides {
+		if i, ok := masters[uid]; ok {
+			for _, t := range times {
+				events[i].repeats.ExDate(t)
+			}
+		}
+	}
+	return events, errors.Join(errs...)
+}
+
+// parseEvent converts a VEVENT, reporting false for events that don't
+// block time
+func parseEvent(vevent *ics.VEvent) (Event, bool, error) {
+	if strings.EqualFold(value(vevent, ics.ComponentPropertyStatus), "CANCELLED") ||
+		strings.EqualFold(value(vevent, ics.ComponentPropertyTransp), "TRANSPARENT") {
+		return Event{}, false, nil
+	}
+	startProp := vevent.GetProperty(ics.ComponentPropertyDtStart)
+	if startProp == nil {
+		return Event{}, false, errors.New("no DTSTART")
+	}
+	starts, allDay, err := parseTimes(startProp)
+	if err != nil {
+		return Event{}, false, err
+	}
+	if allDay || len(starts) != 1 {
+		return Event{}, false, nil
+	}
+	event := Event{Summary: value(vevent, ics.ComponentPropertySummary), Start: starts[0]}
+
+	if endProp := vevent.GetProperty(ics.ComponentPropertyDtEnd); endProp != nil {
+		ends, _, err := parseTimes(endProp)
+		if err != nil || len(ends) != 1 {
+			return Event{}, false, fmt.Errorf("DTEND: %w", err)
+		}
+		event.Duration = ends[0].Sub(event.Start)
+	} else if d := value(vevent, ics.ComponentPropertyDuration); d != "" {
+		if event.Duration, err = ParseDuration(d); err != nil {
+			return Event{}, false, fmt.Errorf("DURATION: %w", err)
+		}
+	}
+	if event.Duration < 0 {
+		return Event{}, false, errors.New("ends before it starts")
+	}
+
+	rules := vevent.GetProperties(ics.ComponentPropertyRrule)
+	extra := vevent.GetProperties(ics.ComponentPropertyRdate)
+	if len(rules) == 0 && len(extra) == 0 {
+		return event, true, nil
+	}
+	set := &rrule.Set{}
+	set.DTStart(event.Start)
+	for _, prop := range rules {
+		opt, err := rrule.StrToROption(prop.Value)
+		if err != nil {
+			return Event{}, false, fmt.Errorf("RRULE: %w", err)
+		}
+		// Occurrences keep the wall-clock time of the first one in its
+		// time zone, across daylight saving changes
+		opt.Dtstart = event.Start
+		rule, err := rrule.NewRRule(*opt)
+		if err != nil {
+			return Event{}, false, fmt.Errorf("RRULE: %w", err)
+		}
+		set.RRule(rule)
+	}
+	for _, prop := range extra {
+		times, _, err := parseTimes(prop)
+		if err != nil {
+			return Event{}, false, fmt.Errorf("RDATE: %w", err)
+		}
+		for _, t := range times {
+			set.RDate(t)
+		}
+	}
+	if len(rules) == 0 {
+		// RDATE adds to DTSTART rather than replacing it
+		set.RDate(event.Start)
+	}
+	for _, prop := range vevent.GetProperties(ics.ComponentPropertyExdate) {
+		times, _, err := parseTimes(prop)
+		if err != nil {
+			return Event{}, false, fmt.Errorf("EXDATE: %w", err)
+		}
+		for _, t := range times {
+			set.ExDate(t)
+		}
+	}
+	event.repeats = set
+	return event, true, nil
+}
+
+// value returns the value of a property, or "" if it isn't set
+func value(vevent *ics.VEvent, name ics.ComponentProperty) string {
+	if prop := vevent.GetProperty(name); prop != nil {
+		return prop.Value
+	}
+	return ""
+}
+
+// describe names an event in errors
+func describe(vevent *ics.VEvent) string {
+	if summary := value(vevent, ics.ComponentPropertySummary); summary != "" {
+		return strconv.Quote(summary)
+	}
+	return "event " + value(vevent, ics.ComponentPropertyUniqueId)
+}
+
+// parseTimes parses a date or date-time property, which may hold a comma
+// separated list, and reports whether it holds dates without a time
+// Times with a TZID are in that zone, ones ending in Z in UTC and others
+// in local time
+func parseTimes(prop *ics.IANAProperty) ([]time.Time, bool, error) {
+	loc := time.Local
+	if tzid, ok := prop.ICalParameters["TZID"]; ok && len(tzid) > 0 {
+		var err error
+		if loc, err = LoadLocation(tzid[0]); err != nil {
+			return nil, false, err
+		}
+	}
+	allDay := false
+	if v, ok := prop.ICalParameters["VALUE"]; ok && len(v) > 0 && strings.EqualFold(v[0], "DATE") {
+		allDay = true
+	}
+
+	var times []time.Time
+	for _, s := range strings.Split(prop.Value, ",") {
+		s = strings.TrimSpace(s)
+		var t time.Time
+		var err error
+		switch {
+		case len(s) == 8:
+			allDay = true
+			t, err = time.ParseInLocation("20060102", s, loc)
+		case strings.HasSuffix(s, "Z"):
+			t, err = time.Parse("20060102T150405Z", s)
+		default:
+			t, err = time.ParseInLocation("20060102T150405", s, loc)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("%q: not a date or time", s)
+		}
+		times = append(times, t)
+	}
+	return times, allDay, nil
+}
+
+// durationPattern matches RFC 5545 durations such as PT1H30M or P1W
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseDuration parses an RFC 5545 duration
+func ParseDuration(s string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("%q: not a duration", s)
+	}
+	var d time.Duration
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	for i, unit := range units {
+		if m[i+2] != "" {
+			n, _ := strconv.Atoi(m[i+2])
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// windowsZones maps the zone names Outlook and Exchange export to IANA ones
+var windowsZones = map[string]string{
+	"Dateline Standard Time":          "Etc/GMT+12",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"Mountain Standard Time":          "America/Denver",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Central Standard Time":           "America/Chicago",
+	"Eastern Standard Time":           "America/New_York",
+	"Atlantic Standard Time":          "America/Halifax",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"UTC":                             "UTC",
+	"GMT Standard Time":               "Europe/London",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Romance Standard Time":           "Europe/Paris",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"FLE Standard Time":               "Europe/Kiev",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"Russian Standard Time":           "Europe/Moscow",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"India Standard Time":             "Asia/Kolkata",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"China Standard Time":             "Asia/Shanghai",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"Korea Standard Time":             "Asia/Seoul",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"Argentina Standard Time":         "America/Argentina/Buenos_Aires",
+	"Canada Central Standard Time":    "America/Regina",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"Central America Standard Time":   "America/Guatemala",
+	"Mexico Standard Time":            "America/Mexico_City",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"Taipei Standard Time":            "Asia/Taipei",
+	"Pakistan Standard Time":          "Asia/Karachi",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"Morocco Standard Time":           "Africa/Casablanca",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+}
+
+// LoadLocation finds the time zone for a TZID, which may be an IANA name,
+// a Windows name or a path ending in an IANA name
+func LoadLocation(tzid string) (*time.Location, error) {
+	tzid = strings.Trim(tzid, `"`)
+	if name, ok := windowsZones[tzid]; ok {
+		tzid = name
+	}
+	if loc, err := time.LoadLocation(tzid); err == nil {
+		return loc, nil
+	}
+	// e.g. /freeassociation.sourceforge.net/Europe/London
+	parts := strings.Split(strings.Trim(tzid, "/"), "/")
+	for i := 1; i < len(parts); i++ {
+		if loc, err := time.LoadLocation(strings.Join(parts[i:], "/")); err == nil {
+			return loc, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown time zone %q", tzid)
+}
+
+// sortMeetings orders meetings by start, then end
+func sortMeetings(meetings []Meeting) {
+	sort.Slice(meetings, func(i, j int) bool {
+		if !meetings[i].Start.Equal(meetings[j].Start) {
+			return meetings[i].Start.Before(meetings[j].Start)
+		}
+		return meetings[i].End.Before(meetings[j].End)
+	})
+}
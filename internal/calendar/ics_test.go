@@ -0,0 +1,190 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vcalendar wraps events in a calendar
+func vcalendar(events ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		strings.Join(events, "") + "END:VCALENDAR\r\n"
+}
+
+// vevent builds an event from property lines
+func vevent(lines ...string) string {
+	return "BEGIN:VEVENT\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VEVENT\r\n"
+}
+
+// parse parses events and lists their meetings in March and April 2025
+func parse(t *testing.T, events ...string) []Meeting {
+	t.Helper()
+	parsed, err := Parse(strings.NewReader(vcalendar(events...)))
+	require.NoError(t, err)
+	var meetings []Meeting
+	for _, e := range parsed {
+		meetings = append(meetings, e.Meetings(
+			time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))...)
+	}
+	sortMeetings(meetings)
+	return meetings
+}
+
+// starts returns when meetings start, in UTC
+func starts(meetings []Meeting) []string {
+	var s []string
+	for _, m := range meetings {
+		s = append(s, m.Start.UTC().Format("2006-01-02 15:04"))
+	}
+	return s
+}
+
+func TestParse_Single(t *testing.T) {
+	meetings := parse(t, vevent(
+		"UID:1",
+		"SUMMARY:Design review\\, round 2",
+		"DTSTART:20250312T140000Z",
+		"DTEND:20250312T150000Z",
+	))
+
+	require.Len(t, meetings, 1)
+	assert.Equal(t, "Design review, round 2", meetings[0].Summary)
+	assert.Equal(t, time.Date(2025, 3, 12, 14, 0, 0, 0, time.UTC), meetings[0].Start)
+	assert.Equal(t, time.Hour, meetings[0].End.Sub(meetings[0].Start))
+}
+
+func TestParse_TimeZones(t *testing.T) {
+	meetings := parse(t,
+		// New York is on daylight time from 9 March
+		vevent("UID:1", "SUMMARY:NY", "DTSTART;TZID=America/New_York:20250310T090000", "DURATION:PT30M"),
+		// Outlook exports Windows zone names
+		vevent("UID:2", "SUMMARY:Paris", "DTSTART;TZID=Romance Standard Time:20250311T090000", "DURATION:PT30M"),
+		vevent("UID:3", "SUMMARY:London", `DTSTART;TZID="/freeassociation.sourceforge.net/Europe/London":20250312T090000`, "DURATION:PT30M"),
+	)
+
+	assert.Equal(t, []string{"2025-03-10 13:00", "2025-03-11 08:00", "2025-03-12 09:00"}, starts(meetings))
+	assert.Equal(t, 30*time.Minute, meetings[0].End.Sub(meetings[0].Start))
+}
+
+func TestParse_UnknownTimeZone(t *testing.T) {
+	events, err := Parse(strings.NewReader(vcalendar(
+		vevent("UID:1", "SUMMARY:Nowhere", "DTSTART;TZID=Atlantis:20250310T090000"),
+		vevent("UID:2", "SUMMARY:Kept", "DTSTART:20250310T090000Z"),
+	)))
+
+	assert.ErrorContains(t, err, `"Nowhere": unknown time zone "Atlantis"`)
+	require.Len(t, events, 1, "other events are still read")
+	assert.Equal(t, "Kept", events[0].Summary)
+}
+
+func TestParse_RecurringAcrossDaylightSaving(t *testing.T) {
+	// Berlin moves to summer time on 30 March, the meeting stays at 10:00
+	meetings := parse(t, vevent(
+		"UID:1",
+		"SUMMARY:Standup",
+		"DTSTART;TZID=Europe/Berlin:20250320T100000",
+		"DTEND;TZID=Europe/Berlin:20250320T101500",
+		"RRULE:FREQ=WEEKLY;COUNT=3",
+	))
+
+	assert.Equal(t, []string{"2025-03-20 09:00", "2025-03-27 09:00", "2025-04-03 08:00"}, starts(meetings))
+	for _, m := range meetings {
+		assert.Equal(t, 15*time.Minute, m.End.Sub(m.Start))
+	}
+}
+
+func TestParse_Exceptions(t *testing.T) {
+	meetings := parse(t,
+		vevent(
+			"UID:weekly",
+			"SUMMARY:1:1",
+			"DTSTART:20250303T100000Z",
+			"DURATION:PT30M",
+			"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20250401T000000Z",
+			"EXDATE:20250310T100000Z",
+		),
+		// The 17 March occurrence moved to the afternoon
+		vevent(
+			"UID:weekly",
+			"RECURRENCE-ID:20250317T100000Z",
+			"SUMMARY:1:1 (moved)",
+			"DTSTART:20250317T150000Z",
+			"DURATION:PT30M",
+		),
+		// The 24 March occurrence was cancelled
+		vevent(
+			"UID:weekly",
+			"RECURRENCE-ID:20250324T100000Z",
+			"STATUS:CANCELLED",
+			"DTSTART:20250324T100000Z",
+		),
+	)
+
+	assert.Equal(t, []string{"2025-03-03 10:00", "2025-03-17 15:00", "2025-03-31 10:00"}, starts(meetings))
+	assert.Equal(t, "1:1 (moved)", meetings[1].Summary)
+}
+
+func TestParse_RDate(t *testing.T) {
+	meetings := parse(t, vevent(
+		"UID:1",
+		"SUMMARY:Workshop",
+		"DTSTART:20250303T100000Z",
+		"DURATION:PT1H",
+		"RDATE:20250305T100000Z,20250307T100000Z",
+	))
+
+	assert.Equal(t, []string{"2025-03-03 10:00", "2025-03-05 10:00", "2025-03-07 10:00"}, starts(meetings))
+}
+
+func TestParse_SkipsEventsThatDontBlockTime(t *testing.T) {
+	meetings := parse(t,
+		vevent("UID:1", "SUMMARY:Holiday", "DTSTART;VALUE=DATE:20250310", "DTEND;VALUE=DATE:20250311"),
+		vevent("UID:2", "SUMMARY:Cancelled", "STATUS:CANCELLED", "DTSTART:20250310T090000Z"),
+		vevent("UID:3", "SUMMARY:Focus block", "TRANSP:TRANSPARENT", "DTSTART:20250310T090000Z"),
+		vevent("UID:4", "SUMMARY:Real", "DTSTART:20250310T100000Z", "DTEND:20250310T110000Z"),
+	)
+
+	require.Len(t, meetings, 1)
+	assert.Equal(t, "Real", meetings[0].Summary)
+}
+
+func TestMeetings_IncludesOnesGoingOn(t *testing.T) {
+	events, err := Parse(strings.NewReader(vcalendar(vevent(
+		"UID:1", "SUMMARY:Offsite", "DTSTART:20250310T090000Z", "DURATION:PT8H", "RRULE:FREQ=DAILY;COUNT=2",
+	))))
+	require.NoError(t, err)
+
+	noon := time.Date(2025, 3, 11, 12, 0, 0, 0, time.UTC)
+	meetings := events[0].Meetings(noon, noon.Add(time.Hour))
+	assert.Equal(t, []string{"2025-03-11 09:00"}, starts(meetings))
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT30M":     30 * time.Minute,
+		"PT1H30M":   90 * time.Minute,
+		"P1D":       24 * time.Hour,
+		"P1W":       7 * 24 * time.Hour,
+		"P1DT2H3S":  26*time.Hour + 3*time.Second,
+		"-PT15M":    -15 * time.Minute,
+		"+PT45S":    45 * time.Second,
+		"pt10m":     10 * time.Minute,
+		"P0D":       0,
+		"PT0H0M10S": 10 * time.Second,
+	}
+	for in, want := range tests {
+		got, err := ParseDuration(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, bad := range []string{"", "P", "PT", "30M", "P1H", "PT1D"} {
+		_, err := ParseDuration(bad)
+		assert.Error(t, err, bad)
+	}
+}
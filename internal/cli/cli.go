@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+)
+
+// openHistory opens the history store; tests replace it with a temp store
+var openHistory = history.Open
+
+// Configure applies the global options that come before a command:
+// --config, POMODORO_* environment variables and a flag per setting
+// It returns the remaining arguments; flag.ErrHelp means help was printed
+func Configure(args, environ []string, stderr io.Writer) ([]string, error) {
+	fs := flag.NewFlagSet("pomodoro", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", lookupEnv(environ, config.EnvPrefix+"CONFIG"), "read settings from `file`")
+	profile := fs.String("profile", lookupEnv(environ, config.EnvPrefix+"PROFILE"), "start with the named `profile`")
+	flagOverrides := []config.Override{}
+	for _, key := range config.Keys() {
+		fs.Func(key, "override "+key, func(value string) error {
+			flagOverrides = append(flagOverrides, config.Override{Key: key, Value: value, Source: config.SourceFlag})
+			return nil
+		})
+	}
+	fs.Usage = func() {
+		usage(stderr)
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Options:")
+		fmt.Fprintln(stderr, "  --config FILE      read settings from FILE (or $POMODORO_CONFIG)")
+		fmt.Fprintln(stderr, "  --profile NAME     start with a profile from the config (or $POMODORO_PROFILE)")
+		fmt.Fprintln(stderr, "  --KEY VALUE        override a setting, e.g. --timer.work 50m")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Settings can also be set with POMODORO_<SECTION>_<NAME>, e.g. POMODORO_TIMER_WORK=50m.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	overrides := append(config.EnvOverrides(environ), flagOverrides...)
+	if err := config.SetOverrides(overrides); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return nil, err
+	}
+	config.SetPath(*path)
+
+	config.SetProfile(*profile)
+	if *profile != "" {
+		// Only look at the file: parsing flags mustn't create or upgrade it
+		var profiles map[string]config.Profile
+		if file, err := config.Path(); err == nil {
+			if cfg, _ := config.LoadFile(file); cfg != nil {
+				profiles = cfg.Profiles
+			}
+		}
+		if _, ok := profiles[*profile]; !ok {
+			err := fmt.Errorf("no profile named %q", *profile)
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return nil, err
+		}
+	}
+	return fs.Args(), nil
+}
+
+// lookupEnv returns the value of name in environment entries
+func lookupEnv(environ []string, name string) string {
+	for _, entry := range environ {
+		if k, v, ok := strings.Cut(entry, "="); ok && k == name {
+			return v
+		}
+	}
+	return ""
+}
+
+// Run executes a subcommand and returns the process exit code
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	switch args[0] {
+	case "history":
+		return runHistory(args[1:], stdout, stderr)
+	case "stats":
+		return runStats(args[1:], stdout, stderr)
+	case "export":
+		return runExport(args[1:], stdout, stderr)
+	case "import":
+		return runImport(args[1:], stdout, stderr)
+	case "config":
+		return runConfig(args[1:], stdout, stderr)
+	case "plan":
+		return runPlan(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		usage(stdout)
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+// usage prints the list of subcommands
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: pomodoro [command]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command the timer TUI is started.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  history   list past sessions and search their notes")
+	fmt.Fprintln(w, "  stats     total focus time by project or tag")
+	fmt.Fprintln(w, "  export    write sessions as CSV, JSON Lines or iCalendar")
+	fmt.Fprintln(w, "  import    add sessions from another timer's export")
+	fmt.Fprintln(w, "  config    check the config file or show the effective settings")
+	fmt.Fprintln(w, "  plan      show today's plan as planned vs actual, or add to it")
+	fmt.Fprintln(w, "  help      show this help")
+}
+
+// runHistory lists recorded sessions, optionally filtered by note text
+func runHistory(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	search := fs.String("search", "", "only show sessions whose note contains `text`")
+	limit := fs.Int("limit", 0, "show at most `n` of the most recent sessions")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	records, err := store.All()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	records = history.Search(records, *search)
+	if *limit > 0 && len(records) > *limit {
+		records = records[len(records)-*limit:]
+	}
+
+	for _, r := range records {
+		fmt.Fprintln(stdout, formatRecord(r))
+	}
+	return 0
+}
+
+// formatRecord renders a record as a single history line
+func formatRecord(r history.Record) string {
+	status := "done"
+	if !r.Completed {
+		status = "skipped"
+	}
+	line := fmt.Sprintf("%s  %-11s  %3dm  %-7s",
+		r.Start.Local().Format("2006-01-02 15:04"),
+		r.Type,
+		int(r.Elapsed.Minutes()),
+		status,
+	)
+	if r.Focus > 0 {
+		line += fmt.Sprintf("  focus %d/5", r.Focus)
+	}
+	if r.Unacknowledged >= time.Minute {
+		line += fmt.Sprintf("  waited %dm", int(r.Unacknowledged.Minutes()))
+	}
+	if r.Note != "" {
+		line += "  " + r.Note
+	}
+	return line
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestHistory(t *testing.T) *history.Store {
+	t.Helper()
+	store := history.NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	openHistory = func() (*history.Store, error) { return store, nil }
+	t.Cleanup(func() { openHistory = history.Open })
+	return store
+}
+
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_NoArgs(t *testing.T) {
+	code, _, stderr := run()
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "Usage")
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	code, _, stderr := run("frobnicate")
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "unknown command")
+}
+
+func TestRun_Help(t *testing.T) {
+	code, stdout, _ := run("help")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "history")
+}
+
+func TestHistory_Search(t *testing.T) {
+	store := setupTestHistory(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Append(history.Record{
+		ID: "a", Type: timer.Work, Start: start, Elapsed: 25 * time.Minute,
+		Completed: true, Note: "refactored parser", Focus: 4, Unacknowledged: 7 * time.Minute,
+	}))
+	require.NoError(t, store.Append(history.Record{
+		ID: "b", Type: timer.Work, Start: start.Add(time.Hour), Elapsed: 10 * time.Minute,
+		Note: "emails",
+	}))
+
+	code, stdout, _ := run("history", "--search", "PARSER")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "refactored parser")
+	assert.Contains(t, stdout, "focus 4/5  waited 7m")
+	assert.NotContains(t, stdout, "emails")
+}
+
+func TestHistory_Limit(t *testing.T) {
+	store := setupTestHistory(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for i, note := range []string{"first", "second", "third"} {
+		require.NoError(t, store.Append(history.Record{
+			ID: note, Type: timer.Work, Start: start.Add(time.Duration(i) * time.Hour), Note: note,
+		}))
+	}
+
+	code, stdout, _ := run("history", "--limit", "1")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "third")
+	assert.NotContains(t, stdout, "first")
+	assert.Contains(t, stdout, "skipped")
+}
+
+func TestHistory_BadFlag(t *testing.T) {
+	setupTestHistory(t)
+
+	code, _, _ := run("history", "--nope")
+
+	assert.Equal(t, 2, code)
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() {
+		config.SetPath("")
+		_ = config.SetOverrides(nil)
+	})
+	path := filepath.Join(t.TempDir(), "alt.toml")
+	var stderr bytes.Buffer
+
+	rest, err := Configure(
+		[]string{"--config", path, "--timer.work", "50m", "stats", "--by", "tag"},
+		[]string{"POMODORO_TIMER_WORK=45m", "POMODORO_TIMER_CYCLE=2"},
+		&stderr,
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stats", "--by", "tag"}, rest)
+	got, err := config.Path()
+	require.NoError(t, err)
+	assert.Equal(t, path, got)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 50*time.Minute, cfg.Timer.Work, "flags win over the environment")
+	assert.Equal(t, 2, cfg.Timer.Cycle)
+}
+
+func TestConfigure_ConfigFromEnvironment(t *testing.T) {
+	t.Cleanup(func() { config.SetPath("") })
+
+	_, err := Configure(nil, []string{"POMODORO_CONFIG=/etc/pomodoro.toml"}, &bytes.Buffer{})
+
+	require.NoError(t, err)
+	got, _ := config.Path()
+	assert.Equal(t, "/etc/pomodoro.toml", got)
+}
+
+func TestConfigure_InvalidValue(t *testing.T) {
+	var stderr bytes.Buffer
+
+	_, err := Configure([]string{"--timer.work", "soon"}, nil, &stderr)
+
+	assert.Error(t, err)
+	assert.Contains(t, stderr.String(), `flag timer.work: "soon" is not a duration`)
+}
+
+func TestConfigure_Profile(t *testing.T) {
+	t.Cleanup(func() {
+		config.SetPath("")
+		config.SetProfile("")
+	})
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[profiles.coding.timer]\nwork = \"50m\"\n"), 0644))
+
+	_, err := Configure([]string{"--config", path, "--profile", "coding"}, nil, &bytes.Buffer{})
+	require.NoError(t, err)
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "coding", cfg.ActiveProfile())
+	assert.Equal(t, 50*time.Minute, cfg.Timer.Work)
+
+	var stderr bytes.Buffer
+	_, err = Configure([]string{"--config", path}, []string{"POMODORO_PROFILE=study"}, &stderr)
+	assert.Error(t, err)
+	assert.Contains(t, stderr.String(), `no profile named "study"`)
+}
+
+func TestConfigure_ProfileDoesNotCreateConfigFile(t *testing.T) {
+	t.Cleanup(func() {
+		config.SetPath("")
+		config.SetProfile("")
+	})
+	path := filepath.Join(t.TempDir(), "config.toml")
+
+	_, err := Configure([]string{"--config", path, "--profile", "coding"}, nil, &bytes.Buffer{})
+
+	assert.Error(t, err)
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/sound"
+)
+
+// runConfig dispatches the config subcommands
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "Usage: pomodoro config check [FILE]")
+		fmt.Fprintln(stderr, "       pomodoro config show [--effective]")
+		return 2
+	}
+
+	switch args[0] {
+	case "check":
+		return runConfigCheck(args[1:], stdout, stderr)
+	case "show":
+		return runConfigShow(args[1:], stdout, stderr)
+	}
+
+	fmt.Fprintf(stderr, "unknown config command %q\n", args[0])
+	return 2
+}
+
+// runConfigCheck reports every problem in the config file
+func runConfigCheck(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("config check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "Usage: pomodoro config check [FILE]")
+		return 2
+	}
+
+	path := fs.Arg(0)
+	if path == "" {
+		var err error
+		if path, err = config.Path(); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && fs.NArg() == 0 {
+		fmt.Fprintf(stdout, "%s: not found, defaults are used\n", path)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	issues := config.Check(data)
+	if cfg, _ := config.LoadFile(path); cfg != nil && cfg.Sound.Enabled && !sound.Available() {
+		// Linux and BSD builds only play sounds with -tags sound
+		issues = append(issues, config.Issue{Key: "sound.enabled", Message: "this build has no audio output, rebuild with -tags sound", Warning: true})
+	}
+	if len(issues) == 0 {
+		fmt.Fprintf(stdout, "%s: OK\n", path)
+		return 0
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(stdout, "%s:%s\n", path, issue)
+	}
+	return 1
+}
+
+// runConfigShow prints the config file, or with --effective every
+// setting after all layers are applied and the layer it came from
+func runConfigShow(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("config show", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	effective := fs.Bool("effective", false, "show the merged settings and where each came from")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	path, err := config.Path()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if !*effective {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			fmt.Fprintf(stdout, "# %s: not found, defaults are used\n", path)
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		stdout.Write(data)
+		return 0
+	}
+
+	cfg, err := config.Read()
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: %v\n", err)
+	}
+	fmt.Fprintf(stdout, "# %s\n", path)
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	for _, key := range config.Keys() {
+		source := string(cfg.Source(key))
+		if source == string(config.SourceEnv) {
+			source += " " + config.EnvName(key)
+		}
+		fmt.Fprintf(tw, "%s = %s\t# %s\n", key, cfg.Value(key), source)
+	}
+	tw.Flush()
+	return 0
+}
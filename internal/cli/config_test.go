@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/sound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	config.SetPath(path)
+	t.Cleanup(func() { config.SetPath("") })
+	return path
+}
+
+func TestConfigCheck_OK(t *testing.T) {
+	path := setupTestConfigFile(t, "[goals]\ndaily_pomodoros = 6\n")
+
+	code, stdout, _ := run("config", "check")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, path+": OK\n", stdout)
+}
+
+func TestConfigCheck_ReportsIssues(t *testing.T) {
+	path := setupTestConfigFile(t, "[goals]\ndaily_pomodoros = 6\ndaily_pomodoro = 4\n\n[timer]\ncycle = -1\n")
+
+	code, stdout, _ := run("config", "check")
+
+	assert.Equal(t, 1, code)
+	assert.Equal(t,
+		path+":3:1: warning: goals.daily_pomodoro: unknown key\n"+
+			path+":6:1: timer.cycle: must not be negative\n",
+		stdout)
+}
+
+func TestConfigCheck_SoundWithoutAudio(t *testing.T) {
+	if sound.Available() {
+		t.Skip("this build plays sounds")
+	}
+	path := setupTestConfigFile(t, "[sound]\nenabled = true\n")
+
+	code, stdout, _ := run("config", "check")
+
+	assert.Equal(t, 1, code)
+	assert.Equal(t, path+":warning: sound.enabled: this build has no audio output, rebuild with -tags sound\n", stdout)
+}
+
+func TestConfigCheck_File(t *testing.T) {
+	setupTestConfigFile(t, "")
+	other := filepath.Join(t.TempDir(), "other.toml")
+	require.NoError(t, os.WriteFile(other, []byte("[ui\n"), 0644))
+
+	code, stdout, _ := run("config", "check", other)
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stdout, other+":1:4: ")
+}
+
+func TestConfigCheck_MissingFile(t *testing.T) {
+	path := setupTestConfigFile(t, "")
+	require.NoError(t, os.Remove(path))
+
+	code, stdout, _ := run("config", "check")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "defaults are used")
+}
+
+func TestConfig_UnknownCommand(t *testing.T) {
+	code, _, stderr := run("config", "frobnicate")
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "unknown config command")
+}
+
+func TestConfigShow(t *testing.T) {
+	setupTestConfigFile(t, "# mine\n[timer]\nwork = \"40m\"\n")
+
+	code, stdout, _ := run("config", "show")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "# mine\n[timer]\nwork = \"40m\"\n", stdout)
+}
+
+func TestConfigShow_MissingFile(t *testing.T) {
+	path := setupTestConfigFile(t, "")
+	require.NoError(t, os.Remove(path))
+
+	code, stdout, _ := run("config", "show")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "# "+path+": not found, defaults are used\n", stdout)
+
+	code, stdout, _ = run("config", "show", "--effective")
+	assert.Equal(t, 0, code)
+	assert.Regexp(t, `timer.work = "25m" +# default\n`, stdout)
+	assert.NoFileExists(t, path, "showing doesn't create the file")
+}
+
+func TestConfigShow_EffectiveDoesNotUpgrade(t *testing.T) {
+	path := setupTestConfigFile(t, "[timer]\nwork = \"40m\"\n")
+
+	code, stdout, _ := run("config", "show", "--effective")
+
+	assert.Equal(t, 0, code)
+	assert.Regexp(t, `timer.work = "40m" +# file\n`, stdout)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[timer]\nwork = \"40m\"\n", string(data), "the file is left as it was")
+	backups, _ := filepath.Glob(path + ".v*.bak")
+	assert.Empty(t, backups)
+}
+
+func TestConfigShow_Effective(t *testing.T) {
+	path := setupTestConfigFile(t, "[timer]\nwork = \"40m\"\nshort_break = \"8m\"\n")
+	require.NoError(t, config.SetOverrides([]config.Override{
+		{Key: "timer.short_break", Value: "7m", Source: config.SourceEnv},
+		{Key: "timer.cycle", Value: "2", Source: config.SourceFlag},
+	}))
+	t.Cleanup(func() { _ = config.SetOverrides(nil) })
+
+	code, stdout, _ := run("config", "show", "--effective")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "# "+path+"\n")
+	assert.Regexp(t, `timer.work = "40m" +# file\n`, stdout)
+	assert.Regexp(t, `timer.short_break = "7m" +# env POMODORO_TIMER_SHORT_BREAK\n`, stdout)
+	assert.Regexp(t, `timer.cycle = 2 +# flag\n`, stdout)
+	assert.Regexp(t, `timer.long_break = "15m" +# default\n`, stdout)
+}
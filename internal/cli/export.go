@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// dateLayout is the layout of --from and --to
+const dateLayout = "2006-01-02"
+
+// runExport writes filtered history in the requested format
+func runExport(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	formatName := fs.String("format", "csv", "output `format`: csv, jsonl or ics")
+	from := fs.String("from", "", "first `date` to include (YYYY-MM-DD)")
+	to := fs.String("to", "", "last `date` to include (YYYY-MM-DD)")
+	types := fs.String("type", "", "comma-separated session `types` (work, short_break, long_break)")
+	projectName := fs.String("project", "", "only sessions in this `project`")
+	task := fs.String("task", "", "only sessions for this `task`")
+	tag := fs.String("tag", "", "only sessions with this `tag`")
+	profile := fs.String("profile", "", "only sessions run under this `profile`")
+	output := fs.String("output", "", "write to `file` instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	format, err := history.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	filter := history.Filter{Project: *projectName, Task: *task, Tag: *tag, Profile: *profile}
+	if filter.From, filter.To, err = parseDateRange(*from, *to); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *types != "" {
+		for _, name := range strings.Split(*types, ",") {
+			sessionType := timer.SessionType(strings.TrimSpace(name))
+			switch sessionType {
+			case timer.Work, timer.ShortBreak, timer.LongBreak:
+			default:
+				fmt.Fprintf(stderr, "Error: --type: unknown session type %q\n", name)
+				return 2
+			}
+			filter.Types = append(filter.Types, sessionType)
+		}
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	records, err := store.All()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := history.Export(w, format, filter.Apply(records)); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseDateRange parses inclusive --from and --to dates into [from, to)
+// Empty dates leave that end of the range open
+func parseDateRange(from, to string) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if from != "" {
+		day, err := time.ParseInLocation(dateLayout, from, time.Local)
+		if err != nil {
+			return start, end, fmt.Errorf("--from: %w", err)
+		}
+		start = day
+	}
+	if to != "" {
+		day, err := time.ParseInLocation(dateLayout, to, time.Local)
+		if err != nil {
+			return start, end, fmt.Errorf("--to: %w", err)
+		}
+		end = day.AddDate(0, 0, 1)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedExportHistory(t *testing.T) {
+	t.Helper()
+	store := setupTestHistory(t)
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	for i, r := range []history.Record{
+		{Type: timer.Work, Project: "api", Task: "API refactor", Tags: []string{"backend"}},
+		{Type: timer.ShortBreak},
+		{Type: timer.Work, Task: "Reviews", Profile: "study"},
+	} {
+		r.Start = day.AddDate(0, 0, i)
+		r.End = r.Start.Add(25 * time.Minute)
+		r.ID = history.NewID(r.Start)
+		r.Completed = true
+		require.NoError(t, store.Append(r))
+	}
+}
+
+func TestExport_CSVToStdout(t *testing.T) {
+	seedExportHistory(t)
+
+	code, stdout, _ := run("export", "--type", "work")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "id,type,start")
+	assert.Contains(t, stdout, "API refactor")
+	assert.Contains(t, stdout, "Reviews")
+	assert.NotContains(t, stdout, "short_break")
+}
+
+func TestExport_DateRangeIsInclusive(t *testing.T) {
+	seedExportHistory(t)
+
+	code, stdout, _ := run("export", "--format", "jsonl", "--from", "2026-03-03", "--to", "2026-03-04")
+
+	assert.Equal(t, 0, code)
+	assert.NotContains(t, stdout, "API refactor")
+	assert.Contains(t, stdout, "short_break")
+	assert.Contains(t, stdout, "Reviews")
+}
+
+func TestExport_ICSToFile(t *testing.T) {
+	seedExportHistory(t)
+	out := filepath.Join(t.TempDir(), "focus.ics")
+
+	code, stdout, _ := run("export", "--format", "ics", "--tag", "backend", "--output", out)
+
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stdout)
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "SUMMARY:API refactor")
+	assert.NotContains(t, string(data), "Reviews")
+}
+
+func TestExport_Profile(t *testing.T) {
+	seedExportHistory(t)
+
+	code, stdout, _ := run("export", "--profile", "study")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, ",profile\n")
+	assert.Contains(t, stdout, "Reviews")
+	assert.Contains(t, stdout, ",study\n")
+	assert.NotContains(t, stdout, "API refactor")
+}
+
+func TestExport_BadArguments(t *testing.T) {
+	seedExportHistory(t)
+
+	tests := [][]string{
+		{"export", "--format", "xml"},
+		{"export", "--from", "March"},
+		{"export", "--to", "2026-13-01"},
+		{"export", "--type", "nap"},
+	}
+
+	for _, args := range tests {
+		code, _, stderr := run(args...)
+		assert.Equal(t, 2, code, args)
+		assert.Contains(t, stderr, "Error", args)
+	}
+}
+
+func TestExport_ByProject(t *testing.T) {
+	seedExportHistory(t)
+
+	code, stdout, _ := run("export", "--project", "API")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "API refactor")
+	assert.NotContains(t, stdout, "Reviews")
+}
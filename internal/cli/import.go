@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kanishkathakur1/pomodoro/internal/importer"
+)
+
+// runImport reads another tool's export into history
+func runImport(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	source := fs.String("from", "csv", "export `source`: csv, toggl, pomofocus or superproductivity")
+	mapping := fs.String("map", "", "generic CSV column `mapping`, e.g. start=Began,duration=Length")
+	layout := fs.String("time-layout", "", "Go time `layout` of generic CSV timestamps (default RFC 3339)")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without writing")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "Usage: pomodoro import [flags] FILE")
+		fs.PrintDefaults()
+		return 2
+	}
+
+	columns, err := importer.ParseMapping(*mapping)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	imp, err := importer.New(*source, importer.Options{Mapping: columns, TimeLayout: *layout})
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	incoming, err := imp.Import(f)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	existing, err := store.All()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	report := importer.Plan(existing, incoming)
+	if *dryRun {
+		for _, r := range report.New {
+			fmt.Fprintln(stdout, "+ "+formatRecord(r))
+		}
+		for _, r := range report.Duplicates {
+			fmt.Fprintln(stdout, "= "+formatRecord(r))
+		}
+		fmt.Fprintf(stdout, "Would import %d sessions, %d duplicates skipped\n",
+			len(report.New), len(report.Duplicates))
+		return 0
+	}
+
+	if len(report.New) > 0 {
+		if err := store.Insert(report.New); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	fmt.Fprintf(stdout, "Imported %d sessions, %d duplicates skipped\n",
+		len(report.New), len(report.Duplicates))
+	return 0
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const togglExport = `Start date,Start time,Duration,Description
+2024-01-15,09:00:00,00:25:00,Essay
+2024-01-15,10:00:00,00:25:00,Reading
+`
+
+func writeImportFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestImport_DryRun(t *testing.T) {
+	store := setupTestHistory(t)
+	path := writeImportFile(t, togglExport)
+
+	code, stdout, _ := run("import", "--from", "toggl", "--dry-run", path)
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "+ ")
+	assert.Contains(t, stdout, "Would import 2 sessions, 0 duplicates skipped")
+	records, err := store.All()
+	require.NoError(t, err)
+	assert.Empty(t, records, "dry run must not write")
+}
+
+func TestImport_SkipsDuplicates(t *testing.T) {
+	store := setupTestHistory(t)
+	existing := time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local)
+	require.NoError(t, store.Append(history.Record{ID: "x", Type: timer.Work, Start: existing, Completed: true}))
+	path := writeImportFile(t, togglExport)
+
+	code, stdout, _ := run("import", "--from", "toggl", path)
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "Imported 1 sessions, 1 duplicates skipped")
+	records, err := store.All()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "Essay", records[0].Task, "imported records are merged in start order")
+
+	// Importing the same file again adds nothing
+	code, stdout, _ = run("import", "--from", "toggl", path)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "Imported 0 sessions, 2 duplicates skipped")
+}
+
+func TestImport_GenericMapping(t *testing.T) {
+	store := setupTestHistory(t)
+	path := writeImportFile(t, "Began,Length\n2024-01-15T09:00:00Z,25m\n")
+
+	code, _, _ := run("import", "--map", "start=Began,duration=Length", path)
+
+	assert.Equal(t, 0, code)
+	records, err := store.All()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 25*time.Minute, records[0].Elapsed)
+}
+
+func TestImport_Errors(t *testing.T) {
+	setupTestHistory(t)
+	bad := writeImportFile(t, "Start date,Start time,Duration\nsoon,later,forever\n")
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"import"}, 2},
+		{[]string{"import", "--from", "clockify", bad}, 2},
+		{[]string{"import", "--map", "colour=x", bad}, 2},
+		{[]string{"import", filepath.Join(t.TempDir(), "missing.csv")}, 1},
+		{[]string{"import", "--from", "toggl", bad}, 1},
+	}
+
+	for _, tt := range tests {
+		code, _, stderr := run(tt.args...)
+		assert.Equal(t, tt.code, code, tt.args)
+		assert.NotEmpty(t, stderr, tt.args)
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/plan"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+)
+
+// planPath returns where the day plan is kept; tests replace it
+var planPath = plan.DefaultPath
+
+// runPlan prints today's plan as planned versus actual, after adding a
+// block to it or clearing it
+func runPlan(args []string, stdout, stderr io.Writer) int {
+	path, err := planPath()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	cfg, _ := config.Read()
+	boundary, _ := cfg.Goals.DayBoundary()
+	day := history.DayStart(time.Now(), boundary)
+	p, err := plan.Load(path, day)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "add":
+			block, err := plan.Parse(strings.Join(args[1:], " "))
+			if err != nil {
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				return 2
+			}
+			p.Blocks = append(p.Blocks, block)
+		case "clear":
+			p = plan.New(day)
+		default:
+			fmt.Fprintf(stderr, "unknown plan command %q\n\n", args[0])
+			fmt.Fprintln(stderr, "Usage: pomodoro plan [add BLOCK | clear]")
+			fmt.Fprintln(stderr, "  e.g. pomodoro plan add 3 API refactor")
+			fmt.Fprintln(stderr, "       pomodoro plan add long break")
+			fmt.Fprintln(stderr, "       pomodoro plan add 14:00 2 reviews")
+			return 2
+		}
+		if err := p.Save(path); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	if len(p.Blocks) == 0 {
+		fmt.Fprintln(stdout, "Nothing planned for today")
+		return 0
+	}
+	for _, b := range p.Blocks {
+		fmt.Fprintln(stdout, ui.PlanLine(b))
+	}
+	fmt.Fprintln(stdout, ui.PlanTotals(p))
+	return 0
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestPlan(t *testing.T) string {
+	t.Helper()
+	setupTestConfigFile(t, "")
+	path := filepath.Join(t.TempDir(), "plan.json")
+	planPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { planPath = plan.DefaultPath })
+	return path
+}
+
+func TestPlan_Empty(t *testing.T) {
+	setupTestPlan(t)
+
+	code, stdout, _ := run("plan")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "Nothing planned for today\n", stdout)
+}
+
+func TestPlan_DoesNotCreateConfigFile(t *testing.T) {
+	setupTestPlan(t)
+	path, err := config.Path()
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(path))
+
+	code, _, _ := run("plan")
+
+	assert.Equal(t, 0, code)
+	assert.NoFileExists(t, path)
+}
+
+func TestPlan_AddAndShow(t *testing.T) {
+	path := setupTestPlan(t)
+
+	code, _, _ := run("plan", "add", "3", "pomodoros", "on", "API", "refactor")
+	require.Equal(t, 0, code)
+	code, _, _ = run("plan", "add", "long", "break")
+	require.Equal(t, 0, code)
+	code, stdout, _ := run("plan", "add", "14:00 2 reviews")
+	require.Equal(t, 0, code)
+
+	lines := strings.Split(strings.TrimRight(stdout, "\n"), "\n")
+	require.Len(t, lines, 4)
+	assert.Regexp(t, `^  ·\s+API refactor\s+0/3$`, lines[0])
+	assert.Regexp(t, `^  ·\s+Long break\s+–$`, lines[1])
+	assert.Regexp(t, `^14:00  reviews\s+0/2$`, lines[2])
+	assert.Equal(t, "0 of 5 planned pomodoros done", lines[3])
+	assert.FileExists(t, path)
+
+	_, again, _ := run("plan")
+	assert.Equal(t, stdout, again, "the plan is kept")
+}
+
+func TestPlan_Clear(t *testing.T) {
+	setupTestPlan(t)
+	run("plan", "add", "3 API refactor")
+
+	code, stdout, _ := run("plan", "clear")
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "Nothing planned for today\n", stdout)
+}
+
+func TestPlan_BadArguments(t *testing.T) {
+	setupTestPlan(t)
+
+	code, _, stderr := run("plan", "add", "25:00", "reviews")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "want HH:MM")
+
+	code, _, stderr = run("plan", "remove")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "unknown plan command")
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/ui"
+)
+
+// runStats prints completed work totals grouped by project or tag
+func runStats(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	by := fs.String("by", "project", "group by `field`: project or tag")
+	from := fs.String("from", "", "first `date` to include (YYYY-MM-DD)")
+	to := fs.String("to", "", "last `date` to include (YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var key func(history.Record) []string
+	switch *by {
+	case "project":
+		key = history.ByProject
+	case "tag":
+		key = history.ByTag
+	default:
+		fmt.Fprintf(stderr, "Error: --by: want project or tag, got %q\n", *by)
+		return 2
+	}
+
+	start, end, err := parseDateRange(*from, *to)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	if end.IsZero() {
+		end = time.Now().Add(time.Second)
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	records, err := store.All()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	groups := history.Breakdown(records, start, end, key)
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := groups[names[i]], groups[names[j]]
+		if a.Focus != b.Focus {
+			return a.Focus > b.Focus
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		label := name
+		if label == "" {
+			label = "(none)"
+		}
+		totals := groups[name]
+		fmt.Fprintf(stdout, "%-24s %4d  %8s\n", label, totals.Pomodoros, ui.FormatDuration(totals.Focus))
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedStatsHistory(t *testing.T) {
+	t.Helper()
+	store := setupTestHistory(t)
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	for i, r := range []history.Record{
+		{Project: "api", Tags: []string{"backend"}, Elapsed: 25 * time.Minute},
+		{Project: "api", Tags: []string{"backend", "q2"}, Elapsed: 50 * time.Minute},
+		{Project: "docs", Elapsed: 25 * time.Minute},
+		{Elapsed: 25 * time.Minute},
+	} {
+		r.ID = string(rune('a' + i))
+		r.Type = timer.Work
+		r.Completed = true
+		r.Start = day.AddDate(0, 0, i)
+		require.NoError(t, store.Append(r))
+	}
+}
+
+func TestStats_ByProject(t *testing.T) {
+	seedStatsHistory(t)
+
+	code, stdout, _ := run("stats")
+
+	assert.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^api\s+2\s+1h15m$`, lines[0], "largest focus first")
+	assert.Regexp(t, `^\(none\)\s+1\s+25m$`, lines[1])
+	assert.Regexp(t, `^docs\s+1\s+25m$`, lines[2])
+}
+
+func TestStats_ByTagInRange(t *testing.T) {
+	seedStatsHistory(t)
+
+	code, stdout, _ := run("stats", "--by", "tag", "--from", "2026-03-03")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "backend")
+	assert.Contains(t, stdout, "q2")
+	assert.Regexp(t, `backend\s+1\s+50m`, stdout)
+}
+
+func TestStats_BadArguments(t *testing.T) {
+	seedStatsHistory(t)
+
+	code, _, _ := run("stats", "--by", "colour")
+	assert.Equal(t, 2, code)
+
+	code, _, _ = run("stats", "--from", "yesterday")
+	assert.Equal(t, 2, code)
+}
@@ -0,0 +1,348 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Issue is a problem found in the config file
+type Issue struct {
+	Line    int    // 1-based, 0 if unknown
+	Column  int    // 1-based, 0 if unknown
+	Key     string // Dotted key the issue is about, if any
+	Message string
+	Warning bool // The file can still be used
+}
+
+// String formats the issue as "line:column: key: message"
+func (i Issue) String() string {
+	var b strings.Builder
+	if i.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d: ", i.Line, i.Column)
+	}
+	if i.Warning {
+		b.WriteString("warning: ")
+	}
+	if i.Key != "" {
+		b.WriteString(i.Key + ": ")
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// FileError lists the issues found in a config file
+type FileError struct {
+	Path   string
+	Issues []Issue
+}
+
+// Error summarizes the first issue
+func (e *FileError) Error() string {
+	msg := e.Path + ":" + e.Issues[0].String()
+	if more := len(e.Issues) - 1; more > 0 {
+		msg += fmt.Sprintf(" (and %d more)", more)
+	}
+	return msg
+}
+
+// Applied reports whether the file could be used despite the issues
+func (e *FileError) Applied() bool {
+	for _, issue := range e.Issues {
+		if !issue.Warning {
+			return false
+		}
+	}
+	return true
+}
+
+// Check reports every issue in config file content
+func Check(data []byte) []Issue {
+	_, issues := check(data)
+	return issues
+}
+
+// check decodes and validates config file content
+// The config is nil if the content has errors
+func check(data []byte) (*Config, []Issue) {
+	var raw map[string]any
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, []Issue{decodeIssue(data, err)}
+	}
+	current, err := migrated(data)
+	if err != nil {
+		line, col := locate(data, "version")
+		return nil, []Issue{{Line: line, Column: col, Key: "version", Message: err.Error()}}
+	}
+
+	cfg := DefaultConfig()
+	md, err := toml.Decode(string(current), cfg)
+	if err != nil {
+		issue := decodeIssue(current, err)
+		if !bytes.Equal(current, data) {
+			// Positions in the upgraded content don't match the file
+			issue.Line, issue.Column = locate(data, issue.Key)
+		}
+		return nil, []Issue{issue}
+	}
+	base := DefaultConfig()
+	if _, err := toml.Decode(string(current), base); err != nil {
+		return nil, []Issue{decodeIssue(current, err)}
+	}
+	cfg.base = base
+	cfg.fileKeys = map[string]bool{}
+	for _, key := range md.Keys() {
+		cfg.fileKeys[key.String()] = true
+	}
+
+	var issues []Issue
+	reported := map[string]bool{}
+	for _, key := range md.Undecoded() {
+		// A whole unknown table is reported once, not key by key
+		if len(key) > 1 && reported[key[:len(key)-1].String()] {
+			reported[key.String()] = true
+			continue
+		}
+		reported[key.String()] = true
+		issues = append(issues, Issue{Key: key.String(), Message: "unknown key", Warning: true})
+	}
+	issues = append(issues, cfg.validate()...)
+
+	for i := range issues {
+		issues[i].Line, issues[i].Column = locate(data, issues[i].Key)
+	}
+	// Report in file order, with issues that have no position last
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i].Line, issues[j].Line
+		return a != 0 && (b == 0 || a < b)
+	})
+	for _, issue := range issues {
+		if !issue.Warning {
+			return nil, issues
+		}
+	}
+	return cfg, issues
+}
+
+// lastKeyPattern finds the key in decode errors that carry no position
+var lastKeyPattern = regexp.MustCompile(`last key "([^"]+)"\): (.*)$`)
+
+// decodeIssue converts a TOML decode error into an issue with a position
+func decodeIssue(data []byte, err error) Issue {
+	var parseErr toml.ParseError
+	if errors.As(err, &parseErr) {
+		issue := Issue{
+			Line:    parseErr.Position.Line,
+			Column:  parseErr.Position.Col,
+			Key:     parseErr.LastKey,
+			Message: parseErr.Message,
+		}
+		// The decoder counts an error at the end of a line as on the next
+		// one, so derive the position from the byte offset instead
+		if start := parseErr.Position.Start; start > 0 && start <= len(data) {
+			before := data[:start]
+			issue.Line = bytes.Count(before, []byte("\n")) + 1
+			issue.Column = start - bytes.LastIndexByte(before, '\n')
+		}
+		return issue
+	}
+	if m := lastKeyPattern.FindStringSubmatch(err.Error()); m != nil {
+		line, col := locate(data, m[1])
+		return Issue{Line: line, Column: col, Key: m[1], Message: m[2]}
+	}
+	return Issue{Message: strings.TrimPrefix(err.Error(), "toml: ")}
+}
+
+// locate finds the line and column where a dotted key or table is set
+// It returns 0, 0 if the key isn't found
+func locate(data []byte, key string) (int, int) {
+	if key == "" {
+		return 0, 0
+	}
+	headers, entries := scan(strings.Split(string(data), "\n"))
+	for _, e := range entries {
+		if e.key == key {
+			return e.start + 1, e.column
+		}
+	}
+	for _, h := range headers {
+		if h.name == key {
+			return h.line + 1, h.column
+		}
+	}
+	return 0, 0
+}
+
+// Validate checks values that parse but make no sense
+func (c *Config) Validate() error {
+	var errs []error
+	for _, issue := range c.validate() {
+		errs = append(errs, errors.New(issue.String()))
+	}
+	return errors.Join(errs...)
+}
+
+// validate lists values that parse but make no sense
+func (c *Config) validate() []Issue {
+	var issues []Issue
+	invalid := func(key, format string, args ...any) {
+		issues = append(issues, Issue{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	durations := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"timer.work", c.Timer.Work},
+		{"timer.short_break", c.Timer.ShortBreak},
+		{"timer.long_break", c.Timer.LongBreak},
+		{"goals.daily_focus", c.Goals.DailyFocus},
+		{"goals.weekly_focus", c.Goals.WeeklyFocus},
+		{"warnings.work", c.Warnings.Work},
+		{"warnings.short_break", c.Warnings.ShortBreak},
+		{"warnings.long_break", c.Warnings.LongBreak},
+		{"reminders.interval", c.Reminders.Interval},
+		{"push.backoff", c.Push.Backoff},
+		{"calendar.lookahead", c.Calendar.Lookahead},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			invalid(d.key, "must not be negative")
+		}
+	}
+	if c.Timer.Cycle < 0 {
+		invalid("timer.cycle", "must not be negative")
+	}
+	if c.Reminders.Max < 0 {
+		invalid("reminders.max", "must not be negative")
+	}
+	if c.Push.Retries < 0 {
+		invalid("push.retries", "must not be negative")
+	}
+	switch c.Push.Service {
+	case "", "ntfy":
+		if c.Push.Priority < 0 || c.Push.Priority > 5 {
+			invalid("push.priority", "%d: want 1 to 5 for ntfy, or 0", c.Push.Priority)
+		}
+	case "gotify":
+		if c.Push.Priority < 0 || c.Push.Priority > 10 {
+			invalid("push.priority", "%d: want 0 to 10 for gotify", c.Push.Priority)
+		}
+	default:
+		invalid("push.service", "%q: want ntfy or gotify", c.Push.Service)
+	}
+	switch c.Status.Service {
+	case "", "slack":
+	case "mattermost":
+		if u, err := url.Parse(c.Status.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("status.url", "%q: want the Mattermost server's http or https URL", c.Status.URL)
+		}
+	default:
+		invalid("status.service", "%q: want slack or mattermost", c.Status.Service)
+	}
+	if c.MQTT.Broker != "" {
+		u, err := url.Parse(c.MQTT.Broker)
+		if err != nil || !slices.Contains([]string{"tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss"}, u.Scheme) || u.Host == "" {
+			invalid("mqtt.broker", "%q: want a URL such as tcp://localhost:1883", c.MQTT.Broker)
+		}
+		if strings.Trim(c.MQTT.Topic, "/") == "" || strings.ContainsAny(c.MQTT.Topic, "#+") {
+			invalid("mqtt.topic", "%q: want a topic without wildcards", c.MQTT.Topic)
+		}
+	}
+	if c.MQTT.QoS < 0 || c.MQTT.QoS > 2 {
+		invalid("mqtt.qos", "%d: want 0, 1 or 2", c.MQTT.QoS)
+	}
+	switch c.Calendar.Conflict {
+	case "", "warn", "shorten", "off":
+	default:
+		invalid("calendar.conflict", "%q: want warn, shorten or off", c.Calendar.Conflict)
+	}
+	if c.Metrics.Listen != "" {
+		if _, port, err := net.SplitHostPort(c.Metrics.Listen); err != nil || port == "" {
+			invalid("metrics.listen", "%q: want host:port, e.g. 127.0.0.1:9091", c.Metrics.Listen)
+		}
+	}
+	for i, w := range c.Webhooks {
+		key := fmt.Sprintf("webhooks[%d]", i)
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid(key+".url", "%q: want an http or https URL", w.URL)
+		}
+		for _, event := range w.Events {
+			if !slices.Contains(WebhookEvents, event) {
+				invalid(key+".events", "%q: want %s", event, strings.Join(WebhookEvents, ", "))
+			}
+		}
+		if w.Timeout < 0 {
+			invalid(key+".timeout", "must not be negative")
+		}
+	}
+	if c.Goals.DailyPomodoros < 0 {
+		invalid("goals.daily_pomodoros", "must not be negative")
+	}
+	if c.Goals.WeeklyPomodoros < 0 {
+		invalid("goals.weekly_pomodoros", "must not be negative")
+	}
+	if c.Sound.Volume < 0 || c.Sound.Volume > 100 {
+		invalid("sound.volume", "%d: want 0 to 100", c.Sound.Volume)
+	}
+	switch c.Notifications.Terminal {
+	case "", "off", "auto", "osc9", "osc777", "kitty":
+	default:
+		invalid("notifications.terminal", "%q: want off, auto, osc9, osc777 or kitty", c.Notifications.Terminal)
+	}
+	for _, entry := range c.QuietHours.Schedule {
+		if _, err := parseQuietPeriod(entry); err != nil {
+			invalid("quiet_hours.schedule", "%q: %v", entry, err)
+		}
+	}
+	for _, channel := range c.QuietHours.Mute {
+		if !slices.Contains(QuietChannels, strings.ToLower(strings.TrimSpace(channel))) {
+			invalid("quiet_hours.mute", "%q: want %s", channel, strings.Join(QuietChannels, ", "))
+		}
+	}
+	if _, err := c.Goals.DayBoundary(); err != nil {
+		invalid("goals.day_start", "%q: want HH:MM", c.Goals.DayStart)
+	}
+	if _, err := c.Goals.FirstWeekday(); err != nil {
+		invalid("goals.week_start", "%q: want a weekday name", c.Goals.WeekStart)
+	}
+
+	if _, ok := c.Profiles[c.DefaultProfile]; c.DefaultProfile != "" && !ok {
+		invalid("profile", "no profile named %q", c.DefaultProfile)
+	}
+	for _, name := range c.ProfileNames() {
+		timer := c.Profiles[name].Timer
+		if timer.Work < 0 || timer.ShortBreak < 0 || timer.LongBreak < 0 || timer.Cycle < 0 {
+			invalid("profiles."+name+".timer", "must not be negative")
+		}
+	}
+
+	keys := reflect.ValueOf(c.Keys)
+	for i := 0; i < keys.NumField(); i++ {
+		for _, k := range keys.Field(i).Interface().([]string) {
+			if strings.TrimSpace(k) == "" {
+				invalid("keys."+tomlName(keys.Type().Field(i)), "empty key")
+			}
+		}
+	}
+	return issues
+}
+
+// tomlName returns the key a struct field is stored under
+func tomlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck_Valid(t *testing.T) {
+	issues := Check([]byte("[goals]\ndaily_pomodoros = 6\n"))
+
+	assert.Empty(t, issues)
+}
+
+func TestCheck_SyntaxErrorPosition(t *testing.T) {
+	issues := Check([]byte("[goals]\ndaily_pomodoros = 6\nday_start = \"04:00\n"))
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, 3, issues[0].Line)
+	assert.False(t, issues[0].Warning)
+}
+
+func TestCheck_TypeMismatch(t *testing.T) {
+	issues := Check([]byte("[goals]\n\n  daily_pomodoros = \"six\"\n"))
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, 3, issues[0].Line)
+	assert.Equal(t, 3, issues[0].Column)
+	assert.Equal(t, "goals.daily_pomodoros", issues[0].Key)
+	assert.Contains(t, issues[0].Message, "incompatible types")
+}
+
+func TestCheck_InvalidDuration(t *testing.T) {
+	issues := Check([]byte("[timer]\nwork = \"25 minutes\"\n"))
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, 2, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "duration")
+}
+
+func TestCheck_UnknownKeys(t *testing.T) {
+	content := `[notifications]
+terminal_bel = false
+
+[colours]
+accent = "red"
+work = "pink"
+`
+	issues := Check([]byte(content))
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, Issue{Line: 2, Column: 1, Key: "notifications.terminal_bel", Message: "unknown key", Warning: true}, issues[0])
+	assert.Equal(t, "colours", issues[1].Key, "an unknown table is reported once")
+	assert.Equal(t, 4, issues[1].Line)
+}
+
+func TestCheck_InvalidValues(t *testing.T) {
+	issues := Check([]byte("[goals]\nweek_start = \"someday\"\n\n[timer]\ncycle = -2\n"))
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, "goals.week_start", issues[0].Key)
+	assert.Equal(t, 2, issues[0].Line)
+	assert.Equal(t, "timer.cycle", issues[1].Key)
+	assert.Equal(t, 5, issues[1].Line)
+}
+
+func TestIssue_String(t *testing.T) {
+	issue := Issue{Line: 4, Column: 2, Key: "timer.work", Message: "must not be negative"}
+	assert.Equal(t, "4:2: timer.work: must not be negative", issue.String())
+
+	issue = Issue{Key: "ui.colour", Message: "unknown key", Warning: true}
+	assert.Equal(t, "warning: ui.colour: unknown key", issue.String())
+}
+
+func TestFileError(t *testing.T) {
+	err := &FileError{Path: "config.toml", Issues: []Issue{
+		{Line: 1, Column: 1, Key: "a", Message: "unknown key", Warning: true},
+		{Line: 2, Column: 1, Key: "timer.cycle", Message: "must not be negative"},
+	}}
+
+	assert.Equal(t, "config.toml:1:1: warning: a: unknown key (and 1 more)", err.Error())
+	assert.False(t, err.Applied())
+
+	err.Issues = err.Issues[:1]
+	assert.True(t, err.Applied())
+}
+
+func TestLoadFile_UnknownKeyIsWarning(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+	require.NoError(t, os.WriteFile(configFile, []byte("[goals]\ndaily_pomodoros = 3\ndaily_pomodoro = 4\n"), 0644))
+
+	cfg, err := LoadFile(configFile)
+
+	require.NotNil(t, cfg)
+	assert.Equal(t, 3, cfg.Goals.DailyPomodoros)
+	var fileErr *FileError
+	require.ErrorAs(t, err, &fileErr)
+	assert.True(t, fileErr.Applied())
+}
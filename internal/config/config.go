@@ -1,52 +1,353 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Notifications NotificationConfig `toml:"notifications"`
+	Version        int                `toml:"version"` // Layout version, see CurrentVersion
+	DefaultProfile string             `toml:"profile"` // Profile used at launch, empty for none
+	Notifications  NotificationConfig `toml:"notifications"`
+	Sound          SoundConfig        `toml:"sound"`
+	Warnings       WarningConfig      `toml:"warnings"`
+	Reminders      ReminderConfig     `toml:"reminders"`
+	QuietHours     QuietHoursConfig   `toml:"quiet_hours"`
+	Push           PushConfig         `toml:"push"`
+	Webhooks       []WebhookConfig    `toml:"webhooks"`
+	Status         StatusConfig       `toml:"status"`
+	MQTT           MQTTConfig         `toml:"mqtt"`
+	DBus           DBusConfig         `toml:"dbus"`
+	Calendar       CalendarConfig     `toml:"calendar"`
+	Metrics        MetricsConfig      `toml:"metrics"`
+	Reflection     ReflectionConfig   `toml:"reflection"`
+	Goals          GoalConfig         `toml:"goals"`
+	Projects       ProjectConfig      `toml:"projects"`
+	Tasks          TaskConfig         `toml:"tasks"`
+	Timer          TimerConfig        `toml:"timer"`
+	UI             UIConfig           `toml:"ui"`
+	Keys           KeyConfig          `toml:"keys"`
+	Hooks          HookConfig         `toml:"hooks"`
+	Profiles       map[string]Profile `toml:"profiles"`
+
+	// base is the file content this config was read from or last saved as,
+	// used to merge edits made on disk in the meantime
+	base *Config
+
+	// fileKeys holds the keys set in the file, overrides the values set by
+	// environment variables and flags, which are never saved
+	fileKeys  map[string]bool
+	overrides map[string]applied
+	active    string // Profile currently applied
+
+	// unapplied is why the file couldn't be used or upgraded; such a
+	// config is never saved over the file
+	unapplied error
 }
 
 // NotificationConfig controls notification behavior
 type NotificationConfig struct {
-	VisualFlash        bool `toml:"visual_flash"`
-	TerminalBell       bool `toml:"terminal_bell"`
-	SystemNotification bool `toml:"system_notification"`
+	VisualFlash        bool   `toml:"visual_flash"`
+	TerminalBell       bool   `toml:"terminal_bell"`
+	SystemNotification bool   `toml:"system_notification"`
+	Terminal           string `toml:"terminal"` // off, auto, osc9, osc777 or kitty
+}
+
+// SoundConfig sets the sounds played on session transitions
+// Each sound is a bundled name (chime, bell, ding, tick) or the path to a
+// WAV or OGG file, empty for silence
+type SoundConfig struct {
+	Enabled  bool   `toml:"enabled"`
+	Volume   int    `toml:"volume"` // Percent, 0-100
+	WorkEnd  string `toml:"work_end"`
+	BreakEnd string `toml:"break_end"`
+	Warning  string `toml:"warning"` // Played with pre-end warnings
+	Ticking  bool   `toml:"ticking"` // Loop the tick sound while a work session runs
+	Tick     string `toml:"tick"`
+}
+
+// WarningConfig sets how long before each session type ends a heads-up
+// is given, zero for none
+type WarningConfig struct {
+	Work       time.Duration `toml:"work"`
+	ShortBreak time.Duration `toml:"short_break"`
+	LongBreak  time.Duration `toml:"long_break"`
+	Message    string        `toml:"message"` // {session} and {remaining} are filled in
+}
+
+// ReminderConfig repeats the notification while a finished session waits
+// for a key press, escalating from the bell to a system notification to
+// the reminder hook
+type ReminderConfig struct {
+	Interval time.Duration `toml:"interval"` // Between reminders, 0 for none
+	Max      int           `toml:"max"`      // Reminders before giving up, 0 for none
+}
+
+// PushConfig sends session alerts to a self-hosted ntfy or Gotify server
+// The templates set the message when each session type ends, with
+// {title}, {message}, {session}, {task} and {project} filled in; empty sends
+// the usual message
+type PushConfig struct {
+	Enabled    bool          `toml:"enabled"`
+	Service    string        `toml:"service"` // ntfy or gotify
+	URL        string        `toml:"url"`
+	Topic      string        `toml:"topic"`    // ntfy only
+	Token      string        `toml:"token"`    // ntfy access token or user:password, Gotify app token
+	Priority   int           `toml:"priority"` // ntfy 1-5, Gotify 0-10, 0 for the server default
+	Retries    int           `toml:"retries"`
+	Backoff    time.Duration `toml:"backoff"` // Before the first retry, doubling after each
+	Work       string        `toml:"work"`
+	ShortBreak string        `toml:"short_break"`
+	LongBreak  string        `toml:"long_break"`
+}
+
+// WebhookConfig posts session events to a URL
+// The body is a text/template rendered with the event, or the event as
+// JSON if empty
+type WebhookConfig struct {
+	URL     string            `toml:"url"`
+	Events  []string          `toml:"events"` // session_start, session_end, reminder; all if empty
+	Body    string            `toml:"body"`
+	Headers map[string]string `toml:"headers"`
+	Secret  string            `toml:"secret"`  // Signs the body with HMAC-SHA256
+	Timeout time.Duration     `toml:"timeout"` // 10s if unset
+}
+
+// StatusConfig shows focus sessions in a Slack or Mattermost status
+// Tokens are read from the credentials file
+type StatusConfig struct {
+	Service string `toml:"service"` // slack or mattermost, empty for off
+	URL     string `toml:"url"`     // Mattermost server
+	Text    string `toml:"text"`    // {end} and {task} are filled in
+	Emoji   string `toml:"emoji"`
+	DND     bool   `toml:"dnd"` // Do not disturb while working
 }
 
-// configPathOverride allows tests to inject a custom config path
-var configPathOverride string
+// MQTTConfig publishes the timer state to an MQTT broker, e.g. for a
+// focus light
+// The username and password are read from the credentials file
+type MQTTConfig struct {
+	Broker   string `toml:"broker"` // e.g. tcp://localhost:1883, empty for off
+	ClientID string `toml:"client_id"`
+	Topic    string `toml:"topic"` // Root of the topic tree
+	QoS      int    `toml:"qos"`
+	Commands bool   `toml:"commands"` // Accept toggle and skip on <topic>/command
+}
 
-// SetConfigPathForTesting sets a custom config path for testing purposes
-func SetConfigPathForTesting(path string) {
-	configPathOverride = path
+// DBusConfig exposes the timer on the session bus for desktop extensions
+// and scripts
+type DBusConfig struct {
+	Enabled bool `toml:"enabled"`
 }
 
-// ResetConfigPathForTesting resets the config path override
-func ResetConfigPathForTesting() {
-	configPathOverride = ""
+// CalendarConfig plans sessions around the meetings in local .ics files
+// Relative paths are resolved against the working directory
+type CalendarConfig struct {
+	Files       []string      `toml:"files"`
+	Conflict    string        `toml:"conflict"`     // warn, shorten or off, for work sessions that run into a meeting
+	AlignBreaks bool          `toml:"align_breaks"` // End breaks as meetings start
+	Lookahead   time.Duration `toml:"lookahead"`    // How soon a meeting must be to show, 0 to hide
+}
+
+// MetricsConfig serves Prometheus metrics at /metrics
+type MetricsConfig struct {
+	Listen string `toml:"listen"` // e.g. 127.0.0.1:9091, empty for off
+}
+
+// WebhookEvents are the events webhooks can subscribe to
+var WebhookEvents = []string{"session_start", "session_end", "reminder"}
+
+// ReflectionConfig controls the end-of-session reflection prompt
+type ReflectionConfig struct {
+	Enabled bool `toml:"enabled"`
+}
+
+// GoalConfig sets daily and weekly focus targets
+// A zero target is not tracked
+type GoalConfig struct {
+	DailyPomodoros  int           `toml:"daily_pomodoros"`
+	DailyFocus      time.Duration `toml:"daily_focus"`
+	WeeklyPomodoros int           `toml:"weekly_pomodoros"`
+	WeeklyFocus     time.Duration `toml:"weekly_focus"`
+	DayStart        string        `toml:"day_start"`  // Local "HH:MM" when a new day begins
+	WeekStart       string        `toml:"week_start"` // Weekday name a new week begins on
+}
+
+// DayBoundary returns the offset from midnight at which a new day begins
+func (g GoalConfig) DayBoundary() (time.Duration, error) {
+	if g.DayStart == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", g.DayStart)
+	if err != nil {
+		return 0, fmt.Errorf("day_start %q: want HH:MM", g.DayStart)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// FirstWeekday returns the weekday on which a new week begins
+func (g GoalConfig) FirstWeekday() (time.Weekday, error) {
+	if g.WeekStart == "" {
+		return time.Monday, nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(g.WeekStart, d.String()) {
+			return d, nil
+		}
+	}
+	return time.Monday, fmt.Errorf("week_start %q: want a weekday name", g.WeekStart)
+}
+
+// ProjectConfig controls how sessions are labelled with a project
+type ProjectConfig struct {
+	Default       string   `toml:"default"`        // Project used when none is derived
+	FromGit       bool     `toml:"from_git"`       // Derive from the enclosing git repository
+	FromDirectory bool     `toml:"from_directory"` // Derive from the working directory name
+	Tags          []string `toml:"tags"`           // Tags always offered in the picker
+}
+
+// TaskConfig lists the files active tasks are picked from
+// Relative paths are resolved against the working directory
+type TaskConfig struct {
+	TodoTxt            string   `toml:"todo_txt"`
+	Markdown           []string `toml:"markdown"`
+	Taskwarrior        bool     `toml:"taskwarrior"`
+	TaskwarriorCommand string   `toml:"taskwarrior_command"` // "task" if empty
+}
+
+// TimerConfig sets session lengths
+// A zero value keeps the standard Pomodoro timing
+type TimerConfig struct {
+	Work       time.Duration `toml:"work"`
+	ShortBreak time.Duration `toml:"short_break"`
+	LongBreak  time.Duration `toml:"long_break"`
+	Cycle      int           `toml:"cycle"` // Work sessions before a long break
+}
+
+// UIConfig controls the look of the TUI
+type UIConfig struct {
+	Theme string `toml:"theme"`
+}
+
+// KeyConfig overrides key bindings
+// Each action takes a list of keys such as "space", "enter" or "ctrl+s"
+type KeyConfig struct {
+	Toggle       []string `toml:"toggle,omitempty"`
+	Skip         []string `toml:"skip,omitempty"`
+	Reset        []string `toml:"reset,omitempty"`
+	Notify       []string `toml:"notify,omitempty"`
+	Labels       []string `toml:"labels,omitempty"`
+	PickTask     []string `toml:"pick_task,omitempty"`
+	CompleteTask []string `toml:"complete_task,omitempty"`
+	PickProfile  []string `toml:"pick_profile,omitempty"`
+	Plan         []string `toml:"plan,omitempty"`
+	DoNotDisturb []string `toml:"do_not_disturb,omitempty"`
+	Help         []string `toml:"help,omitempty"`
+	Quit         []string `toml:"quit,omitempty"`
+}
+
+// HookConfig sets shell commands run as sessions start and end
+// Commands get the session details in POMODORO_* environment variables
+type HookConfig struct {
+	SessionStart string `toml:"session_start"`
+	SessionEnd   string `toml:"session_end"`
+	Reminder     string `toml:"reminder"` // Last step of unacknowledged session reminders
+}
+
+// pathOverride is the config file chosen with --config
+var pathOverride string
+
+// SetPath makes Load and Save use the config file at path
+// An empty path restores the default location
+func SetPath(path string) {
+	pathOverride = path
 }
 
 // DefaultConfig returns sensible default configuration
 func DefaultConfig() *Config {
 	return &Config{
+		Version: CurrentVersion(),
 		Notifications: NotificationConfig{
 			VisualFlash:        true,
 			TerminalBell:       true,
 			SystemNotification: true,
+			Terminal:           "off",
+		},
+		Sound: SoundConfig{
+			Volume:   70,
+			WorkEnd:  "chime",
+			BreakEnd: "bell",
+			Warning:  "ding",
+			Tick:     "tick",
+		},
+		Warnings: WarningConfig{
+			Message: "{session} ends in {remaining}",
+		},
+		QuietHours: QuietHoursConfig{
+			Mute: []string{"bell", "system", "sound", "terminal", "push"},
+		},
+		Status: StatusConfig{
+			Text:  "Focusing until {end}",
+			Emoji: ":tomato:",
+			DND:   true,
+		},
+		Calendar: CalendarConfig{
+			Conflict:    "warn",
+			AlignBreaks: true,
+			Lookahead:   2 * time.Hour,
+		},
+		MQTT: MQTTConfig{
+			ClientID: "pomodoro",
+			Topic:    "pomodoro",
+			QoS:      1,
+		},
+		Push: PushConfig{
+			Service: "ntfy",
+			URL:     "https://ntfy.sh",
+			Retries: 3,
+			Backoff: 5 * time.Second,
+		},
+		Goals: GoalConfig{
+			DailyPomodoros: 8,
+			DayStart:       "04:00",
+			WeekStart:      "monday",
+		},
+		Projects: ProjectConfig{
+			FromGit: true,
+		},
+		Tasks: TaskConfig{
+			Markdown: []string{"TODO.md"},
+		},
+		Timer: TimerConfig{
+			Work:       25 * time.Minute,
+			ShortBreak: 5 * time.Minute,
+			LongBreak:  15 * time.Minute,
+			Cycle:      4,
+		},
+		UI: UIConfig{
+			Theme: "neon",
 		},
 	}
 }
 
+// Path returns the path to the config file
+func Path() (string, error) {
+	return configPath()
+}
+
 // configPath returns the path to the config file
 func configPath() (string, error) {
-	if configPathOverride != "" {
-		return configPathOverride, nil
+	if pathOverride != "" {
+		return pathOverride, nil
 	}
 	configDir, err := os.UserConfigDir()
 	if err != nil {
@@ -56,11 +357,12 @@ func configPath() (string, error) {
 }
 
 // Load reads configuration from the config file
-// If the file doesn't exist, it creates one with defaults
+// If the file doesn't exist, it creates one with defaults. If it can't be
+// used, defaults are returned along with a *FileError explaining why
 func Load() (*Config, error) {
 	path, err := configPath()
 	if err != nil {
-		return DefaultConfig(), nil
+		return withOverrides(DefaultConfig()), nil
 	}
 
 	// Check if config file exists
@@ -69,27 +371,116 @@ func Load() (*Config, error) {
 		cfg := DefaultConfig()
 		if err := cfg.Save(); err != nil {
 			// If we can't save, just return defaults
-			return cfg, nil
+			return withOverrides(cfg), nil
 		}
-		return cfg, nil
+		return withOverrides(cfg), nil
 	}
 
+	// Bring an older file up to date, keeping a backup; if that fails the
+	// file is still upgraded in memory below
+	upgradeErr := upgrade(path)
+
 	// Read existing config
-	cfg := &Config{}
-	if _, err := toml.DecodeFile(path, cfg); err != nil {
-		// On error, return defaults
-		return DefaultConfig(), nil
+	cfg, err := LoadFile(path)
+	if cfg == nil {
+		// The file can't be used, so run with defaults and say why
+		cfg = withOverrides(DefaultConfig())
+		cfg.unapplied = err
+		return cfg, err
+	}
+	if upgradeErr != nil {
+		// Saving would mix the new layout into the old file
+		cfg.unapplied = upgradeErr
+		issue := Issue{Key: "version", Message: "not upgraded, so settings won't be saved: " + upgradeErr.Error(), Warning: true}
+		if data, readErr := os.ReadFile(path); readErr == nil {
+			issue.Line, issue.Column = locate(data, issue.Key)
+		}
+		var fileErr *FileError
+		if errors.As(err, &fileErr) {
+			fileErr.Issues = append(fileErr.Issues, issue)
+		} else {
+			err = &FileError{Path: path, Issues: []Issue{issue}}
+		}
+	}
+
+	return cfg, err
+}
+
+// Read is Load without writing: a missing file gives the defaults and an
+// older one is only upgraded in memory, for commands that just look
+func Read() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return withOverrides(DefaultConfig()), nil
 	}
+	cfg, err := LoadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return withOverrides(DefaultConfig()), nil
+	}
+	if cfg == nil {
+		cfg = withOverrides(DefaultConfig())
+		cfg.unapplied = err
+	}
+	return cfg, err
+}
 
+// LoadFile reads and validates the config file at path, applying the
+// launch profile and the overrides from SetOverrides on top
+// A file with errors returns no config and a *FileError listing them;
+// unknown keys are only warnings, so the config is returned along with
+// a *FileError listing those
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, issues := check(data)
+	if cfg != nil {
+		withOverrides(cfg)
+		if err := withProfile(cfg); err != nil {
+			issues = append(issues, Issue{Key: "profile", Message: err.Error(), Warning: true})
+		}
+	}
+	if len(issues) == 0 {
+		return cfg, nil
+	}
+	fileErr := &FileError{Path: path, Issues: issues}
+	if !fileErr.Applied() {
+		return nil, fileErr
+	}
+	return cfg, fileErr
+}
+
+// parse decodes config file content, remembering it as the merge base
+func parse(data []byte) (*Config, error) {
+	data, err := migrated(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg := DefaultConfig()
+	if _, err := toml.Decode(string(data), cfg); err != nil {
+		return nil, err
+	}
+	base := DefaultConfig()
+	if _, err := toml.Decode(string(data), base); err != nil {
+		return nil, err
+	}
+	cfg.base = base
 	return cfg, nil
 }
 
 // Save writes the configuration to the config file
+// Settings changed in the file since it was read are kept unless this
+// config changed them too; a file that has errors is not overwritten,
+// and neither is one this config couldn't be loaded from
 func (c *Config) Save() error {
 	path, err := configPath()
 	if err != nil {
 		return err
 	}
+	if c.unapplied != nil {
+		return fmt.Errorf("not overwriting %s: %w", path, c.unapplied)
+	}
 
 	// Ensure directory exists
 	dir := filepath.Dir(path)
@@ -97,13 +488,114 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	// Write config file
-	f, err := os.Create(path)
-	if err != nil {
+	c.Version = CurrentVersion()
+	data, err := os.ReadFile(path)
+	var disk *Config
+	switch {
+	case err == nil:
+		if disk, err = parse(data); err != nil {
+			return fmt.Errorf("not overwriting %s: %w", path, err)
+		}
+		// A broken edit is left for the user to fix, even though the
+		// last good config is still running
+		if valid, issues := check(data); valid == nil {
+			return fmt.Errorf("not overwriting %s: %w", path, &FileError{Path: path, Issues: issues})
+		}
+		if c.base != nil {
+			merge(reflect.ValueOf(c).Elem(), reflect.ValueOf(c.base).Elem(), reflect.ValueOf(disk).Elem())
+		}
+	case !os.IsNotExist(err):
 		return err
 	}
-	defer f.Close()
 
-	encoder := toml.NewEncoder(f)
-	return encoder.Encode(c)
+	// Overrides from the environment and flags stay out of the file
+	file := c.withoutOverrides(disk)
+	var out []byte
+	if disk != nil {
+		out = patch(data, file, disk)
+	}
+
+	// Write the whole file if there is none yet or patching it went wrong
+	saved, err := parse(out)
+	if out == nil || err != nil || !sameSettings(saved, file) {
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(file); err != nil {
+			return err
+		}
+		out = buf.Bytes()
+		if saved, err = parse(out); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFileAtomic(path, out); err != nil {
+		return err
+	}
+	c.base = saved.base
+	return nil
+}
+
+// sameSettings reports whether two configs hold the same settings
+func sameSettings(a, b *Config) bool {
+	x, y := *a, *b
+	x.base, y.base = nil, nil
+	x.fileKeys, y.fileKeys = nil, nil
+	x.overrides, y.overrides = nil, nil
+	x.active, y.active = "", ""
+	x.unapplied, y.unapplied = nil, nil
+	return reflect.DeepEqual(x, y)
+}
+
+// merge copies settings from disk into mine wherever mine still matches base
+func merge(mine, base, disk reflect.Value) {
+	for i := 0; i < mine.NumField(); i++ {
+		field := mine.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if field.Kind() == reflect.Struct {
+			merge(field, base.Field(i), disk.Field(i))
+			continue
+		}
+		if reflect.DeepEqual(field.Interface(), base.Field(i).Interface()) {
+			field.Set(disk.Field(i))
+		}
+	}
+}
+
+// Watcher reports when the config file changes on disk
+type Watcher struct {
+	path    string
+	modTime time.Time
+	size    int64
+	content []byte
+}
+
+// NewWatcher starts watching the config file at path
+func NewWatcher(path string) *Watcher {
+	w := &Watcher{path: path}
+	w.Changed()
+	return w
+}
+
+// Changed reports whether the file content differs from the last call
+// The file is only read when its size or modification time moved
+func (w *Watcher) Changed() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return false
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	if bytes.Equal(data, w.content) {
+		return false
+	}
+	w.content = data
+	return true
 }
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,10 +19,10 @@ func setupTestConfig(t *testing.T) (string, func()) {
 	require.NoError(t, err)
 
 	configFile := filepath.Join(tmpDir, "config.toml")
-	SetConfigPathForTesting(configFile)
+	SetPath(configFile)
 
 	cleanup := func() {
-		ResetConfigPathForTesting()
+		SetPath("")
 		os.RemoveAll(tmpDir)
 	}
 
@@ -55,6 +57,25 @@ func TestLoad_NoConfigFile(t *testing.T) {
 	assert.NoError(t, err, "config file should be created")
 }
 
+func TestRead_LeavesFileAlone(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg, err := Read()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig().Timer, cfg.Timer)
+	assert.NoFileExists(t, configFile, "a missing file isn't created")
+
+	old := "[timer]\nwork = \"40m\"\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(old), 0644))
+	cfg, err = Read()
+	require.NoError(t, err)
+	assert.Equal(t, 40*time.Minute, cfg.Timer.Work)
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, old, string(data), "an older file is only upgraded in memory")
+}
+
 func TestLoad_ValidConfigFile(t *testing.T) {
 	configFile, cleanup := setupTestConfig(t)
 	defer cleanup()
@@ -90,8 +111,10 @@ func TestLoad_InvalidTOML(t *testing.T) {
 
 	cfg, err := Load()
 
-	// Should return defaults on parse error
-	require.NoError(t, err)
+	// Should return defaults on parse error, and say why
+	var fileErr *FileError
+	require.ErrorAs(t, err, &fileErr)
+	assert.Equal(t, configFile, fileErr.Path)
 	assert.True(t, cfg.Notifications.VisualFlash)
 	assert.True(t, cfg.Notifications.TerminalBell)
 	assert.True(t, cfg.Notifications.SystemNotification)
@@ -128,8 +151,8 @@ func TestSave_CreatesDirectory(t *testing.T) {
 
 	// Set config path to a nested directory that doesn't exist
 	nestedPath := filepath.Join(tmpDir, "subdir", "another", "config.toml")
-	SetConfigPathForTesting(nestedPath)
-	defer ResetConfigPathForTesting()
+	SetPath(nestedPath)
+	defer SetPath("")
 
 	cfg := DefaultConfig()
 	err = cfg.Save()
@@ -187,15 +210,15 @@ system_notification = true
 
 	require.NoError(t, err)
 	assert.False(t, cfg.Notifications.VisualFlash)
-	// Missing field should be zero value (false for bool)
-	assert.False(t, cfg.Notifications.TerminalBell)
+	// Missing field falls back to the default layer
+	assert.True(t, cfg.Notifications.TerminalBell)
 	assert.True(t, cfg.Notifications.SystemNotification)
 }
 
 func TestConfigPath_UsesOverride(t *testing.T) {
 	customPath := "/custom/path/config.toml"
-	SetConfigPathForTesting(customPath)
-	defer ResetConfigPathForTesting()
+	SetPath(customPath)
+	defer SetPath("")
 
 	path, err := configPath()
 
@@ -204,7 +227,7 @@ func TestConfigPath_UsesOverride(t *testing.T) {
 }
 
 func TestConfigPath_UsesDefaultWhenNoOverride(t *testing.T) {
-	ResetConfigPathForTesting()
+	SetPath("")
 
 	path, err := configPath()
 
@@ -212,3 +235,213 @@ func TestConfigPath_UsesDefaultWhenNoOverride(t *testing.T) {
 	assert.Contains(t, path, "pomodoro")
 	assert.Contains(t, path, "config.toml")
 }
+
+func TestGoalConfig_DayBoundary(t *testing.T) {
+	tests := []struct {
+		dayStart string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"", 0, false},
+		{"00:00", 0, false},
+		{"04:30", 4*time.Hour + 30*time.Minute, false},
+		{"late", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dayStart, func(t *testing.T) {
+			boundary, err := GoalConfig{DayStart: tt.dayStart}.DayBoundary()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, boundary)
+		})
+	}
+}
+
+func TestGoalConfig_FirstWeekday(t *testing.T) {
+	day, err := GoalConfig{}.FirstWeekday()
+	require.NoError(t, err)
+	assert.Equal(t, time.Monday, day)
+
+	day, err = GoalConfig{WeekStart: "Sunday"}.FirstWeekday()
+	require.NoError(t, err)
+	assert.Equal(t, time.Sunday, day)
+
+	_, err = GoalConfig{WeekStart: "someday"}.FirstWeekday()
+	assert.Error(t, err)
+}
+
+func TestLoad_GoalsFromFile(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	configContent := `[goals]
+daily_pomodoros = 6
+daily_focus = "4h"
+weekly_focus = "20h"
+day_start = "05:00"
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(configContent), 0644))
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, cfg.Goals.DailyPomodoros)
+	assert.Equal(t, 4*time.Hour, cfg.Goals.DailyFocus)
+	assert.Equal(t, 20*time.Hour, cfg.Goals.WeeklyFocus)
+	assert.Equal(t, "05:00", cfg.Goals.DayStart)
+}
+
+func TestLoadFile_ReportsParseError(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configFile, []byte("this is { not valid toml"), 0644))
+
+	_, err := LoadFile(configFile)
+
+	assert.Error(t, err)
+}
+
+func TestLoadFile_ReportsInvalidValues(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[timer]\nwork = \"-5m\"\n"), 0644))
+
+	_, err := LoadFile(configFile)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timer.work")
+}
+
+func TestLoadFile_TimerUIAndKeys(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	configContent := `[timer]
+work = "50m"
+short_break = "10m"
+cycle = 3
+
+[ui]
+theme = "mono"
+
+[keys]
+toggle = ["space", "p"]
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(configContent), 0644))
+
+	cfg, err := LoadFile(configFile)
+
+	require.NoError(t, err)
+	assert.Equal(t, 50*time.Minute, cfg.Timer.Work)
+	assert.Equal(t, 10*time.Minute, cfg.Timer.ShortBreak)
+	assert.Equal(t, 3, cfg.Timer.Cycle)
+	assert.Equal(t, "mono", cfg.UI.Theme)
+	assert.Equal(t, []string{"space", "p"}, cfg.Keys.Toggle)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+
+	cfg := DefaultConfig()
+	cfg.Timer.Cycle = -1
+	cfg.Goals.WeekStart = "someday"
+	cfg.Keys.Quit = []string{" "}
+	cfg.Sound.Volume = 120
+	cfg.Notifications.Terminal = "osc1337"
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "notifications.terminal")
+	assert.Contains(t, err.Error(), "sound.volume")
+	assert.Contains(t, err.Error(), "timer.cycle")
+	assert.Contains(t, err.Error(), "goals.week_start")
+	assert.Contains(t, err.Error(), "keys.quit")
+}
+
+func TestSave_KeepsEditsMadeOnDisk(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[notifications]\nterminal_bell = true\n\n[goals]\ndaily_pomodoros = 8\n"), 0644))
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	// Edited on disk while running
+	require.NoError(t, os.WriteFile(configFile, []byte("[notifications]\nterminal_bell = true\n\n[goals]\ndaily_pomodoros = 10\n"), 0644))
+	// Changed in the app
+	cfg.Notifications.TerminalBell = false
+
+	require.NoError(t, cfg.Save())
+
+	saved, err := LoadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, 10, saved.Goals.DailyPomodoros, "edit on disk should be kept")
+	assert.False(t, saved.Notifications.TerminalBell, "change in the app should be saved")
+	assert.Equal(t, 10, cfg.Goals.DailyPomodoros, "in-memory config should pick up the edit")
+}
+
+func TestSave_DoesNotOverwriteInvalidFile(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[goals]\ndaily_pomodoros = 8\n"), 0644))
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	broken := []byte("[goals\ndaily_pomodoros = 10\n")
+	require.NoError(t, os.WriteFile(configFile, broken, 0644))
+
+	assert.Error(t, cfg.Save())
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, broken, content)
+}
+
+func TestSave_DoesNotOverwriteFileThatFailedValidation(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	invalid := []byte(fmt.Sprintf("version = %d\n\n[timer]\nshort_break = \"7m\"\n\n[goals]\ndaily_pomodoros = 10\nweek_start = \"someday\"\n", CurrentVersion()))
+	require.NoError(t, os.WriteFile(configFile, invalid, 0644))
+	cfg, err := Load()
+	require.Error(t, err)
+	require.Equal(t, DefaultConfig().Timer.ShortBreak, cfg.Timer.ShortBreak, "runs with defaults")
+
+	assert.ErrorContains(t, cfg.Save(), "not overwriting")
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, invalid, content)
+}
+
+func TestWatcher_Changed(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[goals]\ndaily_pomodoros = 8\n"), 0644))
+	w := NewWatcher(configFile)
+
+	assert.False(t, w.Changed(), "unchanged file")
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[goals]\ndaily_pomodoros = 12\n"), 0644))
+	assert.True(t, w.Changed(), "edited file")
+	assert.False(t, w.Changed(), "change is reported once")
+
+	// Touching the file without changing it is not a change
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(configFile, later, later))
+	assert.False(t, w.Changed())
+}
+
+func TestWatcher_MissingFile(t *testing.T) {
+	w := NewWatcher(filepath.Join(t.TempDir(), "config.toml"))
+
+	assert.False(t, w.Changed())
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CredentialsFile holds secrets next to the config file, so the config
+// can be shared or kept in dotfiles without them
+const CredentialsFile = "credentials.toml"
+
+// Credentials are the tokens integrations sign in with
+type Credentials struct {
+	Slack      TokenCredentials `toml:"slack"`
+	Mattermost TokenCredentials `toml:"mattermost"`
+	MQTT       LoginCredentials `toml:"mqtt"`
+}
+
+// TokenCredentials is an API token for one service
+type TokenCredentials struct {
+	Token string `toml:"token"`
+}
+
+// LoginCredentials is a username and password for one service
+type LoginCredentials struct {
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+}
+
+// CredentialsPath returns the credentials file beside the config file
+func CredentialsPath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), CredentialsFile), nil
+}
+
+// LoadCredentials reads the credentials file; a missing file has none
+func LoadCredentials() (*Credentials, error) {
+	path, err := CredentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadCredentialsFile(path)
+}
+
+// LoadCredentialsFile reads credentials from path
+func LoadCredentialsFile(path string) (*Credentials, error) {
+	var creds Credentials
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &creds, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toml.Decode(string(data), &creds); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return &creds, nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	dir := t.TempDir()
+	SetPath(filepath.Join(dir, "config.toml"))
+	defer SetPath("")
+
+	creds, err := LoadCredentials()
+	require.NoError(t, err)
+	assert.Empty(t, creds.Slack.Token, "no file means no credentials")
+
+	path, err := CredentialsPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "credentials.toml"), path)
+	require.NoError(t, os.WriteFile(path, []byte("[slack]\ntoken = \"xoxp-1\"\n\n[mattermost]\ntoken = \"mm\"\n"), 0600))
+
+	creds, err = LoadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "xoxp-1", creds.Slack.Token)
+	assert.Equal(t, "mm", creds.Mattermost.Token)
+}
+
+func TestLoadCredentialsFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[slack\n"), 0600))
+
+	_, err := LoadCredentialsFile(path)
+	assert.ErrorContains(t, err, "credentials.toml")
+}
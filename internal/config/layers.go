@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source says which layer a setting's value came from
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// EnvPrefix starts the environment variables that override settings
+const EnvPrefix = "POMODORO_"
+
+// Override sets one setting from outside the config file
+type Override struct {
+	Key    string // Dotted key, e.g. "timer.work"
+	Value  string
+	Source Source
+}
+
+// applied records the value an override set, so Save can tell whether
+// the app changed it since
+type applied struct {
+	source Source
+	value  any
+}
+
+// overrides are applied on top of the config file by Load and LoadFile
+var overrides []Override
+
+// SetOverrides sets the values Load and LoadFile apply on top of the
+// config file, later ones winning
+// Every override is checked first and none are kept if one is invalid
+func SetOverrides(o []Override) error {
+	probe := DefaultConfig()
+	source := map[string]Source{}
+	for _, override := range o {
+		if err := probe.set(override.Key, override.Value); err != nil {
+			return fmt.Errorf("%s %s: %w", override.Source, override.Key, err)
+		}
+		source[override.Key] = override.Source
+	}
+	for _, issue := range probe.validate() {
+		if s, ok := source[issue.Key]; ok {
+			return fmt.Errorf("%s %s: %s", s, issue.Key, issue.Message)
+		}
+	}
+	overrides = o
+	return nil
+}
+
+// Keys lists the dotted key of every setting that can be overridden:
+// those in sections
+// The top-level version and profile keys and the webhooks and profiles
+// tables are left out; version describes the file itself, --profile
+// picks the profile, and lists of tables have no single value to set
+func Keys() []string {
+	var keys []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i)
+		if !section.IsExported() || section.Type.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < section.Type.NumField(); j++ {
+			keys = append(keys, tomlName(section)+"."+tomlName(section.Type.Field(j)))
+		}
+	}
+	return keys
+}
+
+// EnvName returns the environment variable that overrides a key
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// EnvOverrides picks the overrides out of environment entries in
+// "NAME=value" form
+func EnvOverrides(environ []string) []Override {
+	byName := map[string]string{}
+	for _, key := range Keys() {
+		byName[EnvName(key)] = key
+	}
+	var found []Override
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if key, known := byName[name]; ok && known {
+			found = append(found, Override{Key: key, Value: value, Source: SourceEnv})
+		}
+	}
+	return found
+}
+
+// withOverrides applies the overrides to cfg and returns it
+func withOverrides(cfg *Config) *Config {
+	for _, o := range overrides {
+		if err := cfg.set(o.Key, o.Value); err != nil {
+			continue
+		}
+		if cfg.overrides == nil {
+			cfg.overrides = map[string]applied{}
+		}
+		cfg.overrides[o.Key] = applied{source: o.Source, value: cfg.field(o.Key).Interface()}
+	}
+	return cfg
+}
+
+// withoutOverrides returns a copy of c with every setting an override
+// still holds reset to its value in file, or to the default without one
+func (c *Config) withoutOverrides(file *Config) *Config {
+	out := *c
+	if len(c.overrides) == 0 {
+		return &out
+	}
+	if file == nil {
+		file = DefaultConfig()
+	}
+	for key, a := range c.overrides {
+		if reflect.DeepEqual(c.field(key).Interface(), a.value) {
+			out.field(key).Set(file.field(key))
+		}
+	}
+	return &out
+}
+
+// Source returns the layer key's current value came from
+func (c *Config) Source(key string) Source {
+	if a, ok := c.overrides[key]; ok && reflect.DeepEqual(c.field(key).Interface(), a.value) {
+		return a.source
+	}
+	if c.fileKeys[key] {
+		return SourceFile
+	}
+	return SourceDefault
+}
+
+// Value returns key's current value as TOML
+func (c *Config) Value(key string) string {
+	f := c.field(key)
+	if !f.IsValid() {
+		return ""
+	}
+	return literal(f.Interface())
+}
+
+// field returns the settable struct field for a dotted key
+func (c *Config) field(key string) reflect.Value {
+	sectionName, name, ok := strings.Cut(key, ".")
+	if !ok {
+		return reflect.Value{}
+	}
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		section := v.Type().Field(i)
+		if !section.IsExported() || section.Type.Kind() != reflect.Struct || tomlName(section) != sectionName {
+			continue
+		}
+		for j := 0; j < section.Type.NumField(); j++ {
+			if tomlName(section.Type.Field(j)) == name {
+				return v.Field(i).Field(j)
+			}
+		}
+	}
+	return reflect.Value{}
+}
+
+// set parses value into the setting for key
+// Lists are comma separated
+func (c *Config) set(key, value string) error {
+	f := c.field(key)
+	if !f.IsValid() {
+		return fmt.Errorf("unknown setting")
+	}
+	value = strings.TrimSpace(value)
+
+	switch f.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", value)
+		}
+		f.Set(reflect.ValueOf(d))
+	case string:
+		f.SetString(value)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		f.SetBool(b)
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		f.SetInt(int64(n))
+	case []string:
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		f.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("can't be overridden")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withTestOverrides sets overrides for a test
+func withTestOverrides(t *testing.T, o ...Override) {
+	t.Helper()
+	require.NoError(t, SetOverrides(o))
+	t.Cleanup(func() { overrides = nil })
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+
+	assert.Contains(t, keys, "timer.work")
+	assert.Contains(t, keys, "notifications.terminal_bell")
+	assert.Contains(t, keys, "keys.quit")
+	assert.NotContains(t, keys, "version")
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "POMODORO_TIMER_SHORT_BREAK", EnvName("timer.short_break"))
+}
+
+func TestEnvOverrides(t *testing.T) {
+	found := EnvOverrides([]string{
+		"HOME=/home/me",
+		"POMODORO_TIMER_WORK=50m",
+		"POMODORO_UNKNOWN=1",
+		"POMODORO_TASKS_MARKDOWN=a.md, b.md",
+	})
+
+	assert.Equal(t, []Override{
+		{Key: "timer.work", Value: "50m", Source: SourceEnv},
+		{Key: "tasks.markdown", Value: "a.md, b.md", Source: SourceEnv},
+	}, found)
+}
+
+func TestSetOverrides_Invalid(t *testing.T) {
+	defer func() { overrides = nil }()
+
+	err := SetOverrides([]Override{{Key: "timer.work", Value: "soon", Source: SourceFlag}})
+	assert.EqualError(t, err, `flag timer.work: "soon" is not a duration`)
+
+	err = SetOverrides([]Override{{Key: "timer.cycle", Value: "-1", Source: SourceEnv}})
+	assert.EqualError(t, err, "env timer.cycle: must not be negative")
+
+	err = SetOverrides([]Override{{Key: "timer.nope", Value: "1", Source: SourceFlag}})
+	assert.Error(t, err)
+	assert.Empty(t, overrides, "nothing is kept after an error")
+}
+
+func TestLoad_Layers(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[timer]\nwork = \"40m\"\nshort_break = \"8m\"\n\n[tasks]\ntaskwarrior = true\n"), 0644))
+	withTestOverrides(t,
+		Override{Key: "timer.short_break", Value: "7m", Source: SourceEnv},
+		Override{Key: "tasks.taskwarrior", Value: "false", Source: SourceEnv},
+		Override{Key: "tasks.taskwarrior", Value: "true", Source: SourceFlag},
+		Override{Key: "tasks.markdown", Value: "a.md,b.md", Source: SourceFlag},
+	)
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, 40*time.Minute, cfg.Timer.Work)
+	assert.Equal(t, SourceFile, cfg.Source("timer.work"))
+	assert.Equal(t, 7*time.Minute, cfg.Timer.ShortBreak)
+	assert.Equal(t, SourceEnv, cfg.Source("timer.short_break"))
+	assert.True(t, cfg.Tasks.Taskwarrior, "flags win over the environment")
+	assert.Equal(t, SourceFlag, cfg.Source("tasks.taskwarrior"))
+	assert.Equal(t, []string{"a.md", "b.md"}, cfg.Tasks.Markdown)
+	assert.Equal(t, 15*time.Minute, cfg.Timer.LongBreak)
+	assert.Equal(t, SourceDefault, cfg.Source("timer.long_break"))
+	assert.Equal(t, `"7m"`, cfg.Value("timer.short_break"))
+}
+
+func TestSave_LeavesOverridesOutOfFile(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	original := "[timer]\nwork = \"40m\"\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(original), 0644))
+	withTestOverrides(t,
+		Override{Key: "timer.work", Value: "50m", Source: SourceEnv},
+		Override{Key: "timer.cycle", Value: "2", Source: SourceFlag},
+	)
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.Save())
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `work = "40m"`)
+	assert.NotContains(t, string(content), "cycle")
+	assert.Equal(t, 50*time.Minute, cfg.Timer.Work, "override stays in effect")
+}
+
+func TestSave_KeepsChangeToOverriddenSetting(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[notifications]\nterminal_bell = true\n"), 0644))
+	withTestOverrides(t, Override{Key: "notifications.terminal_bell", Value: "true", Source: SourceFlag})
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	// Toggled in the app after launch
+	cfg.Notifications.TerminalBell = false
+	require.NoError(t, cfg.Save())
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "terminal_bell = false")
+}
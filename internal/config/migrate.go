@@ -0,0 +1,141 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+// migrations upgrade a decoded config file one version at a time;
+// migrations[i] takes a version i file to version i+1
+var migrations = []func(raw map[string]any) error{
+	// 0 → 1: files written before versioning already have the version 1
+	// layout and only gain the version field
+	func(map[string]any) error { return nil },
+}
+
+// CurrentVersion returns the config layout version this build writes
+func CurrentVersion() int {
+	return len(migrations)
+}
+
+// fileVersion returns the version a decoded config file declares
+func fileVersion(raw map[string]any) int {
+	v, _ := raw["version"].(int64)
+	return int(v)
+}
+
+// migrate upgrades a decoded config file to the current version in place
+func migrate(raw map[string]any) error {
+	version := fileVersion(raw)
+	if version > CurrentVersion() {
+		return fmt.Errorf("version %d is newer than this pomodoro supports (%d)", version, CurrentVersion())
+	}
+	for v := version; v < CurrentVersion(); v++ {
+		if err := migrations[v](raw); err != nil {
+			return fmt.Errorf("upgrading from version %d: %w", v, err)
+		}
+	}
+	raw["version"] = int64(CurrentVersion())
+	return nil
+}
+
+// migrated returns config file content upgraded to the current version,
+// or data itself if it is current already
+func migrated(data []byte) ([]byte, error) {
+	var raw map[string]any
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, err
+	}
+	if fileVersion(raw) == CurrentVersion() {
+		return data, nil
+	}
+	if err := migrate(raw); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBackup writes the copy kept of a file before upgrading it; tests
+// replace it to make the backup fail
+var writeBackup = writeFileAtomic
+
+// upgrade rewrites an older config file in the current layout, keeping a
+// copy of the original next to it as config.toml.v<N>.bak
+// Settings the migrations didn't touch keep their comments and ordering
+func upgrade(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var before map[string]any
+	if _, err := toml.Decode(string(data), &before); err != nil {
+		return err
+	}
+	version := fileVersion(before)
+	if version >= CurrentVersion() {
+		return nil
+	}
+
+	var after map[string]any
+	if _, err := toml.Decode(string(data), &after); err != nil {
+		return err
+	}
+	if err := migrate(after); err != nil {
+		return err
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if _, err := os.Stat(backup); os.IsNotExist(err) {
+		if err := writeBackup(backup, data); err != nil {
+			return fmt.Errorf("backup: %w", err)
+		}
+	}
+
+	text := string(data)
+	oldKeys, newKeys := flatten(before), flatten(after)
+	for _, key := range sortedKeys(oldKeys) {
+		if _, ok := newKeys[key]; !ok {
+			text = deleteKey(text, key)
+		}
+	}
+	for _, key := range sortedKeys(newKeys) {
+		if old, ok := oldKeys[key]; !ok || !reflect.DeepEqual(old, newKeys[key]) {
+			text = setKey(text, key, literal(newKeys[key]))
+		}
+	}
+	return writeFileAtomic(path, []byte(text))
+}
+
+// flatten maps the dotted key of every setting in a decoded file to its value
+func flatten(raw map[string]any) map[string]any {
+	flat := map[string]any{}
+	for key, value := range raw {
+		if table, ok := value.(map[string]any); ok {
+			for k, v := range flatten(table) {
+				flat[key+"."+k] = v
+			}
+			continue
+		}
+		flat[key] = value
+	}
+	return flat
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
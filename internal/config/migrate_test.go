@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withMigrations replaces the migration chain for a test
+func withMigrations(t *testing.T, chain ...func(map[string]any) error) {
+	t.Helper()
+	saved := migrations
+	migrations = chain
+	t.Cleanup(func() { migrations = saved })
+}
+
+func TestCurrentVersion(t *testing.T) {
+	assert.Equal(t, len(migrations), CurrentVersion())
+	assert.Equal(t, CurrentVersion(), DefaultConfig().Version)
+}
+
+func TestLoad_UpgradesOldFile(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	// Version 2 renames goals.daily to goals.daily_pomodoros
+	withMigrations(t,
+		func(map[string]any) error { return nil },
+		func(raw map[string]any) error {
+			goals, _ := raw["goals"].(map[string]any)
+			if daily, ok := goals["daily"]; ok {
+				goals["daily_pomodoros"] = daily
+				delete(goals, "daily")
+			}
+			return nil
+		},
+	)
+	original := "# keep me\n[goals]\ndaily = 6 # six a day\nday_start = \"05:00\" # early\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(original), 0644))
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, cfg.Goals.DailyPomodoros)
+	assert.Equal(t, 2, cfg.Version)
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "# keep me\nversion = 2\n\n[goals]\nday_start = \"05:00\" # early\ndaily_pomodoros = 6\n", string(content))
+
+	backup, err := os.ReadFile(configFile + ".v0.bak")
+	require.NoError(t, err)
+	assert.Equal(t, original, string(backup))
+}
+
+func TestLoad_ReportsFailedUpgrade(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+	withMigrations(t,
+		func(map[string]any) error { return nil },
+		func(map[string]any) error { return nil },
+	)
+	writeBackup = func(string, []byte) error { return errors.New("disk full") }
+	t.Cleanup(func() { writeBackup = writeFileAtomic })
+
+	original := "version = 1\n\n[goals]\ndaily_pomodoros = 6\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(original), 0644))
+
+	cfg, err := Load()
+
+	var fileErr *FileError
+	require.ErrorAs(t, err, &fileErr)
+	assert.True(t, fileErr.Applied(), "the file is still used")
+	assert.Contains(t, err.Error(), "1:1: warning: version: not upgraded")
+	assert.Contains(t, err.Error(), "backup: disk full")
+	assert.Equal(t, 6, cfg.Goals.DailyPomodoros)
+
+	assert.ErrorContains(t, cfg.Save(), "not overwriting")
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(content), "the old file is left as it was")
+}
+
+func TestLoad_CurrentFileUntouched(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	original := "version = 1\n\n[goals]\ndaily_pomodoros = 6\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(original), 0644))
+
+	_, err := Load()
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(content))
+	_, err = os.Stat(configFile + ".v1.bak")
+	assert.True(t, os.IsNotExist(err), "no backup without an upgrade")
+}
+
+func TestCheck_NewerVersion(t *testing.T) {
+	issues := Check([]byte("version = 99\n"))
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "version", issues[0].Key)
+	assert.Equal(t, 1, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "newer")
+}
+
+func TestMigrate_RunsChainInOrder(t *testing.T) {
+	var order []int
+	withMigrations(t,
+		func(map[string]any) error { order = append(order, 0); return nil },
+		func(map[string]any) error { order = append(order, 1); return nil },
+		func(map[string]any) error { order = append(order, 2); return nil },
+	)
+	raw := map[string]any{"version": int64(1)}
+
+	require.NoError(t, migrate(raw))
+
+	assert.Equal(t, []int{1, 2}, order)
+	assert.Equal(t, int64(3), raw["version"])
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SourceProfile marks settings set by the active profile
+const SourceProfile Source = "profile"
+
+// Profile bundles settings for one way of working, e.g. coding or study
+// Anything left unset keeps the value from the rest of the config
+type Profile struct {
+	Timer         TimerConfig          `toml:"timer"`
+	Notifications ProfileNotifications `toml:"notifications"`
+	Theme         string               `toml:"theme"`
+	Hooks         HookConfig           `toml:"hooks"`
+}
+
+// ProfileNotifications switches notification channels for a profile
+type ProfileNotifications struct {
+	VisualFlash        *bool `toml:"visual_flash"`
+	TerminalBell       *bool `toml:"terminal_bell"`
+	SystemNotification *bool `toml:"system_notification"`
+	Sound              *bool `toml:"sound"` // Sets sound.enabled
+}
+
+// profileOverride is the profile chosen with --profile
+var profileOverride string
+
+// SetProfile makes Load and LoadFile start with the named profile instead
+// of the one the config file names
+func SetProfile(name string) {
+	profileOverride = name
+}
+
+// ProfileNames lists the configured profiles in order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveProfile returns the name of the profile in use, empty for none
+func (c *Config) ActiveProfile() string {
+	return c.active
+}
+
+// UseProfile switches to the named profile, or to none if name is empty
+// Settings overridden by environment variables or flags are left alone
+func (c *Config) UseProfile(name string) error {
+	var profile Profile
+	if name != "" {
+		var ok bool
+		if profile, ok = c.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+	}
+
+	// Undo the current profile
+	file := c.base
+	if file == nil {
+		file = DefaultConfig()
+	}
+	for key, a := range c.overrides {
+		if a.source != SourceProfile {
+			continue
+		}
+		if reflect.DeepEqual(c.field(key).Interface(), a.value) {
+			c.field(key).Set(file.field(key))
+		}
+		delete(c.overrides, key)
+	}
+
+	for key, value := range profile.settings() {
+		if _, overridden := c.overrides[key]; overridden {
+			continue
+		}
+		c.field(key).Set(reflect.ValueOf(value))
+		if c.overrides == nil {
+			c.overrides = map[string]applied{}
+		}
+		c.overrides[key] = applied{source: SourceProfile, value: value}
+	}
+	c.active = name
+	return nil
+}
+
+// settings maps the keys a profile sets to their values
+func (p Profile) settings() map[string]any {
+	set := map[string]any{}
+	if p.Timer.Work > 0 {
+		set["timer.work"] = p.Timer.Work
+	}
+	if p.Timer.ShortBreak > 0 {
+		set["timer.short_break"] = p.Timer.ShortBreak
+	}
+	if p.Timer.LongBreak > 0 {
+		set["timer.long_break"] = p.Timer.LongBreak
+	}
+	if p.Timer.Cycle > 0 {
+		set["timer.cycle"] = p.Timer.Cycle
+	}
+	if p.Notifications.VisualFlash != nil {
+		set["notifications.visual_flash"] = *p.Notifications.VisualFlash
+	}
+	if p.Notifications.TerminalBell != nil {
+		set["notifications.terminal_bell"] = *p.Notifications.TerminalBell
+	}
+	if p.Notifications.SystemNotification != nil {
+		set["notifications.system_notification"] = *p.Notifications.SystemNotification
+	}
+	if p.Notifications.Sound != nil {
+		set["sound.enabled"] = *p.Notifications.Sound
+	}
+	if p.Theme != "" {
+		set["ui.theme"] = p.Theme
+	}
+	if p.Hooks.SessionStart != "" {
+		set["hooks.session_start"] = p.Hooks.SessionStart
+	}
+	if p.Hooks.SessionEnd != "" {
+		set["hooks.session_end"] = p.Hooks.SessionEnd
+	}
+	if p.Hooks.Reminder != "" {
+		set["hooks.reminder"] = p.Hooks.Reminder
+	}
+	return set
+}
+
+// withProfile applies the launch profile to cfg
+// A profile that doesn't exist is reported and none is used
+func withProfile(cfg *Config) error {
+	name := cfg.DefaultProfile
+	if profileOverride != "" {
+		name = profileOverride
+	}
+	if name == "" {
+		return nil
+	}
+	return cfg.UseProfile(name)
+}
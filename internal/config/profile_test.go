@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesConfig = `profile = "coding"
+
+[timer]
+work = "25m"
+short_break = "5m"
+
+[notifications]
+terminal_bell = true
+
+[profiles.coding.timer]
+work = "50m"
+short_break = "10m"
+
+[profiles.coding.notifications]
+terminal_bell = false
+sound = true
+
+[profiles.study]
+theme = "solarized"
+
+[profiles.study.timer]
+work = "45m"
+short_break = "15m"
+cycle = 2
+
+[profiles.study.hooks]
+session_end = "echo done"
+`
+
+func TestLoad_LaunchProfile(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+	require.NoError(t, os.WriteFile(configFile, []byte(profilesConfig), 0644))
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "coding", cfg.ActiveProfile())
+	assert.Equal(t, 50*time.Minute, cfg.Timer.Work)
+	assert.False(t, cfg.Notifications.TerminalBell)
+	assert.True(t, cfg.Sound.Enabled)
+	assert.Equal(t, SourceProfile, cfg.Source("timer.work"))
+	assert.Equal(t, []string{"coding", "study"}, cfg.ProfileNames())
+}
+
+func TestLoad_ProfileFromFlag(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+	require.NoError(t, os.WriteFile(configFile, []byte(profilesConfig), 0644))
+	SetProfile("study")
+	defer SetProfile("")
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "study", cfg.ActiveProfile())
+	assert.Equal(t, 45*time.Minute, cfg.Timer.Work)
+	assert.Equal(t, 2, cfg.Timer.Cycle)
+	assert.Equal(t, "solarized", cfg.UI.Theme)
+	assert.Equal(t, "echo done", cfg.Hooks.SessionEnd)
+	assert.True(t, cfg.Notifications.TerminalBell, "unset channels keep the file's value")
+}
+
+func TestUseProfile_Switches(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+	require.NoError(t, os.WriteFile(configFile, []byte(profilesConfig), 0644))
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.UseProfile("study"))
+	assert.Equal(t, 45*time.Minute, cfg.Timer.Work)
+	assert.True(t, cfg.Notifications.TerminalBell, "coding's setting is undone")
+
+	require.NoError(t, cfg.UseProfile(""))
+	assert.Equal(t, "", cfg.ActiveProfile())
+	assert.Equal(t, 25*time.Minute, cfg.Timer.Work)
+	assert.Equal(t, 4, cfg.Timer.Cycle)
+	assert.Equal(t, "neon", cfg.UI.Theme)
+	assert.Empty(t, cfg.Hooks.SessionEnd)
+
+	assert.Error(t, cfg.UseProfile("gaming"))
+}
+
+func TestUseProfile_OverridesWin(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+	require.NoError(t, os.WriteFile(configFile, []byte(profilesConfig), 0644))
+	withTestOverrides(t, Override{Key: "timer.work", Value: "30m", Source: SourceEnv})
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, cfg.Timer.Work)
+	assert.Equal(t, 10*time.Minute, cfg.Timer.ShortBreak)
+	assert.Equal(t, SourceEnv, cfg.Source("timer.work"))
+}
+
+func TestSave_LeavesProfileOutOfFile(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+	require.NoError(t, os.WriteFile(configFile, []byte(profilesConfig), 0644))
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.Save())
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	expected := strings.Replace(profilesConfig, "\n[timer]", "\nversion = 1\n\n[timer]", 1)
+	assert.Equal(t, expected, string(content), "only the version is added")
+}
+
+func TestCheck_UnknownLaunchProfile(t *testing.T) {
+	issues := Check([]byte("profile = \"gaming\"\n"))
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "profile", issues[0].Key)
+	assert.Equal(t, 1, issues[0].Line)
+}
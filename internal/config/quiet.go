@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// QuietChannels are the notification channels quiet hours can mute
+var QuietChannels = []string{"bell", "system", "sound", "terminal", "push"}
+
+// QuietHoursConfig mutes notification channels on a schedule or while do
+// not disturb is switched on
+// The timer, its view and the history carry on as usual
+type QuietHoursConfig struct {
+	Schedule []string `toml:"schedule"` // e.g. "22:00-07:00" or "mon-fri 12:00-13:00"
+	Mute     []string `toml:"mute"`     // Channels to mute, from QuietChannels
+}
+
+// quietPeriod is one parsed schedule entry
+// A period whose end is not after its start runs past midnight into the
+// next day, and the days are the ones it starts on
+type quietPeriod struct {
+	days       [7]bool
+	start, end time.Duration // Since midnight
+}
+
+// Active returns whether t falls in a scheduled quiet period
+// Entries that don't parse are ignored; Validate reports them
+func (q QuietHoursConfig) Active(t time.Time) bool {
+	since := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	today := t.Weekday()
+	yesterday := (today + 6) % 7
+	for _, entry := range q.Schedule {
+		p, err := parseQuietPeriod(entry)
+		if err != nil {
+			continue
+		}
+		if p.start < p.end {
+			if p.days[today] && since >= p.start && since < p.end {
+				return true
+			}
+			continue
+		}
+		if (p.days[today] && since >= p.start) || (p.days[yesterday] && since < p.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mutes returns whether channel is silenced during quiet hours
+func (q QuietHoursConfig) Mutes(channel string) bool {
+	return slices.ContainsFunc(q.Mute, func(c string) bool {
+		return strings.EqualFold(strings.TrimSpace(c), channel)
+	})
+}
+
+// parseQuietPeriod parses "[days ]HH:MM-HH:MM", where days is a comma
+// separated list of weekdays or ranges such as "mon-fri", every day if left
+// out, and the end may be 24:00
+func parseQuietPeriod(s string) (quietPeriod, error) {
+	var p quietPeriod
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return p, fmt.Errorf("want [days] HH:MM-HH:MM")
+	}
+
+	if len(fields) == 2 {
+		for _, part := range strings.Split(strings.ToLower(fields[0]), ",") {
+			from, to, isRange := strings.Cut(part, "-")
+			first, err := parseWeekday(from)
+			if err != nil {
+				return p, err
+			}
+			last := first
+			if isRange {
+				if last, err = parseWeekday(to); err != nil {
+					return p, err
+				}
+			}
+			for d := first; ; d = (d + 1) % 7 {
+				p.days[d] = true
+				if d == last {
+					break
+				}
+			}
+		}
+	} else {
+		p.days = [7]bool{true, true, true, true, true, true, true}
+	}
+
+	from, to, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return p, fmt.Errorf("want [days] HH:MM-HH:MM")
+	}
+	var err error
+	if p.start, err = parseClock(from); err != nil {
+		return p, err
+	}
+	if p.end, err = parseClock(to); err != nil {
+		return p, err
+	}
+	if p.start == 24*time.Hour {
+		return p, fmt.Errorf("start %s: want 00:00 to 23:59", from)
+	}
+	return p, nil
+}
+
+// parseWeekday accepts full and three-letter weekday names
+func parseWeekday(s string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("%q: want a weekday name", s)
+}
+
+// parseClock parses "HH:MM" as the time since midnight, allowing 24:00
+func parseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q: want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
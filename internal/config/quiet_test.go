@@ -0,0 +1,83 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// at returns the given local time on the week of 2024-06-03, a Monday
+func at(day time.Weekday, clock string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", "2024-06-03 "+clock, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t.AddDate(0, 0, (int(day)+6)%7)
+}
+
+func TestQuietHours_Active(t *testing.T) {
+	q := QuietHoursConfig{Schedule: []string{"22:00-07:00", "mon-fri 12:00-13:00"}}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"late evening", at(time.Wednesday, "22:30"), true},
+		{"early morning", at(time.Thursday, "06:59"), true},
+		{"morning", at(time.Thursday, "07:00"), false},
+		{"weekday lunch", at(time.Friday, "12:15"), true},
+		{"weekend lunch", at(time.Saturday, "12:15"), false},
+		{"afternoon", at(time.Monday, "15:00"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, q.Active(tt.t))
+		})
+	}
+}
+
+func TestQuietHours_OvernightBelongsToStartDay(t *testing.T) {
+	q := QuietHoursConfig{Schedule: []string{"fri 23:00-02:00"}}
+
+	assert.True(t, q.Active(at(time.Friday, "23:30")))
+	assert.True(t, q.Active(at(time.Saturday, "01:00")))
+	assert.False(t, q.Active(at(time.Thursday, "01:00")))
+	assert.False(t, q.Active(at(time.Saturday, "23:30")))
+}
+
+func TestQuietHours_WholeDays(t *testing.T) {
+	q := QuietHoursConfig{Schedule: []string{"sat,sunday 00:00-24:00"}}
+
+	assert.True(t, q.Active(at(time.Sunday, "09:00")))
+	assert.False(t, q.Active(at(time.Monday, "09:00")))
+}
+
+func TestQuietHours_Mutes(t *testing.T) {
+	q := QuietHoursConfig{Mute: []string{"bell", " Sound"}}
+
+	assert.True(t, q.Mutes("bell"))
+	assert.True(t, q.Mutes("sound"))
+	assert.False(t, q.Mutes("system"))
+}
+
+func TestParseQuietPeriod_Errors(t *testing.T) {
+	for _, entry := range []string{"", "22:00", "someday 10:00-11:00", "10:00-25:00", "24:00-01:00", "mon 10:00-11:00 extra"} {
+		_, err := parseQuietPeriod(entry)
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestValidate_QuietHours(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.QuietHours.Schedule = []string{"weekdays 22:00-07:00"}
+	cfg.QuietHours.Mute = []string{"bell", "email"}
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quiet_hours.schedule")
+	assert.Contains(t, err.Error(), `"email"`)
+}
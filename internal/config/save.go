@@ -0,0 +1,305 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// writeFileAtomic replaces path with data so that a crash leaves either
+// the old or the new file, never a partial one
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".config-*.toml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Make the rename itself durable
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil
+	}
+	defer d.Close()
+	_ = d.Sync()
+	return nil
+}
+
+// patch rewrites only the settings in which c differs from disk, keeping
+// the rest of the file text, comments and ordering as they are
+func patch(data []byte, c, disk *Config) []byte {
+	text := string(data)
+	mine, theirs := reflect.ValueOf(c).Elem(), reflect.ValueOf(disk).Elem()
+	for i := 0; i < mine.NumField(); i++ {
+		field := mine.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := tomlName(field)
+		if field.Type.Kind() == reflect.Map || field.Type.Kind() == reflect.Slice {
+			// Profiles and webhooks are only ever edited in the file
+			continue
+		}
+		if field.Type.Kind() != reflect.Struct {
+			if !reflect.DeepEqual(mine.Field(i).Interface(), theirs.Field(i).Interface()) {
+				text = setKey(text, name, literal(mine.Field(i).Interface()))
+			}
+			continue
+		}
+		section, diskSection := mine.Field(i), theirs.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			value := section.Field(j).Interface()
+			if reflect.DeepEqual(value, diskSection.Field(j).Interface()) {
+				continue
+			}
+			text = setKey(text, name+"."+tomlName(section.Type().Field(j)), literal(value))
+		}
+	}
+	return []byte(text)
+}
+
+// literal encodes a single value as TOML
+func literal(value any) string {
+	if d, ok := value.(time.Duration); ok {
+		// "25m" rather than "25m0s"
+		text := d.String()
+		if strings.HasSuffix(text, "m0s") {
+			text = strings.TrimSuffix(text, "0s")
+		}
+		if strings.HasSuffix(text, "h0m") {
+			text = strings.TrimSuffix(text, "0m")
+		}
+		return strconv.Quote(text)
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice && v.Len() == 0 {
+		return "[]"
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]any{"v": value}); err != nil {
+		return `""`
+	}
+	return strings.TrimSpace(strings.TrimPrefix(buf.String(), "v = "))
+}
+
+// keyLines locates a key = value entry in the file text
+type keyLines struct {
+	key        string // Dotted key including its table
+	table      string
+	start, end int // First and last line of the entry
+	valueAt    int // Byte offset of the value in the first line
+	column     int // 1-based column of the key
+}
+
+// tableHeader locates a [table] header in the file text
+type tableHeader struct {
+	name   string
+	line   int
+	column int
+}
+
+// scan finds the table headers and key entries in config file text
+// It understands the plain tables and keys this config uses, including
+// arrays that span several lines
+func scan(lines []string) ([]tableHeader, []keyLines) {
+	var headers []tableHeader
+	var entries []keyLines
+	table := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			table = strings.Trim(stripComment(trimmed), "[] \t")
+			headers = append(headers, tableHeader{name: table, line: i, column: indent + 1})
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			continue
+		}
+		name := strings.Trim(strings.TrimSpace(line[:eq]), `"'`)
+		if table != "" {
+			name = table + "." + name
+		}
+		entry := keyLines{key: name, table: table, start: i, end: i, valueAt: eq + 1, column: indent + 1}
+		depth := bracketDepth(line[eq+1:])
+		for depth > 0 && entry.end+1 < len(lines) {
+			entry.end++
+			depth += bracketDepth(lines[entry.end])
+		}
+		i = entry.end
+		entries = append(entries, entry)
+	}
+	return headers, entries
+}
+
+// bracketDepth returns how many arrays a line opens minus those it closes
+func bracketDepth(s string) int {
+	depth := 0
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return depth
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		}
+	}
+	return depth
+}
+
+// stripComment removes a trailing # comment outside of quotes
+func stripComment(s string) string {
+	if i := commentStart(s); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// commentStart returns the offset of a trailing # comment, or -1
+func commentStart(s string) int {
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return i
+		}
+	}
+	return -1
+}
+
+// setKey sets key to the TOML literal value in the file text
+// An existing entry keeps its position and trailing comment; a new one is
+// added at the end of its table, creating the table if needed
+func setKey(text, key, value string) string {
+	lines := strings.Split(text, "\n")
+	headers, entries := scan(lines)
+
+	for _, e := range entries {
+		if e.key != key {
+			continue
+		}
+		first := lines[e.start]
+		replacement := first[:e.valueAt] + " " + value
+		if e.start == e.end {
+			if c := commentStart(first[e.valueAt:]); c >= 0 {
+				rest := first[e.valueAt:]
+				before := rest[:c]
+				padding := before[len(strings.TrimRight(before, " \t")):]
+				if padding == "" {
+					padding = " "
+				}
+				replacement += padding + rest[c:]
+			}
+		}
+		lines = append(lines[:e.start], append([]string{replacement}, lines[e.end+1:]...)...)
+		return strings.Join(lines, "\n")
+	}
+
+	table, name := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		table, name = key[:i], key[i+1:]
+	}
+	entry := name + " = " + value
+
+	if table == "" {
+		// Top-level keys must come before the first table
+		at := len(lines)
+		if len(headers) > 0 {
+			at = headers[0].line
+			entry += "\n"
+		} else if at > 0 && lines[at-1] == "" {
+			at--
+		}
+		return insertLine(lines, at, entry)
+	}
+
+	at := -1
+	for _, h := range headers {
+		if h.name == table {
+			at = h.line + 1
+		}
+	}
+	if at < 0 {
+		text = strings.TrimRight(text, "\n")
+		if text != "" {
+			text += "\n\n"
+		}
+		return text + "[" + table + "]\n" + entry + "\n"
+	}
+	for _, e := range entries {
+		if e.table == table && e.end+1 > at {
+			at = e.end + 1
+		}
+	}
+	return insertLine(lines, at, entry)
+}
+
+// deleteKey removes a key's entry from the file text
+func deleteKey(text, key string) string {
+	lines := strings.Split(text, "\n")
+	_, entries := scan(lines)
+	for _, e := range entries {
+		if e.key == key {
+			lines = append(lines[:e.start], lines[e.end+1:]...)
+			return strings.Join(lines, "\n")
+		}
+	}
+	return text
+}
+
+// insertLine inserts text as a line before line at
+func insertLine(lines []string, at int, text string) string {
+	out := make([]string, 0, len(lines)+1)
+	out = append(out, lines[:at]...)
+	out = append(out, text)
+	out = append(out, lines[at:]...)
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSave_KeepsCommentsAndOrder(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	original := `# My pomodoro settings
+version = 1
+
+[goals]
+daily_pomodoros = 8 # a good day
+
+[notifications]
+visual_flash = true   # keep it subtle
+terminal_bell = true
+
+[tasks]
+markdown = [
+  "TODO.md",
+]
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(original), 0644))
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	cfg.Notifications.VisualFlash = false
+	cfg.Tasks.Markdown = []string{"TODO.md", "NOTES.md"}
+	cfg.Timer.Work = 50 * time.Minute
+	require.NoError(t, cfg.Save())
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, `# My pomodoro settings
+version = 1
+
+[goals]
+daily_pomodoros = 8 # a good day
+
+[notifications]
+visual_flash = false   # keep it subtle
+terminal_bell = true
+
+[tasks]
+markdown = ["TODO.md", "NOTES.md"]
+
+[timer]
+work = "50m"
+`, string(content))
+}
+
+func TestSave_Atomic(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[goals]\ndaily_pomodoros = 8\n"), 0600))
+	cfg, err := Load()
+	require.NoError(t, err)
+	cfg.Goals.DailyPomodoros = 10
+	require.NoError(t, cfg.Save())
+
+	entries, err := os.ReadDir(filepath.Dir(configFile))
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".config-", "temporary file should be gone")
+	}
+	info, err := os.Stat(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "file mode should be kept")
+}
+
+func TestSave_WritesVersion(t *testing.T) {
+	configFile, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, (&Config{}).Save())
+
+	cfg, err := LoadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion(), cfg.Version)
+}
+
+func TestSetKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		key      string
+		value    string
+		expected string
+	}{
+		{
+			"replace keeps comment",
+			"[ui]\ntheme = \"neon\" # bright\n",
+			"ui.theme", `"mono"`,
+			"[ui]\ntheme = \"mono\" # bright\n",
+		},
+		{
+			"append to existing table",
+			"[ui]\ntheme = \"neon\"\n\n[goals]\ndaily_pomodoros = 8\n",
+			"ui.extra", "1",
+			"[ui]\ntheme = \"neon\"\nextra = 1\n\n[goals]\ndaily_pomodoros = 8\n",
+		},
+		{
+			"new table",
+			"[ui]\ntheme = \"neon\"\n",
+			"timer.cycle", "3",
+			"[ui]\ntheme = \"neon\"\n\n[timer]\ncycle = 3\n",
+		},
+		{
+			"top-level key goes before tables",
+			"# header\n\n[ui]\ntheme = \"neon\"\n",
+			"version", "1",
+			"# header\n\nversion = 1\n\n[ui]\ntheme = \"neon\"\n",
+		},
+		{
+			"multi-line array",
+			"[tasks]\nmarkdown = [\n  \"a.md\", # first\n  \"b.md\",\n]\ntaskwarrior = true\n",
+			"tasks.markdown", `["c.md"]`,
+			"[tasks]\nmarkdown = [\"c.md\"]\ntaskwarrior = true\n",
+		},
+		{
+			"hash inside a string is not a comment",
+			"[keys]\nhelp = [\"#\"] # why not\n",
+			"keys.help", `["?"]`,
+			"[keys]\nhelp = [\"?\"] # why not\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, setKey(tt.text, tt.key, tt.value))
+		})
+	}
+}
+
+func TestDeleteKey(t *testing.T) {
+	text := "[tasks]\nmarkdown = [\n  \"a.md\",\n]\ntaskwarrior = true\n"
+
+	assert.Equal(t, "[tasks]\ntaskwarrior = true\n", deleteKey(text, "tasks.markdown"))
+	assert.Equal(t, text, deleteKey(text, "tasks.todo_txt"))
+}
+
+func TestLiteral(t *testing.T) {
+	assert.Equal(t, `"25m"`, literal(25*time.Minute))
+	assert.Equal(t, `"1h30m"`, literal(90*time.Minute))
+	assert.Equal(t, `"2h"`, literal(2*time.Hour))
+	assert.Equal(t, `"45s"`, literal(45*time.Second))
+	assert.Equal(t, "true", literal(true))
+	assert.Equal(t, `["a", "b"]`, literal([]string{"a", "b"}))
+}
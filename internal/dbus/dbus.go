@@ -0,0 +1,222 @@
+package dbus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	godbus "github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// Where the timer is found on the bus
+const (
+	Name      = "io.github.pomodoro.Timer"
+	Path      = godbus.ObjectPath("/io/github/pomodoro/Timer")
+	Interface = "io.github.pomodoro.Timer"
+)
+
+// Commands sent by the methods of the same name
+const (
+	Toggle = "toggle"
+	Skip   = "skip"
+	Reset  = "reset"
+)
+
+// propertiesInterface is the standard interface for reading properties
+const propertiesInterface = "org.freedesktop.DBus.Properties"
+
+// State is the timer as exposed through properties
+type State struct {
+	SessionType   string        // work, short_break or long_break
+	Remaining     time.Duration // Rounded down to seconds when exposed
+	Running       bool
+	PomodoroCount int
+}
+
+// properties returns the property values for s
+func (s State) properties() map[string]godbus.Variant {
+	return map[string]godbus.Variant{
+		"SessionType":   godbus.MakeVariant(s.SessionType),
+		"Remaining":     godbus.MakeVariant(int64(s.Remaining / time.Second)),
+		"Running":       godbus.MakeVariant(s.Running),
+		"PomodoroCount": godbus.MakeVariant(int32(s.PomodoroCount)),
+	}
+}
+
+// Service owns Name on the bus and exposes the timer at Path:
+//
+//	Toggle(), Skip(), Reset()  commands, as if the key had been pressed
+//	SessionType    s           work, short_break or long_break
+//	Remaining      x           seconds left
+//	Running        b
+//	PomodoroCount  i           work sessions completed in this cycle
+//
+// PropertiesChanged is emitted with the properties that changed whenever
+// the state is published
+type Service struct {
+	conn     *godbus.Conn
+	commands chan string
+
+	mu    sync.Mutex
+	props map[string]godbus.Variant
+}
+
+// Connect registers the service on the bus at address, or on the session
+// bus if address is empty
+func Connect(address string) (*Service, error) {
+	var conn *godbus.Conn
+	var err error
+	if address == "" {
+		conn, err = godbus.ConnectSessionBus()
+	} else {
+		conn, err = godbus.Connect(address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dbus: %w", err)
+	}
+
+	s := &Service{
+		conn:     conn,
+		commands: make(chan string, 8),
+		props:    State{SessionType: "work"}.properties(),
+	}
+	if err := s.export(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("dbus: %w", err)
+	}
+	reply, err := conn.RequestName(Name, godbus.NameFlagDoNotQueue)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("dbus: %w", err)
+	}
+	if reply != godbus.RequestNameReplyPrimaryOwner {
+		conn.Close()
+		return nil, fmt.Errorf("dbus: %s is taken, is pomodoro already running?", Name)
+	}
+	return s, nil
+}
+
+// export puts the methods, properties and introspection data at Path
+func (s *Service) export() error {
+	methods := map[string]any{
+		"Toggle": s.command(Toggle),
+		"Skip":   s.command(Skip),
+		"Reset":  s.command(Reset),
+	}
+	if err := s.conn.ExportMethodTable(methods, Path, Interface); err != nil {
+		return err
+	}
+	if err := s.conn.Export(properties{s}, Path, propertiesInterface); err != nil {
+		return err
+	}
+
+	timer := introspect.Interface{
+		Name: Interface,
+		Methods: []introspect.Method{
+			{Name: "Toggle"}, {Name: "Skip"}, {Name: "Reset"},
+		},
+		Properties: []introspect.Property{
+			{Name: "SessionType", Type: "s", Access: "read"},
+			{Name: "Remaining", Type: "x", Access: "read"},
+			{Name: "Running", Type: "b", Access: "read"},
+			{Name: "PomodoroCount", Type: "i", Access: "read"},
+		},
+	}
+	node := &introspect.Node{
+		Name:       string(Path),
+		Interfaces: []introspect.Interface{introspect.IntrospectData, prop.IntrospectData, timer},
+	}
+	return s.conn.Export(introspect.NewIntrospectable(node), Path, "org.freedesktop.DBus.Introspectable")
+}
+
+// command returns a method that passes cmd on to Commands
+// Commands are dropped while the app is too busy to take them
+func (s *Service) command(cmd string) func() *godbus.Error {
+	return func() *godbus.Error {
+		select {
+		case s.commands <- cmd:
+		default:
+		}
+		return nil
+	}
+}
+
+// Commands returns the commands called over the bus
+func (s *Service) Commands() <-chan string {
+	return s.commands
+}
+
+// Publish updates the properties and signals the ones that changed
+func (s *Service) Publish(state State) {
+	s.mu.Lock()
+	changed := map[string]godbus.Variant{}
+	for name, value := range state.properties() {
+		if s.props[name].String() != value.String() {
+			changed[name] = value
+			s.props[name] = value
+		}
+	}
+	s.mu.Unlock()
+
+	if len(changed) > 0 {
+		_ = s.conn.Emit(Path, propertiesInterface+".PropertiesChanged", Interface, changed, []string{})
+	}
+}
+
+// Close releases the name and disconnects
+func (s *Service) Close() error {
+	return s.conn.Close()
+}
+
+// properties implements org.freedesktop.DBus.Properties for the timer
+type properties struct {
+	s *Service
+}
+
+// Get returns a property
+func (p properties) Get(iface, name string) (godbus.Variant, *godbus.Error) {
+	if iface != Interface {
+		return godbus.Variant{}, unknownInterface(iface)
+	}
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+	value, ok := p.s.props[name]
+	if !ok {
+		return godbus.Variant{}, unknownProperty(name)
+	}
+	return value, nil
+}
+
+// GetAll returns every property
+func (p properties) GetAll(iface string) (map[string]godbus.Variant, *godbus.Error) {
+	if iface != Interface {
+		return nil, unknownInterface(iface)
+	}
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+	all := make(map[string]godbus.Variant, len(p.s.props))
+	for name, value := range p.s.props {
+		all[name] = value
+	}
+	return all, nil
+}
+
+// Set refuses, as every property is read-only
+func (p properties) Set(iface, name string, _ godbus.Variant) *godbus.Error {
+	if _, err := p.Get(iface, name); err != nil {
+		return err
+	}
+	return godbus.NewError("org.freedesktop.DBus.Error.PropertyReadOnly", []any{name + " is read-only"})
+}
+
+// unknownInterface is the error for properties of other interfaces
+func unknownInterface(iface string) *godbus.Error {
+	return godbus.NewError("org.freedesktop.DBus.Error.UnknownInterface", []any{"no interface " + iface})
+}
+
+// unknownProperty is the error for properties the timer doesn't have
+func unknownProperty(name string) *godbus.Error {
+	return godbus.NewError("org.freedesktop.DBus.Error.UnknownProperty", []any{"no property " + name})
+}
@@ -0,0 +1,175 @@
+package dbus
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	godbus "github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// busConfig is a session bus that lets every client own and call anything
+const busConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-BUS Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// startBus runs a private dbus-daemon for the test and returns its address
+func startBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not installed")
+	}
+	// Socket paths are limited to about 100 bytes, too few for t.TempDir
+	dir, err := os.MkdirTemp("", "dbus")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	conf := filepath.Join(dir, "bus.conf")
+	content := strings.Replace(busConfig, "%s", filepath.Join(dir, "bus"), 1)
+	require.NoError(t, os.WriteFile(conf, []byte(content), 0o600))
+
+	cmd := exec.Command(daemon, "--config-file="+conf, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	return strings.TrimSpace(address)
+}
+
+// connect starts a bus with the service on it and returns a client
+// connection to call it with
+func connect(t *testing.T) (*Service, *godbus.Conn) {
+	t.Helper()
+	address := startBus(t)
+	s, err := Connect(address)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	client, err := godbus.Connect(address)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return s, client
+}
+
+func TestProperties(t *testing.T) {
+	s, client := connect(t)
+	obj := client.Object(Name, Path)
+
+	var all map[string]godbus.Variant
+	require.NoError(t, obj.Call(propertiesInterface+".GetAll", 0, Interface).Store(&all))
+	assert.Equal(t, "work", all["SessionType"].Value())
+	assert.Equal(t, int64(0), all["Remaining"].Value())
+	assert.Equal(t, false, all["Running"].Value())
+	assert.Equal(t, int32(0), all["PomodoroCount"].Value())
+
+	s.Publish(State{SessionType: "short_break", Remaining: 299*time.Second + 600*time.Millisecond, Running: true, PomodoroCount: 2})
+
+	value, err := obj.GetProperty(Interface + ".SessionType")
+	require.NoError(t, err)
+	assert.Equal(t, "short_break", value.Value())
+	value, err = obj.GetProperty(Interface + ".Remaining")
+	require.NoError(t, err)
+	assert.Equal(t, int64(299), value.Value(), "rounded down to seconds")
+	value, err = obj.GetProperty(Interface + ".Running")
+	require.NoError(t, err)
+	assert.Equal(t, true, value.Value())
+	value, err = obj.GetProperty(Interface + ".PomodoroCount")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), value.Value())
+
+	_, err = obj.GetProperty(Interface + ".Colour")
+	assert.ErrorContains(t, err, "no property Colour")
+	err = obj.SetProperty(Interface+".Running", godbus.MakeVariant(false))
+	assert.ErrorContains(t, err, "read-only")
+}
+
+func TestMethods(t *testing.T) {
+	s, client := connect(t)
+	obj := client.Object(Name, Path)
+
+	for _, method := range []string{"Toggle", "Skip", "Reset"} {
+		require.NoError(t, obj.Call(Interface+"."+method, 0).Err, method)
+	}
+	for _, want := range []string{Toggle, Skip, Reset} {
+		select {
+		case got := <-s.Commands():
+			assert.Equal(t, want, got)
+		case <-time.After(time.Second):
+			t.Fatalf("no %s command", want)
+		}
+	}
+}
+
+func TestPropertiesChanged(t *testing.T) {
+	s, client := connect(t)
+	require.NoError(t, client.AddMatchSignal(
+		godbus.WithMatchObjectPath(Path),
+		godbus.WithMatchInterface(propertiesInterface),
+		godbus.WithMatchMember("PropertiesChanged"),
+	))
+	signals := make(chan *godbus.Signal, 8)
+	client.Signal(signals)
+
+	s.Publish(State{SessionType: "work", Remaining: 25 * time.Minute, Running: true})
+
+	select {
+	case sig := <-signals:
+		require.Len(t, sig.Body, 3)
+		assert.Equal(t, Interface, sig.Body[0])
+		changed := sig.Body[1].(map[string]godbus.Variant)
+		assert.Len(t, changed, 2, "only the properties that changed")
+		assert.Equal(t, int64(1500), changed["Remaining"].Value())
+		assert.Equal(t, true, changed["Running"].Value())
+	case <-time.After(time.Second):
+		t.Fatal("no PropertiesChanged signal")
+	}
+
+	// Publishing the same state again stays quiet
+	s.Publish(State{SessionType: "work", Remaining: 25 * time.Minute, Running: true})
+	select {
+	case sig := <-signals:
+		t.Fatalf("unexpected signal %v", sig.Body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestIntrospect(t *testing.T) {
+	_, client := connect(t)
+
+	var xml string
+	require.NoError(t, client.Object(Name, Path).Call("org.freedesktop.DBus.Introspectable.Introspect", 0).Store(&xml))
+	assert.Contains(t, xml, `<interface name="io.github.pomodoro.Timer">`)
+	assert.Contains(t, xml, `<method name="Toggle">`)
+	assert.Contains(t, xml, `<property name="Remaining" type="x" access="read">`)
+	assert.Contains(t, xml, `<interface name="org.freedesktop.DBus.Properties">`)
+}
+
+func TestConnect_NameTaken(t *testing.T) {
+	address := startBus(t)
+	s, err := Connect(address)
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = Connect(address)
+	assert.ErrorContains(t, err, "already running")
+}
@@ -0,0 +1,258 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatICS   Format = "ics"
+)
+
+// ParseFormat validates a format name
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatJSONL, FormatICS:
+		return f, nil
+	case "json":
+		return FormatJSONL, nil
+	case "ical":
+		return FormatICS, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want csv, jsonl or ics)", s)
+}
+
+// Filter selects records for export
+// Zero fields match everything
+type Filter struct {
+	From    time.Time // Inclusive
+	To      time.Time // Exclusive
+	Types   []timer.SessionType
+	Project string
+	Task    string
+	Tag     string
+	Profile string
+}
+
+// Match reports whether r passes the filter
+func (f Filter) Match(r Record) bool {
+	if !f.From.IsZero() && r.Start.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !r.Start.Before(f.To) {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, r.Type) {
+		return false
+	}
+	if f.Project != "" && !strings.EqualFold(r.Project, f.Project) {
+		return false
+	}
+	if f.Task != "" && !strings.EqualFold(r.Task, f.Task) {
+		return false
+	}
+	if f.Profile != "" && !strings.EqualFold(r.Profile, f.Profile) {
+		return false
+	}
+	if f.Tag != "" && !slices.ContainsFunc(r.Tags, func(tag string) bool {
+		return strings.EqualFold(tag, f.Tag)
+	}) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the records that pass the filter
+func (f Filter) Apply(records []Record) []Record {
+	var matches []Record
+	for _, r := range records {
+		if f.Match(r) {
+			matches = append(matches, r)
+		}
+	}
+	return matches
+}
+
+// Export writes records to w in the given format
+func Export(w io.Writer, format Format, records []Record) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, records)
+	case FormatJSONL:
+		return WriteJSONL(w, records)
+	case FormatICS:
+		return WriteICS(w, records)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// csvHeader lists the columns written by WriteCSV
+var csvHeader = []string{
+	"id", "type", "start", "end", "planned_seconds", "elapsed_seconds",
+	"completed", "project", "task", "tags", "note", "focus", "profile",
+}
+
+// WriteCSV writes records as CSV with a header row
+// Times are RFC 3339 and tags are separated by semicolons
+func WriteCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{
+			r.ID,
+			string(r.Type),
+			r.Start.Format(time.RFC3339),
+			r.End.Format(time.RFC3339),
+			strconv.Itoa(int(r.Planned.Seconds())),
+			strconv.Itoa(int(r.Elapsed.Seconds())),
+			strconv.FormatBool(r.Completed),
+			r.Project,
+			r.Task,
+			strings.Join(r.Tags, ";"),
+			r.Note,
+			strconv.Itoa(r.Focus),
+			r.Profile,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSONL writes one JSON record per line, matching the history file format
+func WriteJSONL(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteICS writes work sessions as iCalendar (RFC 5545) events
+// Breaks are left out since they don't belong on a calendar
+func WriteICS(w io.Writer, records []Record) error {
+	iw := &icsWriter{w: w}
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:-//kanishkathakur1//pomodoro//EN")
+	iw.line("CALSCALE:GREGORIAN")
+
+	for _, r := range records {
+		if r.Type != timer.Work {
+			continue
+		}
+		summary := r.Task
+		if summary == "" {
+			summary = r.Project
+		}
+		if summary == "" {
+			summary = "Pomodoro"
+		}
+		iw.line("BEGIN:VEVENT")
+		iw.line("UID:" + r.ID + "@pomodoro")
+		iw.line("DTSTAMP:" + icsTime(r.End))
+		iw.line("DTSTART:" + icsTime(r.Start))
+		iw.line("DTEND:" + icsTime(r.End))
+		iw.line("SUMMARY:" + EscapeICSText(summary))
+		if r.Note != "" {
+			iw.line("DESCRIPTION:" + EscapeICSText(r.Note))
+		}
+		// The project leads the categories so calendars can colour by it
+		categories := r.Tags
+		if r.Project != "" {
+			categories = append([]string{r.Project}, r.Tags...)
+		}
+		if len(categories) > 0 {
+			escaped := make([]string, len(categories))
+			for i, category := range categories {
+				escaped[i] = EscapeICSText(category)
+			}
+			iw.line("CATEGORIES:" + strings.Join(escaped, ","))
+		}
+		if !r.Completed {
+			iw.line("STATUS:CANCELLED")
+		}
+		iw.line("END:VEVENT")
+	}
+
+	iw.line("END:VCALENDAR")
+	return iw.err
+}
+
+// icsTime formats a time as an RFC 5545 UTC date-time
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// EscapeICSText escapes a TEXT property value per RFC 5545 section 3.3.11
+func EscapeICSText(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ReplaceAll(s, "\r\n", "\n") {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case ';':
+			b.WriteString(`\;`)
+		case ',':
+			b.WriteString(`\,`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			// Lone carriage returns are dropped
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// icsMaxLine is the longest content line allowed before folding, in octets
+const icsMaxLine = 75
+
+// icsWriter writes CRLF-terminated content lines, folding long ones
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes one content line, folding it per RFC 5545 section 3.1
+// Folds never split a multi-byte UTF-8 sequence
+func (iw *icsWriter) line(s string) {
+	if iw.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > icsMaxLine {
+			b.WriteString("\r\n ")
+			width = 1 // The leading space counts toward the next line
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	_, iw.err = io.WriteString(iw.w, b.String())
+}
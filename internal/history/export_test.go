@@ -0,0 +1,214 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportRecords() []Record {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	return []Record{
+		{
+			ID: NewID(start), Type: timer.Work, Start: start, End: start.Add(25 * time.Minute),
+			Planned: 25 * time.Minute, Elapsed: 25 * time.Minute, Completed: true,
+			Task: "API refactor", Tags: []string{"backend", "q2"}, Note: "split handlers; added tests", Focus: 4,
+			Profile: "coding",
+		},
+		{
+			ID: NewID(start.Add(25 * time.Minute)), Type: timer.ShortBreak, Start: start.Add(25 * time.Minute),
+			End: start.Add(30 * time.Minute), Planned: 5 * time.Minute, Elapsed: 5 * time.Minute, Completed: true,
+		},
+		{
+			ID: NewID(start.Add(time.Hour)), Type: timer.Work, Start: start.Add(time.Hour), End: start.Add(70 * time.Minute),
+			Planned: 25 * time.Minute, Elapsed: 10 * time.Minute, Task: "Reviews", Tags: []string{"Q2"},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, expected := range map[string]Format{"csv": FormatCSV, "JSONL": FormatJSONL, "json": FormatJSONL, "ics": FormatICS, "ical": FormatICS} {
+		f, err := ParseFormat(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, expected, f)
+	}
+
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	records := exportRecords()
+	start := records[0].Start
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected int
+	}{
+		{"zero filter", Filter{}, 3},
+		{"from", Filter{From: start.Add(time.Minute)}, 2},
+		{"to is exclusive", Filter{To: records[2].Start}, 2},
+		{"type", Filter{Types: []timer.SessionType{timer.Work}}, 2},
+		{"task ignores case", Filter{Task: "api REFACTOR"}, 1},
+		{"tag ignores case", Filter{Tag: "q2"}, 2},
+		{"profile ignores case", Filter{Profile: "Coding"}, 1},
+		{"no match", Filter{Tag: "frontend"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.filter.Apply(records), tt.expected)
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, exportRecords()))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, "work", rows[1][1])
+	assert.Equal(t, "2026-03-02T09:00:00Z", rows[1][2])
+	assert.Equal(t, "1500", rows[1][5])
+	assert.Equal(t, "backend;q2", rows[1][9])
+	assert.Equal(t, "split handlers; added tests", rows[1][10])
+	assert.Equal(t, "coding", rows[1][12])
+	assert.Equal(t, "", rows[2][12])
+	assert.Equal(t, "false", rows[3][6])
+}
+
+func TestWriteJSONL_RoundTrip(t *testing.T) {
+	records := exportRecords()
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSONL(&buf, records))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+
+	// The output is the history file format, so a store can read it back
+	store := newTestStore(t)
+	require.NoError(t, os.MkdirAll(filepath.Dir(store.Path()), 0755))
+	require.NoError(t, os.WriteFile(store.Path(), buf.Bytes(), 0644))
+	got, err := store.All()
+	require.NoError(t, err)
+	require.Len(t, got, len(records))
+	for i, r := range records {
+		assert.Equal(t, r.ID, got[i].ID)
+		assert.Equal(t, r.Type, got[i].Type)
+		assert.True(t, r.Start.Equal(got[i].Start), "start of record %d", i)
+		assert.Equal(t, r.Elapsed, got[i].Elapsed)
+		assert.Equal(t, r.Tags, got[i].Tags)
+		assert.Equal(t, r.Note, got[i].Note)
+	}
+}
+
+// unfoldICS reverses RFC 5545 line folding and splits content lines
+func unfoldICS(t *testing.T, s string) []string {
+	t.Helper()
+	require.True(t, strings.HasSuffix(s, "\r\n"), "content must end with CRLF")
+	assert.NotContains(t, strings.ReplaceAll(s, "\r\n", ""), "\n", "bare LF is not allowed")
+
+	unfolded := strings.ReplaceAll(s, "\r\n ", "")
+	return strings.Split(strings.TrimSuffix(unfolded, "\r\n"), "\r\n")
+}
+
+// unescapeICSText reverses EscapeICSText
+func unescapeICSText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func TestWriteICS_Structure(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteICS(&buf, exportRecords()))
+
+	lines := unfoldICS(t, buf.String())
+
+	assert.Equal(t, "BEGIN:VCALENDAR", lines[0])
+	assert.Equal(t, "END:VCALENDAR", lines[len(lines)-1])
+	assert.Contains(t, lines, "VERSION:2.0")
+	assert.Equal(t, 2, strings.Count(buf.String(), "BEGIN:VEVENT"), "only work sessions become events")
+	assert.Contains(t, lines, "DTSTART:20260302T090000Z")
+	assert.Contains(t, lines, "DTEND:20260302T092500Z")
+	assert.Contains(t, lines, "SUMMARY:API refactor")
+	assert.Contains(t, lines, "CATEGORIES:backend,q2")
+	assert.Contains(t, lines, "STATUS:CANCELLED")
+}
+
+func TestWriteICS_EscapingRoundTrip(t *testing.T) {
+	tricky := "Fix a\\b; then c, d\nand e"
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	records := []Record{{ID: "x", Type: timer.Work, Start: start, End: start, Task: tricky, Note: tricky}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteICS(&buf, records))
+	lines := unfoldICS(t, buf.String())
+
+	var summary string
+	for _, line := range lines {
+		if value, ok := strings.CutPrefix(line, "SUMMARY:"); ok {
+			summary = value
+		}
+	}
+	assert.Equal(t, `Fix a\\b\; then c\, d\nand e`, summary)
+	assert.Equal(t, tricky, unescapeICSText(summary))
+}
+
+func TestWriteICS_Folding(t *testing.T) {
+	// Multi-byte runes make sure folds land between UTF-8 sequences
+	long := strings.Repeat("Überarbeitung der Schnittstelle — ", 8)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	records := []Record{{ID: "x", Type: timer.Work, Start: start, End: start, Task: long}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteICS(&buf, records))
+
+	physical := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	folded := 0
+	for _, line := range physical {
+		assert.LessOrEqual(t, len(line), icsMaxLine, "line exceeds 75 octets: %q", line)
+		assert.True(t, utf8.ValidString(line), "fold split a UTF-8 sequence")
+		if strings.HasPrefix(line, " ") {
+			folded++
+		}
+	}
+	assert.Greater(t, folded, 1)
+
+	lines := unfoldICS(t, buf.String())
+	assert.Contains(t, lines, "SUMMARY:"+long)
+}
+
+func TestExport_UnknownFormat(t *testing.T) {
+	assert.Error(t, Export(&bytes.Buffer{}, Format("xml"), nil))
+}
@@ -0,0 +1,236 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// Record is a single finished (or skipped) session
+type Record struct {
+	ID        string            `json:"id"`
+	Type      timer.SessionType `json:"type"`
+	Start     time.Time         `json:"start"`
+	End       time.Time         `json:"end"`
+	Planned   time.Duration     `json:"planned"`
+	Elapsed   time.Duration     `json:"elapsed"`
+	Completed bool              `json:"completed"`
+	Project   string            `json:"project,omitempty"`
+	Task      string            `json:"task,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Note      string            `json:"note,omitempty"`
+	Focus     int               `json:"focus,omitempty"` // Self-rated focus 1-5, 0 if unrated
+	Profile   string            `json:"profile,omitempty"`
+
+	// Unacknowledged is how long the finished session waited for a key press
+	Unacknowledged time.Duration `json:"unacknowledged,omitempty"`
+
+	// Amend marks a line that only sets Unacknowledged on the earlier record
+	// with the same ID; All folds it in
+	Amend bool `json:"amend,omitempty"`
+}
+
+// amendment is the line Acknowledge appends
+type amendment struct {
+	ID             string        `json:"id"`
+	Amend          bool          `json:"amend"`
+	Unacknowledged time.Duration `json:"unacknowledged"`
+}
+
+// Store persists records as JSON Lines, one record per line
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by the given file
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Open creates a store at the default history location
+func Open() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(path), nil
+}
+
+// DefaultPath returns the history file next to the config file
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "pomodoro", "history.jsonl"), nil
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// NewID derives a record ID from the session start time
+func NewID(start time.Time) string {
+	return start.UTC().Format("20060102T150405.000000000Z")
+}
+
+// Append adds a record to the end of the history file
+func (s *Store) Append(r Record) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Acknowledge notes how long the record with the given ID waited for a key
+// press, appending an amendment instead of rewriting the file
+func (s *Store) Acknowledge(id string, waited time.Duration) error {
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(amendment{ID: id, Amend: true, Unacknowledged: waited})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// All reads every record in file order, with amendments folded in
+// A missing history file is treated as empty
+func (s *Store) All() ([]Record, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	index := map[string]int{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, lineNo, err)
+		}
+		if r.Amend {
+			if i, ok := index[r.ID]; ok {
+				records[i].Unacknowledged = r.Unacknowledged
+			}
+			continue
+		}
+		index[r.ID] = len(records)
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// Update applies fn to the record with the given ID and rewrites the file
+func (s *Store) Update(id string, fn func(*Record)) error {
+	records, err := s.All()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i := range records {
+		if records[i].ID == id {
+			fn(&records[i])
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("history: no record with id %q", id)
+	}
+
+	return s.rewrite(records)
+}
+
+// rewrite replaces the history file with the given records
+func (s *Store) rewrite(records []Record) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".history-*.jsonl")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Search returns records whose note contains query, ignoring case
+func Search(records []Record, query string) []Record {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matches []Record
+	for _, r := range records {
+		if query == "" || strings.Contains(strings.ToLower(r.Note), query) {
+			matches = append(matches, r)
+		}
+	}
+	return matches
+}
+
+// Insert adds records and rewrites the file ordered by start time
+func (s *Store) Insert(records []Record) error {
+	existing, err := s.All()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	all := append(existing, records...)
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Start.Before(all[j].Start)
+	})
+	return s.rewrite(all)
+}
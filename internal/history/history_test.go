@@ -0,0 +1,161 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	return NewStore(filepath.Join(t.TempDir(), "pomodoro", "history.jsonl"))
+}
+
+func testRecord(start time.Time, note string) Record {
+	return Record{
+		ID:        NewID(start),
+		Type:      timer.Work,
+		Start:     start,
+		End:       start.Add(25 * time.Minute),
+		Planned:   25 * time.Minute,
+		Elapsed:   25 * time.Minute,
+		Completed: true,
+		Note:      note,
+	}
+}
+
+func TestAll_MissingFile(t *testing.T) {
+	store := newTestStore(t)
+
+	records, err := store.All()
+
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestAppend_RoundTrip(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.Append(testRecord(start, "wrote parser")))
+	require.NoError(t, store.Append(testRecord(start.Add(time.Hour), "")))
+
+	records, err := store.All()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "wrote parser", records[0].Note)
+	assert.True(t, records[0].Start.Equal(start))
+	assert.Equal(t, 25*time.Minute, records[1].Elapsed)
+	assert.Equal(t, timer.Work, records[1].Type)
+}
+
+func TestAll_CorruptLine(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, os.MkdirAll(filepath.Dir(store.Path()), 0755))
+	require.NoError(t, os.WriteFile(store.Path(), []byte("{\"id\":\"a\"}\nnot json\n"), 0644))
+
+	_, err := store.All()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ":2:")
+}
+
+func TestUpdate(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	first := testRecord(start, "")
+	second := testRecord(start.Add(time.Hour), "")
+	require.NoError(t, store.Append(first))
+	require.NoError(t, store.Append(second))
+
+	err := store.Update(second.ID, func(r *Record) {
+		r.Note = "reviewed PRs"
+		r.Focus = 4
+	})
+	require.NoError(t, err)
+
+	records, err := store.All()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Empty(t, records[0].Note)
+	assert.Equal(t, "reviewed PRs", records[1].Note)
+	assert.Equal(t, 4, records[1].Focus)
+}
+
+func TestUpdate_UnknownID(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.Append(testRecord(time.Now(), "")))
+
+	err := store.Update("missing", func(r *Record) {})
+
+	assert.Error(t, err)
+}
+
+func TestAcknowledge(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	first := testRecord(start, "")
+	second := testRecord(start.Add(time.Hour), "")
+	require.NoError(t, store.Append(first))
+	require.NoError(t, store.Append(second))
+	before, err := os.ReadFile(store.Path())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Acknowledge(first.ID, 3*time.Minute))
+
+	after, err := os.ReadFile(store.Path())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(after), string(before)), "the file is only appended to")
+	records, err := store.All()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, 3*time.Minute, records[0].Unacknowledged)
+	assert.Zero(t, records[1].Unacknowledged)
+
+	// Rewriting the file keeps the amended value
+	require.NoError(t, store.Update(second.ID, func(r *Record) { r.Note = "done" }))
+	records, err = store.All()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, 3*time.Minute, records[0].Unacknowledged)
+	assert.False(t, records[0].Amend)
+}
+
+func TestSearch(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	records := []Record{
+		testRecord(start, "Fixed the Parser bug"),
+		testRecord(start.Add(time.Hour), "code review"),
+		testRecord(start.Add(2*time.Hour), ""),
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{"empty query matches all", "", 3},
+		{"case insensitive", "parser", 1},
+		{"substring", "view", 1},
+		{"no match", "deploy", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, Search(records, tt.query), tt.expected)
+		})
+	}
+}
+
+func TestNewID_StableAndUnique(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, NewID(start), NewID(start.In(time.FixedZone("X", 3600))))
+	assert.NotEqual(t, NewID(start), NewID(start.Add(time.Millisecond)))
+}
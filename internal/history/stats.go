@@ -0,0 +1,112 @@
+package history
+
+import (
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// Totals summarises completed work sessions
+type Totals struct {
+	Pomodoros int
+	Focus     time.Duration
+}
+
+// Sum totals completed work sessions that started in [from, to)
+func Sum(records []Record, from, to time.Time) Totals {
+	var totals Totals
+	for _, r := range records {
+		if r.Type != timer.Work || !r.Completed {
+			continue
+		}
+		if r.Start.Before(from) || !r.Start.Before(to) {
+			continue
+		}
+		totals.Pomodoros++
+		totals.Focus += r.Elapsed
+	}
+	return totals
+}
+
+// DayStart returns when the day containing now began
+// boundary shifts the start of day past midnight, so 4h means days run 04:00-04:00
+func DayStart(now time.Time, boundary time.Duration) time.Time {
+	start := atBoundary(now.Year(), now.Month(), now.Day(), boundary, now.Location())
+	if now.Before(start) {
+		start = atBoundary(now.Year(), now.Month(), now.Day()-1, boundary, now.Location())
+	}
+	return start
+}
+
+// WeekStart returns when the week containing now began
+func WeekStart(now time.Time, boundary time.Duration, first time.Weekday) time.Time {
+	day := DayStart(now, boundary)
+	offset := (int(day.Weekday()) - int(first) + 7) % 7
+	return atBoundary(day.Year(), day.Month(), day.Day()-offset, boundary, now.Location())
+}
+
+// atBoundary returns the wall-clock boundary time on the given date
+func atBoundary(year int, month time.Month, day int, boundary time.Duration, loc *time.Location) time.Time {
+	hour := int(boundary / time.Hour)
+	minute := int(boundary % time.Hour / time.Minute)
+	return time.Date(year, month, day, hour, minute, 0, 0, loc)
+}
+
+// ByProject keys a record by its project
+func ByProject(r Record) []string {
+	return []string{r.Project}
+}
+
+// ByTag keys a record by each of its tags, or "" when untagged
+func ByTag(r Record) []string {
+	if len(r.Tags) == 0 {
+		return []string{""}
+	}
+	return r.Tags
+}
+
+// Breakdown totals completed work sessions in [from, to) per key
+// A record with several keys counts toward each of them
+func Breakdown(records []Record, from, to time.Time, key func(Record) []string) map[string]Totals {
+	groups := map[string]Totals{}
+	for _, r := range records {
+		if r.Type != timer.Work || !r.Completed {
+			continue
+		}
+		if r.Start.Before(from) || !r.Start.Before(to) {
+			continue
+		}
+		for _, k := range key(r) {
+			totals := groups[k]
+			totals.Pomodoros++
+			totals.Focus += r.Elapsed
+			groups[k] = totals
+		}
+	}
+	return groups
+}
+
+// Projects returns the distinct projects in records, most recent first
+func Projects(records []Record) []string {
+	return distinct(records, ByProject)
+}
+
+// Tags returns the distinct tags in records, most recent first
+func Tags(records []Record) []string {
+	return distinct(records, func(r Record) []string { return r.Tags })
+}
+
+// distinct collects non-empty keys walking records from newest to oldest
+func distinct(records []Record, key func(Record) []string) []string {
+	seen := map[string]bool{}
+	var values []string
+	for i := len(records) - 1; i >= 0; i-- {
+		for _, k := range key(records[i]) {
+			if k != "" && !seen[k] {
+				seen[k] = true
+				values = append(values, k)
+			}
+		}
+	}
+	return values
+}
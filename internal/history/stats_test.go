@@ -0,0 +1,109 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSum(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Type: timer.Work, Start: day.Add(9 * time.Hour), Elapsed: 25 * time.Minute, Completed: true},
+		{Type: timer.Work, Start: day.Add(10 * time.Hour), Elapsed: 25 * time.Minute, Completed: true},
+		{Type: timer.Work, Start: day.Add(11 * time.Hour), Elapsed: 10 * time.Minute, Completed: false},
+		{Type: timer.ShortBreak, Start: day.Add(12 * time.Hour), Elapsed: 5 * time.Minute, Completed: true},
+		{Type: timer.Work, Start: day.Add(-time.Hour), Elapsed: 25 * time.Minute, Completed: true},
+	}
+
+	totals := Sum(records, day, day.Add(24*time.Hour))
+
+	assert.Equal(t, 2, totals.Pomodoros)
+	assert.Equal(t, 50*time.Minute, totals.Focus)
+}
+
+func TestDayStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		now      time.Time
+		boundary time.Duration
+		expected time.Time
+	}{
+		{
+			"midnight boundary",
+			time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC),
+			0,
+			time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"after late boundary",
+			time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC),
+			4 * time.Hour,
+			time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			"night owl before boundary belongs to previous day",
+			time.Date(2026, 3, 2, 2, 30, 0, 0, time.UTC),
+			4 * time.Hour,
+			time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			"exactly on boundary",
+			time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC),
+			4 * time.Hour,
+			time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DayStart(tt.now, tt.boundary))
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	// 2026-03-04 is a Wednesday
+	now := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC), WeekStart(now, 4*time.Hour, time.Monday))
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), WeekStart(now, 0, time.Sunday))
+
+	// Monday 02:00 still belongs to the previous week with a 04:00 boundary
+	early := time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 2, 23, 4, 0, 0, 0, time.UTC), WeekStart(early, 4*time.Hour, time.Monday))
+}
+
+func TestBreakdown(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Type: timer.Work, Start: day.Add(9 * time.Hour), Elapsed: 25 * time.Minute, Completed: true, Project: "api", Tags: []string{"backend", "q2"}},
+		{Type: timer.Work, Start: day.Add(10 * time.Hour), Elapsed: 50 * time.Minute, Completed: true, Project: "api", Tags: []string{"q2"}},
+		{Type: timer.Work, Start: day.Add(11 * time.Hour), Elapsed: 25 * time.Minute, Completed: true},
+		{Type: timer.Work, Start: day.Add(12 * time.Hour), Elapsed: 5 * time.Minute, Project: "api"},
+	}
+	end := day.Add(24 * time.Hour)
+
+	byProject := Breakdown(records, day, end, ByProject)
+	assert.Equal(t, Totals{Pomodoros: 2, Focus: 75 * time.Minute}, byProject["api"])
+	assert.Equal(t, Totals{Pomodoros: 1, Focus: 25 * time.Minute}, byProject[""])
+
+	byTag := Breakdown(records, day, end, ByTag)
+	assert.Equal(t, 2, byTag["q2"].Pomodoros)
+	assert.Equal(t, 1, byTag["backend"].Pomodoros)
+	assert.Equal(t, 1, byTag[""].Pomodoros)
+}
+
+func TestProjectsAndTags_MostRecentFirst(t *testing.T) {
+	records := []Record{
+		{Project: "api", Tags: []string{"backend"}},
+		{Project: "docs", Tags: []string{"writing", "backend"}},
+		{Project: ""},
+		{Project: "api"},
+	}
+
+	assert.Equal(t, []string{"api", "docs"}, Projects(records))
+	assert.Equal(t, []string{"writing", "backend"}, Tags(records))
+}
@@ -0,0 +1,59 @@
+package hook
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Event names passed to hooks in POMODORO_EVENT
+const (
+	SessionStart = "session_start"
+	SessionEnd   = "session_end"
+	Reminder     = "reminder"
+)
+
+// Env describes the session a hook runs for
+type Env struct {
+	Event     string
+	Session   string // work, short_break or long_break
+	Profile   string
+	Project   string
+	Task      string
+	Completed bool // For session_end, false if the session was skipped
+	Reminder  int  // For reminder, how many have been sent including this one
+}
+
+// vars returns the environment entries for the hook
+func (e Env) vars() []string {
+	return []string{
+		"POMODORO_EVENT=" + e.Event,
+		"POMODORO_SESSION=" + e.Session,
+		"POMODORO_PROFILE=" + e.Profile,
+		"POMODORO_PROJECT=" + e.Project,
+		"POMODORO_TASK=" + e.Task,
+		fmt.Sprintf("POMODORO_COMPLETED=%t", e.Completed),
+		fmt.Sprintf("POMODORO_REMINDER=%d", e.Reminder),
+	}
+}
+
+// Run executes a hook command with sh -c and waits for it to finish
+// An empty command does nothing
+func Run(command string, env Env) error {
+	if strings.TrimSpace(command) == "" {
+		return nil
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), env.vars()...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s hook: %w: %s", env.Event, err, msg)
+		}
+		return fmt.Errorf("%s hook: %w", env.Event, err)
+	}
+	return nil
+}
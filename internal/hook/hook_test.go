@@ -0,0 +1,39 @@
+package hook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_PassesSessionDetails(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env")
+
+	err := Run(`echo "$POMODORO_EVENT $POMODORO_SESSION $POMODORO_PROFILE $POMODORO_PROJECT $POMODORO_COMPLETED" > `+out, Env{
+		Event:     SessionEnd,
+		Session:   "work",
+		Profile:   "coding",
+		Project:   "api",
+		Completed: true,
+	})
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "session_end work coding api true\n", string(content))
+}
+
+func TestRun_Empty(t *testing.T) {
+	assert.NoError(t, Run("  ", Env{Event: SessionStart}))
+}
+
+func TestRun_Failure(t *testing.T) {
+	err := Run("echo broken >&2; exit 3", Env{Event: SessionStart})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session_start hook")
+	assert.Contains(t, err.Error(), "broken")
+}
@@ -0,0 +1,270 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// Mapping maps record fields to CSV column headers
+// Fields: start, end, duration, type, project, task, tags, note, completed, focus, profile
+type Mapping map[string]string
+
+// mappingFields lists the fields a Mapping may set
+var mappingFields = []string{"start", "end", "duration", "type", "project", "task", "tags", "note", "completed", "focus", "profile"}
+
+// DefaultMapping matches the columns written by history.WriteCSV
+func DefaultMapping() Mapping {
+	return Mapping{
+		"start":     "start",
+		"end":       "end",
+		"duration":  "elapsed_seconds",
+		"type":      "type",
+		"project":   "project",
+		"task":      "task",
+		"tags":      "tags",
+		"note":      "note",
+		"completed": "completed",
+		"focus":     "focus",
+		"profile":   "profile",
+	}
+}
+
+// ParseMapping parses "field=Column,field=Column" into a mapping
+func ParseMapping(s string) (Mapping, error) {
+	m := Mapping{}
+	if strings.TrimSpace(s) == "" {
+		return m, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || column == "" {
+			return nil, fmt.Errorf("mapping %q: want field=Column", pair)
+		}
+		if !isMappingField(field) {
+			return nil, fmt.Errorf("mapping %q: unknown field %q", pair, field)
+		}
+		m[field] = strings.TrimSpace(column)
+	}
+	return m, nil
+}
+
+// isMappingField reports whether field can be mapped
+func isMappingField(field string) bool {
+	for _, f := range mappingFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// GenericCSV imports any CSV with a header row using a column mapping
+// Unmapped fields fall back to DefaultMapping. Durations may be Go durations
+// ("25m"), clock times ("00:25:00") or plain seconds
+type GenericCSV struct {
+	Mapping    Mapping
+	TimeLayout string
+	Location   *time.Location
+}
+
+// Import implements Importer
+func (g *GenericCSV) Import(r io.Reader) ([]history.Record, error) {
+	mapping := DefaultMapping()
+	for field, column := range g.Mapping {
+		mapping[field] = column
+	}
+	layout := g.TimeLayout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	loc := location(g.Location)
+
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []history.Record
+	for _, row := range rows {
+		get := func(field string) string { return row.get(mapping[field]) }
+
+		startText := get("start")
+		if startText == "" {
+			return nil, fmt.Errorf("line %d: missing start", row.line)
+		}
+		start, err := time.ParseInLocation(layout, startText, loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: start: %w", row.line, err)
+		}
+
+		var elapsed time.Duration
+		switch {
+		case get("duration") != "":
+			if elapsed, err = parseDuration(get("duration")); err != nil {
+				return nil, fmt.Errorf("line %d: duration: %w", row.line, err)
+			}
+		case get("end") != "":
+			end, err := time.ParseInLocation(layout, get("end"), loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: end: %w", row.line, err)
+			}
+			if end.Before(start) {
+				return nil, fmt.Errorf("line %d: end %s is before start", row.line, get("end"))
+			}
+			elapsed = end.Sub(start)
+		default:
+			return nil, fmt.Errorf("line %d: need an end or duration column", row.line)
+		}
+
+		record := workRecord(start, elapsed, get("task"))
+		if sessionType := get("type"); sessionType != "" {
+			record.Type = timer.SessionType(strings.ToLower(sessionType))
+			switch record.Type {
+			case timer.Work, timer.ShortBreak, timer.LongBreak:
+			default:
+				return nil, fmt.Errorf("line %d: type: unknown session type %q", row.line, sessionType)
+			}
+		}
+		if completed := get("completed"); completed != "" {
+			if record.Completed, err = strconv.ParseBool(completed); err != nil {
+				return nil, fmt.Errorf("line %d: completed: want true or false, got %q", row.line, completed)
+			}
+		}
+		if focus := get("focus"); focus != "" {
+			if record.Focus, err = strconv.Atoi(focus); err != nil || record.Focus < 1 || record.Focus > 5 {
+				return nil, fmt.Errorf("line %d: focus: want 1 to 5, got %q", row.line, focus)
+			}
+		}
+		record.Project = get("project")
+		record.Tags = splitTags(get("tags"))
+		record.Note = get("note")
+		record.Profile = get("profile")
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// TogglCSV imports a Toggl Track detailed time entry CSV export
+type TogglCSV struct {
+	Location *time.Location
+}
+
+// Import implements Importer
+func (t *TogglCSV) Import(r io.Reader) ([]history.Record, error) {
+	loc := location(t.Location)
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []history.Record
+	for _, row := range rows {
+		start, err := time.ParseInLocation("2006-01-02 15:04:05",
+			row.get("Start date")+" "+row.get("Start time"), loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: start: %w", row.line, err)
+		}
+		elapsed, err := parseDuration(row.get("Duration"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: duration: %w", row.line, err)
+		}
+
+		task := row.get("Description")
+		if task == "" {
+			task = row.get("Task")
+		}
+		record := workRecord(start, elapsed, task)
+		record.Project = row.get("Project")
+		record.Tags = splitTags(row.get("Tags"))
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// csvRow is a data row addressable by header name
+type csvRow struct {
+	line    int
+	columns map[string]int
+	values  []string
+}
+
+// get returns the trimmed value under header, or "" if absent
+func (r csvRow) get(header string) string {
+	i, ok := r.columns[strings.ToLower(header)]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+// readCSV reads a CSV with a header row into addressable rows
+func readCSV(r io.Reader) ([]csvRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var rows []csvRow
+	for {
+		values, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		rows = append(rows, csvRow{line: line, columns: columns, values: values})
+	}
+}
+
+// parseDuration accepts "25m", "HH:MM:SS", "MM:SS" or whole seconds, none
+// of them negative
+func parseDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return 0, fmt.Errorf("negative duration %q", s)
+		}
+		return d, nil
+	}
+	if seconds, err := strconv.Atoi(s); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("negative duration %q", s)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var total time.Duration
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total = total*60 + time.Duration(n)
+	}
+	return total * time.Second, nil
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMapping(t *testing.T) {
+	m, err := ParseMapping("start=Began At, duration = Length")
+	require.NoError(t, err)
+	assert.Equal(t, Mapping{"start": "Began At", "duration": "Length"}, m)
+
+	m, err = ParseMapping("")
+	require.NoError(t, err)
+	assert.Empty(t, m)
+
+	_, err = ParseMapping("start")
+	assert.Error(t, err)
+	_, err = ParseMapping("colour=Red")
+	assert.Error(t, err)
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected time.Duration
+	}{
+		{"25m", 25 * time.Minute},
+		{"1500", 25 * time.Minute},
+		{"00:25:00", 25 * time.Minute},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"25:00", 25 * time.Minute},
+	}
+	for _, tt := range tests {
+		d, err := parseDuration(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.expected, d, tt.in)
+	}
+
+	for _, bad := range []string{"", "soon", "1:2:3:4", "-1:00", "-25m", "-60"} {
+		_, err := parseDuration(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestGenericCSV_NativeExportRoundTrip(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	original := []history.Record{{
+		ID: history.NewID(start), Type: timer.Work, Start: start, End: start.Add(25 * time.Minute),
+		Planned: 25 * time.Minute, Elapsed: 25 * time.Minute, Completed: true,
+		Project: "api", Task: "API refactor", Tags: []string{"backend", "q2"}, Note: "split, tested", Focus: 4,
+		Profile: "coding",
+	}}
+	var buf bytes.Buffer
+	require.NoError(t, history.WriteCSV(&buf, original))
+
+	records, err := (&GenericCSV{}).Import(&buf)
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	r := records[0]
+	assert.True(t, r.Start.Equal(start))
+	assert.Equal(t, 25*time.Minute, r.Elapsed)
+	assert.Equal(t, "api", r.Project)
+	assert.Equal(t, "API refactor", r.Task)
+	assert.Equal(t, []string{"backend", "q2"}, r.Tags)
+	assert.Equal(t, "split, tested", r.Note)
+	assert.Equal(t, 4, r.Focus)
+	assert.Equal(t, "coding", r.Profile)
+	assert.True(t, r.Completed)
+}
+
+func TestGenericCSV_CustomMapping(t *testing.T) {
+	data := "\ufeffWhen,Until,What\n2026-03-02 09:00,2026-03-02 09:50,Writing\n"
+	imp := &GenericCSV{
+		Mapping:    Mapping{"start": "When", "end": "Until", "task": "What"},
+		TimeLayout: "2006-01-02 15:04",
+		Location:   time.UTC,
+	}
+
+	records, err := imp.Import(strings.NewReader(data))
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), records[0].Start)
+	assert.Equal(t, 50*time.Minute, records[0].Elapsed)
+	assert.Equal(t, "Writing", records[0].Task)
+	assert.Equal(t, timer.Work, records[0].Type)
+	assert.True(t, records[0].Completed)
+}
+
+func TestGenericCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing start", "start,elapsed_seconds\n,60\n"},
+		{"bad start", "start,elapsed_seconds\nyesterday,60\n"},
+		{"no length", "start\n2026-03-02T09:00:00Z\n"},
+		{"bad duration", "start,elapsed_seconds\n2026-03-02T09:00:00Z,long\n"},
+		{"negative duration", "start,elapsed_seconds\n2026-03-02T09:00:00Z,-60\n"},
+		{"end before start", "start,end\n2026-03-02T09:00:00Z,2026-03-02T08:00:00Z\n"},
+		{"unknown type", "start,elapsed_seconds,type\n2026-03-02T09:00:00Z,60,meeting\n"},
+		{"bad completed", "start,elapsed_seconds,completed\n2026-03-02T09:00:00Z,60,maybe\n"},
+		{"bad focus", "start,elapsed_seconds,focus\n2026-03-02T09:00:00Z,60,high\n"},
+		{"focus out of range", "start,elapsed_seconds,focus\n2026-03-02T09:00:00Z,60,6\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&GenericCSV{}).Import(strings.NewReader(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "line 2")
+		})
+	}
+}
+
+func TestGenericCSV_TypeIgnoresCase(t *testing.T) {
+	data := "start,elapsed_seconds,type\n2026-03-02T09:00:00Z,300,Short_Break\n"
+
+	records, err := (&GenericCSV{}).Import(strings.NewReader(data))
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, timer.ShortBreak, records[0].Type)
+}
+
+func TestTogglCSV(t *testing.T) {
+	data := `User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()
+Ana,ana@example.com,,Website,,Landing page copy,No,2024-01-15,09:00:00,2024-01-15,09:25:00,00:25:00,"writing, marketing",
+Ana,ana@example.com,,Website,Review,,No,2024-01-15,10:00:00,2024-01-15,11:30:00,01:30:00,,
+`
+	loc := time.FixedZone("CET", 3600)
+
+	records, err := (&TogglCSV{Location: loc}).Import(strings.NewReader(data))
+
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.True(t, records[0].Start.Equal(time.Date(2024, 1, 15, 9, 0, 0, 0, loc)))
+	assert.Equal(t, 25*time.Minute, records[0].Elapsed)
+	assert.Equal(t, "Landing page copy", records[0].Task)
+	assert.Equal(t, "Website", records[0].Project)
+	assert.Equal(t, []string{"writing", "marketing"}, records[0].Tags)
+	assert.Equal(t, "Review", records[1].Task, "falls back to the task column")
+	assert.Equal(t, 90*time.Minute, records[1].Elapsed)
+}
+
+func TestTogglCSV_BadRow(t *testing.T) {
+	data := "Start date,Start time,Duration\n2024-01-15,late,00:25:00\n"
+
+	_, err := (&TogglCSV{}).Import(strings.NewReader(data))
+
+	assert.Error(t, err)
+}
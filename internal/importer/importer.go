@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/kanishkathakur1/pomodoro/internal/timer"
+)
+
+// Importer converts another tool's export into history records
+type Importer interface {
+	Import(r io.Reader) ([]history.Record, error)
+}
+
+// Report describes what an import would add
+type Report struct {
+	New        []history.Record
+	Duplicates []history.Record
+}
+
+// Plan splits incoming records into new ones and duplicates
+// Records are duplicates when they start in the same second as an existing
+// or earlier incoming record
+func Plan(existing, incoming []history.Record) Report {
+	seen := make(map[int64]bool, len(existing))
+	for _, r := range existing {
+		seen[r.Start.Unix()] = true
+	}
+
+	var report Report
+	for _, r := range incoming {
+		key := r.Start.Unix()
+		if seen[key] {
+			report.Duplicates = append(report.Duplicates, r)
+			continue
+		}
+		seen[key] = true
+		report.New = append(report.New, r)
+	}
+
+	sort.SliceStable(report.New, func(i, j int) bool {
+		return report.New[i].Start.Before(report.New[j].Start)
+	})
+	return report
+}
+
+// New returns the importer for a source name
+func New(source string, opts Options) (Importer, error) {
+	switch strings.ToLower(source) {
+	case "csv":
+		return &GenericCSV{Mapping: opts.Mapping, TimeLayout: opts.TimeLayout, Location: opts.Location}, nil
+	case "toggl":
+		return &TogglCSV{Location: opts.Location}, nil
+	case "pomofocus":
+		return &PomofocusJSON{Location: opts.Location}, nil
+	case "superproductivity", "super-productivity":
+		return &SuperProductivityJSON{Location: opts.Location}, nil
+	}
+	return nil, fmt.Errorf("unknown import source %q (want csv, toggl, pomofocus or superproductivity)", source)
+}
+
+// Options are shared importer settings
+type Options struct {
+	Mapping    Mapping        // Column mapping for generic CSV
+	TimeLayout string         // Time layout for generic CSV, RFC 3339 if empty
+	Location   *time.Location // Zone for timestamps without an offset, local if nil
+}
+
+// workRecord builds a completed work record from a start and duration
+func workRecord(start time.Time, d time.Duration, task string) history.Record {
+	return history.Record{
+		ID:        history.NewID(start),
+		Type:      timer.Work,
+		Start:     start,
+		End:       start.Add(d),
+		Planned:   d,
+		Elapsed:   d,
+		Completed: true,
+		Task:      task,
+	}
+}
+
+// location returns loc, or the local zone when nil
+func location(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.Local
+	}
+	return loc
+}
+
+// splitTags splits a tag list on commas or semicolons
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
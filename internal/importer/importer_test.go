@@ -0,0 +1,46 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	existing := []history.Record{workRecord(base, 25*time.Minute, "a")}
+	incoming := []history.Record{
+		workRecord(base.Add(2*time.Hour), 25*time.Minute, "c"),
+		workRecord(base.Add(500*time.Millisecond), 25*time.Minute, "same second"),
+		workRecord(base.Add(time.Hour), 25*time.Minute, "b"),
+		workRecord(base.Add(time.Hour), 25*time.Minute, "repeat in batch"),
+	}
+
+	report := Plan(existing, incoming)
+
+	require.Len(t, report.New, 2)
+	assert.Equal(t, "b", report.New[0].Task, "new records are sorted by start")
+	assert.Equal(t, "c", report.New[1].Task)
+	require.Len(t, report.Duplicates, 2)
+	assert.Equal(t, "same second", report.Duplicates[0].Task)
+	assert.Equal(t, "repeat in batch", report.Duplicates[1].Task)
+}
+
+func TestNew(t *testing.T) {
+	for _, source := range []string{"csv", "toggl", "Pomofocus", "superproductivity", "super-productivity"} {
+		imp, err := New(source, Options{})
+		require.NoError(t, err, source)
+		assert.NotNil(t, imp)
+	}
+
+	_, err := New("clockify", Options{})
+	assert.Error(t, err)
+}
+
+func TestSplitTags(t *testing.T) {
+	assert.Equal(t, []string{"a", "b c", "d"}, splitTags(" a, b c ;d,,"))
+	assert.Nil(t, splitTags(""))
+}
@@ -0,0 +1,181 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/history"
+)
+
+// PomofocusJSON imports a Pomofocus report export
+// The export is an array of entries, or an object holding them under "logs",
+// each with a start time, a length in minutes or an end time, and a task name
+type PomofocusJSON struct {
+	Location *time.Location
+}
+
+// pomofocusEntry is one logged pomodoro
+type pomofocusEntry struct {
+	StartTime json.RawMessage `json:"startTime"`
+	EndTime   json.RawMessage `json:"endTime"`
+	Minutes   float64         `json:"minutes"`
+	Task      string          `json:"task"`
+	TaskName  string          `json:"taskName"`
+	Project   string          `json:"project"`
+	Note      string          `json:"note"`
+}
+
+// Import implements Importer
+func (p *PomofocusJSON) Import(r io.Reader) ([]history.Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []pomofocusEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var wrapped struct {
+			Logs []pomofocusEntry `json:"logs"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("pomofocus: %w", err)
+		}
+		entries = wrapped.Logs
+	}
+
+	loc := location(p.Location)
+	var records []history.Record
+	for i, e := range entries {
+		start, err := parseJSONTime(e.StartTime, loc)
+		if err != nil {
+			return nil, fmt.Errorf("pomofocus entry %d: startTime: %w", i+1, err)
+		}
+
+		elapsed := time.Duration(e.Minutes * float64(time.Minute))
+		if elapsed == 0 && len(e.EndTime) > 0 {
+			end, err := parseJSONTime(e.EndTime, loc)
+			if err != nil {
+				return nil, fmt.Errorf("pomofocus entry %d: endTime: %w", i+1, err)
+			}
+			elapsed = end.Sub(start)
+		}
+
+		task := e.Task
+		if task == "" {
+			task = e.TaskName
+		}
+		record := workRecord(start, elapsed, task)
+		record.Project = e.Project
+		record.Note = e.Note
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseJSONTime accepts an RFC 3339 string or Unix milliseconds
+func parseJSONTime(raw json.RawMessage, loc *time.Location) (time.Time, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if t, err := time.Parse(time.RFC3339, text); err == nil {
+			return t, nil
+		}
+		return time.ParseInLocation("2006-01-02 15:04:05", text, loc)
+	}
+
+	var millis int64
+	if err := json.Unmarshal(raw, &millis); err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s", raw)
+	}
+	return time.UnixMilli(millis).In(loc), nil
+}
+
+// SuperProductivityJSON imports a Super Productivity backup file
+// Super Productivity only tracks time spent per task per day, so each
+// task-day becomes one record. Records for a day are laid end to end from
+// midnight in task order, which keeps timestamps stable across re-imports
+type SuperProductivityJSON struct {
+	Location *time.Location
+}
+
+// spTaskState is Super Productivity's normalized task collection
+type spTaskState struct {
+	IDs      []string          `json:"ids"`
+	Entities map[string]spTask `json:"entities"`
+}
+
+// spTask is the subset of a Super Productivity task we import
+type spTask struct {
+	ID             string           `json:"id"`
+	Title          string           `json:"title"`
+	Notes          string           `json:"notes"`
+	TimeSpentOnDay map[string]int64 `json:"timeSpentOnDay"` // Milliseconds keyed by YYYY-MM-DD
+}
+
+// spBackup holds the collections that contain tasks
+type spBackup struct {
+	Task        *spTaskState `json:"task"`
+	TaskArchive *spTaskState `json:"taskArchive"`
+}
+
+// Import implements Importer
+func (s *SuperProductivityJSON) Import(r io.Reader) ([]history.Record, error) {
+	var backup struct {
+		spBackup
+		Data *spBackup `json:"data"` // Newer versions wrap the state
+	}
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return nil, fmt.Errorf("superproductivity: %w", err)
+	}
+	state := backup.spBackup
+	if backup.Data != nil {
+		state = *backup.Data
+	}
+	if state.Task == nil {
+		return nil, fmt.Errorf("superproductivity: no task data found")
+	}
+
+	var tasks []spTask
+	for _, collection := range []*spTaskState{state.Task, state.TaskArchive} {
+		if collection == nil {
+			continue
+		}
+		for _, id := range collection.IDs {
+			if task, ok := collection.Entities[id]; ok {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	loc := location(s.Location)
+	dayOffset := map[string]time.Duration{}
+	var records []history.Record
+	for _, task := range tasks {
+		days := make([]string, 0, len(task.TimeSpentOnDay))
+		for day := range task.TimeSpentOnDay {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+
+		for _, day := range days {
+			spent := time.Duration(task.TimeSpentOnDay[day]) * time.Millisecond
+			if spent <= 0 {
+				continue
+			}
+			date, err := time.ParseInLocation("2006-01-02", day, loc)
+			if err != nil {
+				return nil, fmt.Errorf("superproductivity task %q: day %s: %w", task.ID, day, err)
+			}
+			start := date.Add(dayOffset[day])
+			dayOffset[day] += spent
+
+			record := workRecord(start, spent, task.Title)
+			record.Note = task.Notes
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
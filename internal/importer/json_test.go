@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPomofocusJSON(t *testing.T) {
+	data := `[
+		{"startTime": "2024-01-15T09:00:00Z", "minutes": 25, "task": "Essay", "project": "School", "note": "intro"},
+		{"startTime": 1705312800000, "endTime": 1705314300000, "taskName": "Reading"}
+	]`
+
+	records, err := (&PomofocusJSON{Location: time.UTC}).Import(strings.NewReader(data))
+
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), records[0].Start)
+	assert.Equal(t, 25*time.Minute, records[0].Elapsed)
+	assert.Equal(t, "Essay", records[0].Task)
+	assert.Equal(t, "intro", records[0].Note)
+	assert.Equal(t, "School", records[0].Project)
+	assert.Equal(t, time.UnixMilli(1705312800000).UTC(), records[1].Start)
+	assert.Equal(t, 25*time.Minute, records[1].Elapsed)
+	assert.Equal(t, "Reading", records[1].Task)
+}
+
+func TestPomofocusJSON_WrappedLogs(t *testing.T) {
+	data := `{"logs": [{"startTime": "2024-01-15 09:00:00", "minutes": 50}]}`
+
+	records, err := (&PomofocusJSON{Location: time.UTC}).Import(strings.NewReader(data))
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 50*time.Minute, records[0].Elapsed)
+}
+
+func TestPomofocusJSON_Invalid(t *testing.T) {
+	_, err := (&PomofocusJSON{}).Import(strings.NewReader(`"nope"`))
+	assert.Error(t, err)
+
+	_, err = (&PomofocusJSON{}).Import(strings.NewReader(`[{"startTime": true}]`))
+	assert.Error(t, err)
+}
+
+const superProductivityBackup = `{
+	"task": {
+		"ids": ["t2", "t1"],
+		"entities": {
+			"t1": {"id": "t1", "title": "Write docs", "timeSpentOnDay": {"2024-01-15": 1500000, "2024-01-16": 600000}},
+			"t2": {"id": "t2", "title": "Fix bug", "notes": "race in cache", "timeSpentOnDay": {"2024-01-15": 3000000}}
+		}
+	},
+	"taskArchive": {
+		"ids": ["t0"],
+		"entities": {"t0": {"id": "t0", "title": "Old task", "timeSpentOnDay": {"2024-01-10": 0}}}
+	}
+}`
+
+func TestSuperProductivityJSON(t *testing.T) {
+	records, err := (&SuperProductivityJSON{Location: time.UTC}).Import(strings.NewReader(superProductivityBackup))
+
+	require.NoError(t, err)
+	require.Len(t, records, 3, "zero-time days are skipped")
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Write docs", records[0].Task)
+	assert.Equal(t, day, records[0].Start)
+	assert.Equal(t, 25*time.Minute, records[0].Elapsed)
+	assert.Equal(t, day.AddDate(0, 0, 1), records[1].Start)
+	assert.Equal(t, "Fix bug", records[2].Task)
+	assert.Equal(t, day.Add(25*time.Minute), records[2].Start, "same-day records are laid end to end")
+	assert.Equal(t, "race in cache", records[2].Note)
+}
+
+func TestSuperProductivityJSON_StableAcrossImports(t *testing.T) {
+	first, err := (&SuperProductivityJSON{Location: time.UTC}).Import(strings.NewReader(superProductivityBackup))
+	require.NoError(t, err)
+	second, err := (&SuperProductivityJSON{Location: time.UTC}).Import(strings.NewReader(superProductivityBackup))
+	require.NoError(t, err)
+
+	report := Plan(first, second)
+
+	assert.Empty(t, report.New)
+	assert.Len(t, report.Duplicates, 3)
+}
+
+func TestSuperProductivityJSON_WrappedData(t *testing.T) {
+	data := `{"data": ` + superProductivityBackup + `}`
+
+	records, err := (&SuperProductivityJSON{Location: time.UTC}).Import(strings.NewReader(data))
+
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+}
+
+func TestSuperProductivityJSON_NoTasks(t *testing.T) {
+	_, err := (&SuperProductivityJSON{}).Import(strings.NewReader(`{"project": {}}`))
+	assert.Error(t, err)
+}
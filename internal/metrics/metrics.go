@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Session outcomes counted by pomodoro_sessions_total
+const (
+	Completed = "completed"
+	Skipped   = "skipped"
+	Voided    = "voided" // Reset after starting, so not recorded
+)
+
+// sessionTypes are the values of the type label
+var sessionTypes = []string{"work", "short_break", "long_break"}
+
+// lengthBuckets cover sessions from a minute to an hour, in seconds
+var lengthBuckets = []float64{60, 300, 600, 900, 1200, 1500, 1800, 2700, 3600}
+
+// Metrics holds the focus metrics of the running app
+type Metrics struct {
+	registry *prometheus.Registry
+	sessions *prometheus.CounterVec
+	length   *prometheus.HistogramVec
+	state    *prometheus.GaugeVec
+	today    prometheus.Gauge
+
+	mu        sync.Mutex // Guards the timer snapshot below
+	running   bool
+	remaining time.Duration
+	at        time.Time // When remaining was taken
+}
+
+// New creates the metrics with every series at zero
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		sessions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pomodoro_sessions_total",
+			Help: "Sessions that ended, by type and outcome.",
+		}, []string{"type", "outcome"}),
+		length: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "pomodoro_session_length_seconds",
+			Help:    "Time actually spent in sessions that ended.",
+			Buckets: lengthBuckets,
+		}, []string{"type"}),
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "pomodoro_state",
+			Help: "1 for the current session type and whether it is running or paused.",
+		}, []string{"type", "state"}),
+		today: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "pomodoro_pomodoros_today",
+			Help: "Work sessions completed since the start of the day.",
+		}),
+	}
+	remaining := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "pomodoro_remaining_seconds",
+		Help: "Time left in the current session.",
+	}, m.remainingSeconds)
+
+	for _, t := range sessionTypes {
+		for _, outcome := range []string{Completed, Skipped, Voided} {
+			m.sessions.WithLabelValues(t, outcome)
+		}
+		m.length.WithLabelValues(t)
+	}
+	m.registry.MustRegister(m.sessions, m.length, m.state, m.today, remaining)
+	return m
+}
+
+// Record counts a session that ended after running for elapsed
+func (m *Metrics) Record(sessionType, outcome string, elapsed time.Duration) {
+	m.sessions.WithLabelValues(sessionType, outcome).Inc()
+	m.length.WithLabelValues(sessionType).Observe(elapsed.Seconds())
+}
+
+// SetState takes a snapshot of the timer
+// Remaining time keeps counting down from it while running
+func (m *Metrics) SetState(sessionType string, running bool, remaining time.Duration) {
+	m.state.Reset()
+	state := "paused"
+	if running {
+		state = "running"
+	}
+	m.state.WithLabelValues(sessionType, state).Set(1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running, m.remaining, m.at = running, remaining, time.Now()
+}
+
+// SetToday sets the number of pomodoros completed today
+func (m *Metrics) SetToday(n int) {
+	m.today.Set(float64(n))
+}
+
+// remainingSeconds reports the time left as of now
+func (m *Metrics) remainingSeconds() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	left := m.remaining
+	if m.running {
+		left -= time.Since(m.at)
+	}
+	return max(0, left.Round(time.Second).Seconds())
+}
+
+// Handler serves the metrics, in OpenMetrics format to scrapers that ask
+// for it and the Prometheus text format otherwise
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// Server serves /metrics over HTTP
+type Server struct {
+	srv *http.Server
+	ln  net.Listener
+}
+
+// Serve starts serving the metrics on addr, e.g. "127.0.0.1:9091"
+func Serve(m *Metrics, addr string) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	s := &Server{srv: &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}, ln: ln}
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			ln.Close()
+		}
+	}()
+	return s, nil
+}
+
+// Addr returns the address being listened on
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
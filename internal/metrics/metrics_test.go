@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// get requests the metrics, accepting the given format
+func get(m *Metrics, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+// scrape fetches the metrics in the Prometheus text format and parses them
+func scrape(t *testing.T, m *Metrics) map[string]*dto.MetricFamily {
+	t.Helper()
+	rec := get(m, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, expfmt.TypeTextPlain, expfmt.ResponseFormat(rec.Header()).FormatType())
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(rec.Body)
+	require.NoError(t, err, rec.Body.String())
+	return families
+}
+
+// find returns the metric in family with the given labels
+func find(t *testing.T, family *dto.MetricFamily, labels map[string]string) *dto.Metric {
+	t.Helper()
+	require.NotNil(t, family)
+	for _, metric := range family.GetMetric() {
+		got := map[string]string{}
+		for _, pair := range metric.GetLabel() {
+			got[pair.GetName()] = pair.GetValue()
+		}
+		if assert.ObjectsAreEqual(labels, got) {
+			return metric
+		}
+	}
+	t.Fatalf("%s has no series with labels %v", family.GetName(), labels)
+	return nil
+}
+
+func TestNewExposesZeroSeries(t *testing.T) {
+	families := scrape(t, New())
+
+	sessions := families["pomodoro_sessions_total"]
+	require.NotNil(t, sessions)
+	assert.Equal(t, dto.MetricType_COUNTER, sessions.GetType())
+	assert.Len(t, sessions.GetMetric(), 9)
+	assert.Zero(t, find(t, sessions, map[string]string{"type": "work", "outcome": "voided"}).GetCounter().GetValue())
+
+	length := families["pomodoro_session_length_seconds"]
+	require.NotNil(t, length)
+	assert.Equal(t, dto.MetricType_HISTOGRAM, length.GetType())
+	assert.Len(t, length.GetMetric(), 3)
+
+	assert.Equal(t, dto.MetricType_GAUGE, families["pomodoro_pomodoros_today"].GetType())
+	assert.Equal(t, dto.MetricType_GAUGE, families["pomodoro_remaining_seconds"].GetType())
+}
+
+func TestRecord(t *testing.T) {
+	m := New()
+	m.Record("work", Completed, 25*time.Minute)
+	m.Record("work", Completed, 20*time.Minute)
+	m.Record("work", Skipped, 3*time.Minute)
+	m.Record("short_break", Voided, 30*time.Second)
+
+	families := scrape(t, m)
+	sessions := families["pomodoro_sessions_total"]
+	assert.Equal(t, 2.0, find(t, sessions, map[string]string{"type": "work", "outcome": "completed"}).GetCounter().GetValue())
+	assert.Equal(t, 1.0, find(t, sessions, map[string]string{"type": "work", "outcome": "skipped"}).GetCounter().GetValue())
+	assert.Equal(t, 1.0, find(t, sessions, map[string]string{"type": "short_break", "outcome": "voided"}).GetCounter().GetValue())
+
+	work := find(t, families["pomodoro_session_length_seconds"], map[string]string{"type": "work"}).GetHistogram()
+	assert.Equal(t, uint64(3), work.GetSampleCount())
+	assert.Equal(t, float64(48*60), work.GetSampleSum())
+	for _, b := range work.GetBucket() {
+		switch b.GetUpperBound() {
+		case 300:
+			assert.Equal(t, uint64(1), b.GetCumulativeCount())
+		case 1200:
+			assert.Equal(t, uint64(2), b.GetCumulativeCount())
+		case 1500:
+			assert.Equal(t, uint64(3), b.GetCumulativeCount())
+		}
+	}
+}
+
+func TestSetState(t *testing.T) {
+	m := New()
+	m.SetState("work", true, 10*time.Minute)
+	m.SetToday(4)
+
+	families := scrape(t, m)
+	state := families["pomodoro_state"]
+	require.Len(t, state.GetMetric(), 1)
+	assert.Equal(t, 1.0, find(t, state, map[string]string{"type": "work", "state": "running"}).GetGauge().GetValue())
+	assert.Equal(t, 4.0, families["pomodoro_pomodoros_today"].GetMetric()[0].GetGauge().GetValue())
+	assert.InDelta(t, 600, families["pomodoro_remaining_seconds"].GetMetric()[0].GetGauge().GetValue(), 1)
+
+	// Only the current state is set
+	m.SetState("short_break", false, 5*time.Minute)
+	families = scrape(t, m)
+	state = families["pomodoro_state"]
+	require.Len(t, state.GetMetric(), 1)
+	assert.Equal(t, 1.0, find(t, state, map[string]string{"type": "short_break", "state": "paused"}).GetGauge().GetValue())
+	assert.Equal(t, 300.0, families["pomodoro_remaining_seconds"].GetMetric()[0].GetGauge().GetValue())
+}
+
+func TestRemainingCountsDown(t *testing.T) {
+	m := New()
+	m.mu.Lock()
+	m.running, m.remaining, m.at = true, time.Minute, time.Now().Add(-20*time.Second)
+	m.mu.Unlock()
+	assert.Equal(t, 40.0, m.remainingSeconds())
+
+	m.mu.Lock()
+	m.at = time.Now().Add(-time.Hour)
+	m.mu.Unlock()
+	assert.Zero(t, m.remainingSeconds(), "never negative")
+}
+
+func TestOpenMetrics(t *testing.T) {
+	m := New()
+	m.Record("work", Completed, 25*time.Minute)
+
+	rec := get(m, `application/openmetrics-text; version=1.0.0`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "application/openmetrics-text; version=1.0.0"))
+	body := rec.Body.String()
+	// Counter families drop the _total suffix that their samples carry
+	assert.Contains(t, body, "# TYPE pomodoro_sessions counter\n")
+	assert.Contains(t, body, `pomodoro_sessions_total{outcome="completed",type="work"} 1.0`+"\n")
+	assert.Contains(t, body, "# TYPE pomodoro_session_length_seconds histogram\n")
+	assert.True(t, strings.HasSuffix(body, "# EOF\n"))
+}
+
+func TestServe(t *testing.T) {
+	m := New()
+	srv, err := Serve(m, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer srv.Close()
+
+	resp, err := http.Get("http://" + srv.Addr() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "# TYPE pomodoro_sessions_total counter")
+
+	_, err = Serve(m, srv.Addr())
+	assert.Error(t, err, "address in use")
+}
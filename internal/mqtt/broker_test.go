@@ -0,0 +1,113 @@
+package mqtt
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+// testBroker is an embedded MQTT 3.1.1 broker with only what these tests
+// use: QoS 0 and 1 publishes, retained messages and exact topic
+// subscriptions
+type testBroker struct {
+	ln net.Listener
+
+	mu       sync.Mutex // Guards the maps and every write to a client
+	retained map[string][]byte
+	subs     map[net.Conn][]string
+}
+
+// startBroker listens on a free local port until the test ends
+func startBroker(t *testing.T) *testBroker {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &testBroker{ln: ln, retained: map[string][]byte{}, subs: map[net.Conn][]string{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.handle(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return b
+}
+
+// URL returns the broker address for clients
+func (b *testBroker) URL() string {
+	return "tcp://" + b.ln.Addr().String()
+}
+
+// Retained returns the retained message on topic
+func (b *testBroker) Retained(topic string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	payload, ok := b.retained[topic]
+	return string(payload), ok
+}
+
+// DropClients closes every client connection without a DISCONNECT, as a
+// network failure would
+func (b *testBroker) DropClients() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn := range b.subs {
+		conn.Close()
+	}
+}
+
+func (b *testBroker) handle(conn net.Conn) {
+	b.mu.Lock()
+	b.subs[conn] = nil
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.subs, conn)
+		b.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		cp, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		switch p := cp.(type) {
+		case *packets.ConnectPacket:
+			_ = packets.NewControlPacket(packets.Connack).Write(conn)
+		case *packets.PublishPacket:
+			if p.Qos > 0 {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				_ = ack.Write(conn)
+			}
+			if p.Retain {
+				b.retained[p.TopicName] = p.Payload
+			}
+			for sub, topics := range b.subs {
+				for _, topic := range topics {
+					if topic == p.TopicName {
+						msg := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+						msg.TopicName, msg.Payload = p.TopicName, p.Payload
+						_ = msg.Write(sub)
+					}
+				}
+			}
+		case *packets.SubscribePacket:
+			b.subs[conn] = append(b.subs[conn], p.Topics...)
+			ack := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+			ack.MessageID = p.MessageID
+			ack.ReturnCodes = make([]byte, len(p.Topics))
+			_ = ack.Write(conn)
+		}
+		b.mu.Unlock()
+	}
+}
@@ -0,0 +1,168 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+)
+
+// Commands accepted on the command topic
+const (
+	Toggle = "toggle"
+	Skip   = "skip"
+)
+
+// connectTimeout bounds the first connection; later drops are retried in
+// the background
+const connectTimeout = 10 * time.Second
+
+// State is the timer as published to the broker
+type State struct {
+	Session   string        // work, short_break or long_break
+	Remaining time.Duration // Rounded down to seconds when published
+	Duration  time.Duration
+	Running   bool
+}
+
+// statePayload is the JSON published on <topic>/state
+type statePayload struct {
+	Session   string `json:"session"`
+	State     string `json:"state"` // running or paused
+	Remaining int    `json:"remaining"`
+	Duration  int    `json:"duration"`
+}
+
+// Publisher keeps retained messages describing the timer under a topic
+// root:
+//
+//	<topic>/state         JSON with all of the below
+//	<topic>/session       work, short_break or long_break
+//	<topic>/remaining     seconds left
+//	<topic>/running       true or false
+//	<topic>/availability  online, or offline once disconnected
+type Publisher struct {
+	client   paho.Client
+	root     string
+	qos      byte
+	commands chan string
+
+	mu   sync.Mutex
+	last *State // Published again after reconnecting
+}
+
+// Connect opens a connection to the configured broker
+func Connect(cfg config.MQTTConfig, creds config.LoginCredentials) (*Publisher, error) {
+	if cfg.Broker == "" {
+		return nil, errors.New("mqtt: no broker")
+	}
+	p := &Publisher{
+		root: strings.TrimRight(cfg.Topic, "/"),
+		qos:  byte(cfg.QoS),
+	}
+	if cfg.Commands {
+		p.commands = make(chan string, 8)
+	}
+
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(creds.Username).
+		SetPassword(creds.Password).
+		SetConnectTimeout(connectTimeout).
+		SetAutoReconnect(true).
+		SetWill(p.topic("availability"), "offline", p.qos, true).
+		SetOnConnectHandler(p.onConnect)
+	p.client = paho.NewClient(opts)
+
+	token := p.client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		p.client.Disconnect(0)
+		return nil, fmt.Errorf("mqtt %s: timed out connecting", cfg.Broker)
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("mqtt %s: %w", cfg.Broker, err)
+	}
+	return p, nil
+}
+
+// onConnect announces the publisher, subscribes to commands and brings the
+// retained state up to date, after every connection
+func (p *Publisher) onConnect(c paho.Client) {
+	c.Publish(p.topic("availability"), p.qos, true, "online")
+	if p.commands != nil {
+		c.Subscribe(p.topic("command"), p.qos, func(_ paho.Client, msg paho.Message) {
+			command := strings.ToLower(strings.TrimSpace(string(msg.Payload())))
+			if command != Toggle && command != Skip {
+				return
+			}
+			select {
+			case p.commands <- command:
+			default:
+				// A button mashed faster than the app keeps up
+			}
+		})
+	}
+	p.mu.Lock()
+	last := p.last
+	p.mu.Unlock()
+	if last != nil {
+		p.send(*last)
+	}
+}
+
+// Publish updates the retained state messages
+// It doesn't wait for the broker; while disconnected the latest state is
+// kept and sent on reconnecting
+func (p *Publisher) Publish(s State) {
+	p.mu.Lock()
+	p.last = &s
+	p.mu.Unlock()
+	if p.client.IsConnectionOpen() {
+		p.send(s)
+	}
+}
+
+// send publishes every state topic
+func (p *Publisher) send(s State) {
+	state := "paused"
+	if s.Running {
+		state = "running"
+	}
+	remaining := int(s.Remaining / time.Second)
+	payload, _ := json.Marshal(statePayload{
+		Session:   s.Session,
+		State:     state,
+		Remaining: remaining,
+		Duration:  int(s.Duration / time.Second),
+	})
+	p.client.Publish(p.topic("state"), p.qos, true, payload)
+	p.client.Publish(p.topic("session"), p.qos, true, s.Session)
+	p.client.Publish(p.topic("remaining"), p.qos, true, strconv.Itoa(remaining))
+	p.client.Publish(p.topic("running"), p.qos, true, strconv.FormatBool(s.Running))
+}
+
+// Commands delivers toggle and skip requests from the command topic, or
+// is nil if commands are off
+func (p *Publisher) Commands() <-chan string {
+	return p.commands
+}
+
+// Close marks the publisher offline and disconnects
+func (p *Publisher) Close() {
+	if p.client.IsConnectionOpen() {
+		p.client.Publish(p.topic("availability"), p.qos, true, "offline").WaitTimeout(time.Second)
+	}
+	p.client.Disconnect(250)
+}
+
+// topic returns a topic under the root
+func (p *Publisher) topic(name string) string {
+	return p.root + "/" + name
+}
@@ -0,0 +1,121 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connect starts a publisher against b
+func connect(t *testing.T, b *testBroker, commands bool) *Publisher {
+	t.Helper()
+	p, err := Connect(config.MQTTConfig{
+		Broker:   b.URL(),
+		ClientID: "pomodoro-test",
+		Topic:    "office/door/",
+		QoS:      1,
+		Commands: commands,
+	}, config.LoginCredentials{})
+	require.NoError(t, err)
+	t.Cleanup(p.Close)
+	return p
+}
+
+// retained waits for topic to hold want
+func retained(t *testing.T, b *testBroker, topic, want string) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		got, _ := b.Retained(topic)
+		return got == want
+	}, 5*time.Second, 10*time.Millisecond, "%s should become %q", topic, want)
+}
+
+func TestPublisher_PublishesRetainedState(t *testing.T) {
+	b := startBroker(t)
+	p := connect(t, b, false)
+
+	p.Publish(State{Session: "work", Remaining: 1499900 * time.Millisecond, Duration: 25 * time.Minute, Running: true})
+
+	retained(t, b, "office/door/session", "work")
+	retained(t, b, "office/door/remaining", "1499")
+	retained(t, b, "office/door/running", "true")
+	retained(t, b, "office/door/availability", "online")
+	state, _ := b.Retained("office/door/state")
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(state), &payload))
+	assert.Equal(t, map[string]any{"session": "work", "state": "running", "remaining": float64(1499), "duration": float64(1500)}, payload)
+
+	p.Publish(State{Session: "short_break", Remaining: 5 * time.Minute, Duration: 5 * time.Minute})
+	retained(t, b, "office/door/running", "false")
+	retained(t, b, "office/door/session", "short_break")
+}
+
+func TestPublisher_CloseMarksOffline(t *testing.T) {
+	b := startBroker(t)
+	p := connect(t, b, false)
+	retained(t, b, "office/door/availability", "online")
+
+	p.Close()
+
+	retained(t, b, "office/door/availability", "offline")
+}
+
+func TestPublisher_RepublishesAfterReconnect(t *testing.T) {
+	b := startBroker(t)
+	p := connect(t, b, false)
+	p.Publish(State{Session: "work", Remaining: time.Minute, Running: true})
+	retained(t, b, "office/door/remaining", "60")
+
+	b.mu.Lock()
+	delete(b.retained, "office/door/remaining")
+	b.mu.Unlock()
+	b.DropClients()
+
+	retained(t, b, "office/door/remaining", "60")
+}
+
+func TestPublisher_Commands(t *testing.T) {
+	b := startBroker(t)
+	p := connect(t, b, true)
+	require.NotNil(t, p.Commands())
+
+	button := paho.NewClient(paho.NewClientOptions().AddBroker(b.URL()).SetClientID("button"))
+	require.True(t, button.Connect().WaitTimeout(5*time.Second))
+	defer button.Disconnect(0)
+
+	for _, payload := range []string{"dance", " Toggle\n", "skip"} {
+		button.Publish("office/door/command", 0, false, payload).WaitTimeout(time.Second)
+	}
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case c := <-p.Commands():
+			got = append(got, c)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("commands received: %v", got)
+		}
+	}
+	assert.Equal(t, []string{Toggle, Skip}, got)
+}
+
+func TestPublisher_CommandsOff(t *testing.T) {
+	b := startBroker(t)
+	assert.Nil(t, connect(t, b, false).Commands())
+}
+
+func TestConnect_Errors(t *testing.T) {
+	_, err := Connect(config.MQTTConfig{}, config.LoginCredentials{})
+	assert.EqualError(t, err, "mqtt: no broker")
+
+	b := startBroker(t)
+	addr := b.URL()
+	b.ln.Close()
+	_, err = Connect(config.MQTTConfig{Broker: addr, ClientID: "x", Topic: "t"}, config.LoginCredentials{})
+	assert.Error(t, err)
+}
@@ -2,15 +2,32 @@ package notify
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"github.com/gen2brain/beeep"
 	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/sound"
+)
+
+// Event is a session transition with a sound of its own
+type Event string
+
+const (
+	WorkEnd  Event = "work_end"
+	BreakEnd Event = "break_end"
+	Warning  Event = "warning" // A minute before a work session ends
 )
 
 // Injectable functions for testing
 var (
-	systemNotifyFunc = beeep.Notify
-	terminalBellFunc = func() { fmt.Print("\a") }
+	systemNotifyFunc           = beeep.Notify
+	terminalBellFunc           = func() { fmt.Fprint(Output, "\a") }
+	soundOutput                = sound.Device()
+	terminalWriter   io.Writer = Output
+	getenv                     = os.Getenv
+	now                        = time.Now
 )
 
 // SetNotifyFuncsForTesting replaces notification functions for testing
@@ -23,39 +40,180 @@ func SetNotifyFuncsForTesting(sn func(string, string, any) error, tb func()) {
 	}
 }
 
+// SetSoundOutputForTesting makes notifiers created afterwards play sounds
+// through out, such as a sound.Recorder
+func SetSoundOutputForTesting(out sound.Output) {
+	soundOutput = out
+}
+
+// SetNowForTesting sets the clock quiet hours are checked against
+func SetNowForTesting(f func() time.Time) {
+	now = f
+}
+
+// SetTerminalForTesting writes terminal notifications to w and detects the
+// terminal from env instead of the process environment
+func SetTerminalForTesting(w io.Writer, env map[string]string) {
+	terminalWriter = w
+	getenv = func(key string) string { return env[key] }
+}
+
 // ResetNotifyFuncsForTesting restores default notification functions
 func ResetNotifyFuncsForTesting() {
 	systemNotifyFunc = beeep.Notify
-	terminalBellFunc = func() { fmt.Print("\a") }
+	terminalBellFunc = func() { fmt.Fprint(Output, "\a") }
+	soundOutput = sound.Device()
+	terminalWriter = Output
+	getenv = os.Getenv
+	now = time.Now
 }
 
 // Notifier handles all notification methods
 type Notifier struct {
 	config *config.Config
+	player *sound.Player
+	dnd    bool // Do not disturb, switched on by hand
 }
 
 // New creates a new Notifier with the given configuration
 func New(cfg *config.Config) *Notifier {
-	return &Notifier{config: cfg}
+	return &Notifier{config: cfg, player: sound.NewPlayer(soundOutput)}
 }
 
 // Notify triggers all enabled notification methods
 func (n *Notifier) Notify(title, message string) error {
 	var lastErr error
 
-	if n.config.Notifications.TerminalBell {
+	if n.config.Notifications.TerminalBell && !n.Muted("bell") {
 		terminalBellFunc()
 	}
 
-	if n.config.Notifications.SystemNotification {
+	if n.config.Notifications.SystemNotification && !n.Muted("system") {
 		if err := systemNotifyFunc(title, message, ""); err != nil {
 			lastErr = err
 		}
 	}
 
+	if err := n.terminalNotify(title, message); err != nil {
+		lastErr = err
+	}
+
 	return lastErr
 }
 
+// Remind repeats a notification with urgency rising with level: the
+// terminal bell first, from level 2 a system notification as well
+// Channels switched off in the config stay silent
+func (n *Notifier) Remind(level int, title, message string) error {
+	if level >= 1 && n.config.Notifications.TerminalBell && !n.Muted("bell") {
+		terminalBellFunc()
+	}
+	if level < 2 {
+		return nil
+	}
+	var lastErr error
+	if n.config.Notifications.SystemNotification && !n.Muted("system") {
+		lastErr = systemNotifyFunc(title, message, "")
+	}
+	if err := n.terminalNotify(title, message); err != nil {
+		lastErr = err
+	}
+	return lastErr
+}
+
+// TerminalProtocol returns the escape sequences used for terminal
+// notifications, detecting them from the environment when set to auto
+func (n *Notifier) TerminalProtocol() Protocol {
+	switch setting := n.config.Notifications.Terminal; setting {
+	case "", "off":
+		return ProtocolNone
+	case "auto":
+		return DetectProtocol(getenv)
+	default:
+		return Protocol(setting)
+	}
+}
+
+// terminalNotify writes a notification to the terminal the TUI runs in
+func (n *Notifier) terminalNotify(title, message string) error {
+	if n.Muted("terminal") {
+		return nil
+	}
+	seq := TerminalSequence(n.TerminalProtocol(), title, message, getenv("TMUX") != "")
+	if seq == nil {
+		return nil
+	}
+	_, err := terminalWriter.Write(seq)
+	return err
+}
+
+// PlaySound plays the sound configured for event when sound is enabled
+func (n *Notifier) PlaySound(event Event) error {
+	cfg := n.config.Sound
+	if !cfg.Enabled || n.Muted("sound") {
+		return nil
+	}
+	var name string
+	switch event {
+	case WorkEnd:
+		name = cfg.WorkEnd
+	case BreakEnd:
+		name = cfg.BreakEnd
+	case Warning:
+		name = cfg.Warning
+	}
+	if name == "" {
+		return nil
+	}
+	return n.player.Play(name, cfg.Volume)
+}
+
+// SetTicking starts or stops the ticking loop
+// It only ticks while sound and ticking are enabled
+func (n *Notifier) SetTicking(on bool) error {
+	cfg := n.config.Sound
+	if !on || !cfg.Enabled || !cfg.Ticking || cfg.Tick == "" || n.Muted("sound") {
+		n.player.StopLoop()
+		return nil
+	}
+	return n.player.Loop(cfg.Tick, cfg.Volume)
+}
+
+// Quiet returns why notifications are muted: "Do not disturb" when
+// switched on by hand, "Quiet hours" during a scheduled period, or ""
+func (n *Notifier) Quiet() string {
+	switch {
+	case n.dnd:
+		return "Do not disturb"
+	case n.config.QuietHours.Active(now()):
+		return "Quiet hours"
+	}
+	return ""
+}
+
+// Muted returns whether channel is silenced right now
+func (n *Notifier) Muted(channel string) bool {
+	return n.config.QuietHours.Mutes(channel) && n.Quiet() != ""
+}
+
+// DoNotDisturb returns whether do not disturb is switched on
+func (n *Notifier) DoNotDisturb() bool {
+	return n.dnd
+}
+
+// ToggleDoNotDisturb mutes or unmutes the quiet hours channels until
+// toggled again, whatever the schedule says
+func (n *Notifier) ToggleDoNotDisturb() {
+	n.dnd = !n.dnd
+}
+
+// ReloadSounds stops the ticking loop and forgets decoded sounds, so that
+// changed settings and edited files are picked up
+func (n *Notifier) ReloadSounds() {
+	n.player.StopLoop()
+	n.player.Forget()
+}
+
 // VisualFlash returns whether visual flash is enabled
 func (n *Notifier) VisualFlash() bool {
 	return n.config.Notifications.VisualFlash
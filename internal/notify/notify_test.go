@@ -1,11 +1,15 @@
 package notify
 
 import (
+	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/kanishkathakur1/pomodoro/internal/sound"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setupMocks(t *testing.T) (bellCalled *bool, notifyCalled *bool, notifyTitle *string, notifyMessage *string, cleanup func()) {
@@ -268,3 +272,165 @@ func TestSetNotifyFuncsForTesting_NilDoesNotReplace(t *testing.T) {
 	terminalBellFunc()
 	assert.True(t, called)
 }
+
+func newSoundNotifier(t *testing.T, s config.SoundConfig) (*Notifier, *sound.Recorder) {
+	t.Helper()
+	out := &sound.Recorder{}
+	SetSoundOutputForTesting(out)
+	t.Cleanup(ResetNotifyFuncsForTesting)
+	return New(&config.Config{Sound: s}), out
+}
+
+func TestPlaySound(t *testing.T) {
+	n, out := newSoundNotifier(t, config.SoundConfig{
+		Enabled:  true,
+		Volume:   50,
+		WorkEnd:  "chime",
+		BreakEnd: "bell",
+	})
+
+	require.NoError(t, n.PlaySound(WorkEnd))
+	require.NoError(t, n.PlaySound(BreakEnd))
+	require.NoError(t, n.PlaySound(Warning), "no warning sound is configured")
+
+	assert.Equal(t, []string{"chime", "bell"}, out.Names())
+	assert.Equal(t, 0.5, out.Played()[0].Volume)
+}
+
+func TestPlaySound_Disabled(t *testing.T) {
+	n, out := newSoundNotifier(t, config.DefaultConfig().Sound)
+
+	require.NoError(t, n.PlaySound(WorkEnd))
+	require.NoError(t, n.SetTicking(true))
+
+	assert.Empty(t, out.Played())
+}
+
+func TestPlaySound_MissingFile(t *testing.T) {
+	n, _ := newSoundNotifier(t, config.SoundConfig{Enabled: true, WorkEnd: "/no/such/file.ogg"})
+
+	assert.Error(t, n.PlaySound(WorkEnd))
+}
+
+func TestSetTicking(t *testing.T) {
+	n, out := newSoundNotifier(t, config.SoundConfig{Enabled: true, Volume: 20, Ticking: true, Tick: "tick"})
+
+	require.NoError(t, n.SetTicking(true))
+	require.NoError(t, n.SetTicking(true))
+	require.NoError(t, n.SetTicking(false))
+
+	assert.Equal(t, []sound.Played{{Name: "tick", Volume: 0.2, Loop: true, Stopped: true}}, out.Played())
+
+	require.NoError(t, n.SetTicking(true))
+	n.ReloadSounds()
+	assert.True(t, out.Played()[1].Stopped, "reloading stops the loop")
+}
+
+func TestRemind_Escalates(t *testing.T) {
+	bellCalled, notifyCalled, _, notifyMessage, cleanup := setupMocks(t)
+	defer cleanup()
+	notifier := New(config.DefaultConfig())
+
+	require.NoError(t, notifier.Remind(1, "Title", "first"))
+	assert.True(t, *bellCalled)
+	assert.False(t, *notifyCalled, "level 1 only rings the bell")
+
+	require.NoError(t, notifier.Remind(2, "Title", "second"))
+	assert.True(t, *notifyCalled)
+	assert.Equal(t, "second", *notifyMessage)
+}
+
+func TestRemind_RespectsDisabledChannels(t *testing.T) {
+	bellCalled, notifyCalled, _, _, cleanup := setupMocks(t)
+	defer cleanup()
+	notifier := New(&config.Config{})
+
+	require.NoError(t, notifier.Remind(3, "Title", "third"))
+	assert.False(t, *bellCalled)
+	assert.False(t, *notifyCalled)
+}
+
+func TestNotify_Terminal(t *testing.T) {
+	_, _, _, _, cleanup := setupMocks(t)
+	defer cleanup()
+	var out bytes.Buffer
+	SetTerminalForTesting(&out, map[string]string{"TERM": "xterm-kitty"})
+
+	cfg := &config.Config{Notifications: config.NotificationConfig{Terminal: "auto"}}
+	require.NoError(t, New(cfg).Notify("Pomodoro", "Break over"))
+	assert.Equal(t, "\x1b]99;i=pomodoro:d=0;Pomodoro\x1b\\\x1b]99;i=pomodoro:p=body;Break over\x1b\\", out.String())
+
+	out.Reset()
+	cfg.Notifications.Terminal = "osc9"
+	SetTerminalForTesting(&out, map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"})
+	require.NoError(t, New(cfg).Notify("Pomodoro", "Done"))
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]9;Pomodoro: Done\a\x1b\\", out.String())
+}
+
+func TestNotify_TerminalOff(t *testing.T) {
+	_, _, _, _, cleanup := setupMocks(t)
+	defer cleanup()
+	var out bytes.Buffer
+	SetTerminalForTesting(&out, map[string]string{"TERM_PROGRAM": "iTerm.app"})
+
+	require.NoError(t, New(config.DefaultConfig()).Notify("Pomodoro", "Done"))
+	assert.Empty(t, out.String())
+}
+
+func TestRemind_Terminal(t *testing.T) {
+	_, _, _, _, cleanup := setupMocks(t)
+	defer cleanup()
+	var out bytes.Buffer
+	SetTerminalForTesting(&out, nil)
+
+	n := New(&config.Config{Notifications: config.NotificationConfig{Terminal: "osc777"}})
+	require.NoError(t, n.Remind(1, "Title", "first"))
+	assert.Empty(t, out.String(), "level 1 only rings the bell")
+	require.NoError(t, n.Remind(2, "Title", "second"))
+	assert.Equal(t, "\x1b]777;notify;Title;second\a", out.String())
+}
+
+func TestNotify_QuietHoursMuteChosenChannels(t *testing.T) {
+	bellCalled, notifyCalled, _, _, cleanup := setupMocks(t)
+	defer cleanup()
+	SetNowForTesting(func() time.Time { return time.Date(2024, 6, 3, 23, 0, 0, 0, time.Local) })
+
+	cfg := config.DefaultConfig()
+	cfg.QuietHours = config.QuietHoursConfig{Schedule: []string{"22:00-07:00"}, Mute: []string{"bell"}}
+	n := New(cfg)
+
+	assert.Equal(t, "Quiet hours", n.Quiet())
+	require.NoError(t, n.Notify("Title", "Message"))
+	assert.False(t, *bellCalled, "the bell is muted")
+	assert.True(t, *notifyCalled, "system notifications aren't")
+
+	SetNowForTesting(func() time.Time { return time.Date(2024, 6, 3, 9, 0, 0, 0, time.Local) })
+	assert.Empty(t, n.Quiet())
+	require.NoError(t, n.Notify("Title", "Message"))
+	assert.True(t, *bellCalled)
+}
+
+func TestToggleDoNotDisturb(t *testing.T) {
+	bellCalled, notifyCalled, _, _, cleanup := setupMocks(t)
+	defer cleanup()
+	out := &sound.Recorder{}
+	SetSoundOutputForTesting(out)
+	cfg := config.DefaultConfig()
+	cfg.Sound.Enabled = true
+	n := New(cfg)
+
+	n.ToggleDoNotDisturb()
+	assert.True(t, n.DoNotDisturb())
+	assert.Equal(t, "Do not disturb", n.Quiet())
+	require.NoError(t, n.Notify("Title", "Message"))
+	require.NoError(t, n.Remind(3, "Title", "Message"))
+	require.NoError(t, n.PlaySound(WorkEnd))
+	assert.False(t, *bellCalled)
+	assert.False(t, *notifyCalled)
+	assert.Empty(t, out.Played())
+
+	n.ToggleDoNotDisturb()
+	assert.False(t, n.DoNotDisturb())
+	require.NoError(t, n.Notify("Title", "Message"))
+	assert.True(t, *bellCalled)
+}
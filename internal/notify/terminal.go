@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Protocol is a family of terminal escape sequences that show a desktop
+// notification from inside the terminal, which also works over SSH
+type Protocol string
+
+const (
+	ProtocolNone Protocol = ""
+	OSC9         Protocol = "osc9"   // iTerm2, Windows Terminal, WezTerm, ConEmu
+	OSC777       Protocol = "osc777" // rxvt-unicode, foot, Ghostty, Warp
+	Kitty        Protocol = "kitty"  // kitty's OSC 99
+)
+
+// Escape sequence framing
+const (
+	esc = "\x1b"
+	bel = "\a"
+	st  = esc + `\`
+)
+
+// TerminalOutput is the terminal the TUI draws on
+// Writes are serialized so a notification can't land in the middle of a
+// frame, and the file is exposed so Bubble Tea still sees a terminal
+type TerminalOutput struct {
+	*os.File
+	mu sync.Mutex
+}
+
+// NewTerminalOutput wraps f, usually os.Stdout
+func NewTerminalOutput(f *os.File) *TerminalOutput {
+	return &TerminalOutput{File: f}
+}
+
+// Write implements io.Writer
+func (o *TerminalOutput) Write(b []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.File.Write(b)
+}
+
+// Output is shared by the TUI and the notifications written as escape
+// sequences; pass it to tea.WithOutput
+var Output = NewTerminalOutput(os.Stdout)
+
+// DetectProtocol picks the notification sequences the terminal understands
+// from its environment, or ProtocolNone if it isn't known to support any
+func DetectProtocol(getenv func(string) string) Protocol {
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm":
+		return OSC9
+	case "ghostty", "WarpTerminal":
+		return OSC777
+	}
+
+	term := getenv("TERM")
+	switch {
+	case term == "xterm-kitty" || getenv("KITTY_WINDOW_ID") != "":
+		return Kitty
+	case term == "xterm-ghostty", strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "rxvt-unicode"):
+		return OSC777
+	case term == "wezterm", getenv("WT_SESSION") != "", getenv("ConEmuPID") != "":
+		return OSC9
+	case getenv("LC_TERMINAL") == "iTerm2":
+		// Set by iTerm2 and passed on over SSH
+		return OSC9
+	}
+	return ProtocolNone
+}
+
+// TerminalSequence returns the bytes that make the terminal show a
+// notification, wrapped for tmux passthrough if inTmux is set
+// Control characters in title and body are replaced so they can't end
+// the sequence early
+func TerminalSequence(p Protocol, title, body string, inTmux bool) []byte {
+	title, body = printable(title), printable(body)
+
+	var seq string
+	switch p {
+	case OSC9:
+		// OSC 9 has no title, so it leads the message
+		message := body
+		if title != "" {
+			message = title + ": " + body
+		}
+		seq = esc + "]9;" + message + bel
+	case OSC777:
+		// The title ends at the first semicolon
+		seq = esc + "]777;notify;" + strings.ReplaceAll(title, ";", ",") + ";" + body + bel
+	case Kitty:
+		// The title is sent first with d=0 to say the body follows
+		seq = esc + "]99;i=pomodoro:d=0;" + title + st +
+			esc + "]99;i=pomodoro:p=body;" + body + st
+	default:
+		return nil
+	}
+
+	if inTmux {
+		// tmux forwards DCS tmux; sequences with escapes doubled, given
+		// allow-passthrough is on
+		seq = esc + "Ptmux;" + strings.ReplaceAll(seq, esc, esc+esc) + st
+	}
+	return []byte(seq)
+}
+
+// printable replaces control characters with spaces
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
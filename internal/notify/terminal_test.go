@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol Protocol
+		want     string
+	}{
+		{"osc9", OSC9, "\x1b]9;Pomodoro: Time for a break\a"},
+		{"osc777", OSC777, "\x1b]777;notify;Pomodoro;Time for a break\a"},
+		{"kitty", Kitty, "\x1b]99;i=pomodoro:d=0;Pomodoro\x1b\\\x1b]99;i=pomodoro:p=body;Time for a break\x1b\\"},
+		{"none", ProtocolNone, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TerminalSequence(tt.protocol, "Pomodoro", "Time for a break", false)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestTerminalSequence_OSC9WithoutTitle(t *testing.T) {
+	assert.Equal(t, "\x1b]9;Done\a", string(TerminalSequence(OSC9, "", "Done", false)))
+}
+
+func TestTerminalSequence_Tmux(t *testing.T) {
+	got := TerminalSequence(OSC9, "Pomodoro", "Done", true)
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]9;Pomodoro: Done\a\x1b\\", string(got))
+
+	got = TerminalSequence(Kitty, "A", "B", true)
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]99;i=pomodoro:d=0;A\x1b\x1b\\\x1b\x1b]99;i=pomodoro:p=body;B\x1b\x1b\\\x1b\\", string(got))
+}
+
+func TestTerminalSequence_StripsControlCharacters(t *testing.T) {
+	got := TerminalSequence(OSC777, "a;b\x1b]", "line\nbreak\a", false)
+	assert.Equal(t, "\x1b]777;notify;a,b ];line break \a", string(got))
+}
+
+func TestDetectProtocol(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Protocol
+	}{
+		{"iTerm2", map[string]string{"TERM_PROGRAM": "iTerm.app"}, OSC9},
+		{"WezTerm", map[string]string{"TERM_PROGRAM": "WezTerm"}, OSC9},
+		{"Ghostty", map[string]string{"TERM_PROGRAM": "ghostty"}, OSC777},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{"kitty in tmux", map[string]string{"TERM": "tmux-256color", "KITTY_WINDOW_ID": "1"}, Kitty},
+		{"foot", map[string]string{"TERM": "foot-extra"}, OSC777},
+		{"urxvt", map[string]string{"TERM": "rxvt-unicode-256color"}, OSC777},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "abc"}, OSC9},
+		{"iTerm2 over ssh", map[string]string{"TERM": "xterm-256color", "LC_TERMINAL": "iTerm2"}, OSC9},
+		{"unknown", map[string]string{"TERM": "xterm-256color"}, ProtocolNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectProtocol(func(key string) string { return tt.env[key] }))
+		})
+	}
+}
+
+func TestTerminalOutput_Write(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	defer f.Close()
+
+	out := NewTerminalOutput(f)
+	_, err = out.Write([]byte("frame"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal([]byte("frame"), data))
+	assert.Equal(t, f.Fd(), out.Fd(), "the file stays visible to the TUI")
+}
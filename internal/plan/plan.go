@@ -0,0 +1,286 @@
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is how plans name their day
+const dateLayout = "2006-01-02"
+
+// Block is a stretch of the day: a number of work sessions on one task, or
+// a long break
+type Block struct {
+	Task  string `json:"task,omitempty"`
+	Count int    `json:"count,omitempty"` // Work sessions planned
+	Start string `json:"start,omitempty"` // HH:MM, empty to follow the block before
+	Break bool   `json:"break,omitempty"` // A long break instead of work
+
+	// What actually happened
+	Done    int       `json:"done,omitempty"`    // Work sessions completed, or 1 once the break is over
+	Started time.Time `json:"started,omitempty"` // When the first session began
+}
+
+// Finished returns whether the block has been worked through
+func (b Block) Finished() bool {
+	if b.Break {
+		return b.Done > 0
+	}
+	return b.Done >= b.Count
+}
+
+// String renders the block the way Parse reads it, e.g. "14:00 3 Reviews"
+func (b Block) String() string {
+	var parts []string
+	if b.Start != "" {
+		parts = append(parts, b.Start)
+	}
+	if b.Break {
+		parts = append(parts, "long break")
+	} else {
+		parts = append(parts, strconv.Itoa(b.Count), b.Task)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Label describes the block for display, e.g. "Reviews ×2 at 14:00"
+func (b Block) Label() string {
+	label := "Long break"
+	if !b.Break {
+		label = b.Task
+		if b.Count != 1 {
+			label += fmt.Sprintf(" ×%d", b.Count)
+		}
+	}
+	if b.Start != "" {
+		label += " at " + b.Start
+	}
+	return label
+}
+
+// Parse reads a block written like "3 API refactor", "14:00 2x Reviews",
+// "3 pomodoros on API refactor" or "long break"
+// Without a count a block is a single session
+func Parse(line string) (Block, error) {
+	fields := strings.Fields(line)
+	var b Block
+	if len(fields) > 0 && strings.Contains(fields[0], ":") {
+		if _, err := clock(fields[0]); err != nil {
+			return Block{}, err
+		}
+		b.Start = fields[0]
+		fields = fields[1:]
+	}
+
+	if rest := strings.ToLower(strings.Join(fields, " ")); rest == "long break" || rest == "break" {
+		b.Break = true
+		return b, nil
+	}
+
+	b.Count = 1
+	if len(fields) > 0 {
+		count := strings.TrimRight(strings.ToLower(fields[0]), "x×")
+		if n, err := strconv.Atoi(count); err == nil {
+			if n < 1 {
+				return Block{}, fmt.Errorf("%d sessions: want at least 1", n)
+			}
+			b.Count = n
+			fields = fields[1:]
+			if len(fields) > 0 && strings.HasPrefix(strings.ToLower(fields[0]), "pomodoro") {
+				fields = fields[1:]
+			}
+			if len(fields) > 0 && strings.EqualFold(fields[0], "on") {
+				fields = fields[1:]
+			}
+		}
+	}
+	b.Task = strings.Join(fields, " ")
+	if b.Task == "" {
+		return Block{}, errors.New("no task, write e.g. \"3 API refactor\" or \"long break\"")
+	}
+	return b, nil
+}
+
+// clock parses HH:MM into the time since midnight
+func clock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q: want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Plan is the queue of blocks for one day
+type Plan struct {
+	Date   string  `json:"date"` // YYYY-MM-DD
+	Blocks []Block `json:"blocks"`
+
+	dayStart time.Duration // When the day begins; blocks planned earlier fall after midnight
+}
+
+// New creates an empty plan for the day that begins at day
+func New(day time.Time) *Plan {
+	return &Plan{Date: day.Format(dateLayout), dayStart: timeOfDay(day)}
+}
+
+// timeOfDay returns the wall clock time of t as the time since midnight
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+// For returns whether the plan is for the day that begins at day
+func (p *Plan) For(day time.Time) bool {
+	return p.Date == day.Format(dateLayout)
+}
+
+// Before returns whether the plan is for a day earlier than the one that
+// begins at day
+func (p *Plan) Before(day time.Time) bool {
+	return p.Date < day.Format(dateLayout)
+}
+
+// StartTime returns when block i is planned to start in loc, if it has a
+// time
+// The time is read on the wall clock, so a DST change doesn't move it, and
+// a time before the day start is in the small hours of the next date
+func (p *Plan) StartTime(i int, loc *time.Location) (time.Time, bool) {
+	offset, err := clock(p.Blocks[i].Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	day, err := time.Parse(dateLayout, p.Date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if offset < p.dayStart {
+		day = day.AddDate(0, 0, 1)
+	}
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc), true
+}
+
+// Current returns the index of the block being worked on, or -1 once
+// every block is finished
+// Blocks are worked in order, but once a block's start time has come the
+// unfinished blocks before it are left behind
+func (p *Plan) Current(now time.Time) int {
+	current := -1
+	for i, b := range p.Blocks {
+		if b.Finished() {
+			continue
+		}
+		if current < 0 {
+			current = i
+			continue
+		}
+		if start, ok := p.StartTime(i, now.Location()); ok && !start.After(now) {
+			current = i
+		}
+	}
+	return current
+}
+
+// Next returns the index of the block after the current one, or -1
+func (p *Plan) Next(now time.Time) int {
+	current := p.Current(now)
+	if current < 0 {
+		return -1
+	}
+	for i := current + 1; i < len(p.Blocks); i++ {
+		if !p.Blocks[i].Finished() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Record counts a session towards block i
+func (p *Plan) Record(i int, started time.Time) {
+	b := &p.Blocks[i]
+	if b.Started.IsZero() {
+		b.Started = started
+	}
+	b.Done++
+}
+
+// Totals returns the work sessions planned and done
+func (p *Plan) Totals() (planned, done int) {
+	for _, b := range p.Blocks {
+		if !b.Break {
+			planned += b.Count
+			done += min(b.Done, b.Count)
+		}
+	}
+	return planned, done
+}
+
+// DefaultPath returns where the plan is kept
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "pomodoro", "plan.json"), nil
+}
+
+// Load reads the plan at path, or returns an empty one for day if there is
+// none or it was made for another day
+func Load(path string, day time.Time) (*Plan, error) {
+	p, err := Read(path)
+	if err != nil {
+		return New(day), err
+	}
+	if p == nil || !p.For(day) {
+		return New(day), nil
+	}
+	p.dayStart = timeOfDay(day)
+	return p, nil
+}
+
+// Read returns the plan at path whatever day it was made for, or nil if
+// there is none
+func Read(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return &p, nil
+}
+
+// Save writes the plan to path, replacing it atomically
+func (p *Plan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
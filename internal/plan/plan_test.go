@@ -0,0 +1,231 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var day = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected Block
+	}{
+		{"3 API refactor", Block{Task: "API refactor", Count: 3}},
+		{"3 pomodoros on API refactor", Block{Task: "API refactor", Count: 3}},
+		{"2x reviews", Block{Task: "reviews", Count: 2}},
+		{"2× reviews", Block{Task: "reviews", Count: 2}},
+		{"14:00 2 Reviews", Block{Task: "Reviews", Count: 2, Start: "14:00"}},
+		{"Write docs", Block{Task: "Write docs", Count: 1}},
+		{"1 pomodoro on inbox", Block{Task: "inbox", Count: 1}},
+		{"long break", Block{Break: true}},
+		{"12:30 Break", Block{Break: true, Start: "12:30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			b, err := Parse(tt.line)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, b)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, line := range []string{"", "3", "25:00 reviews", "0 reviews", "14:00"} {
+		t.Run(line, func(t *testing.T) {
+			_, err := Parse(line)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBlock_StringRoundTrip(t *testing.T) {
+	for _, b := range []Block{
+		{Task: "API refactor", Count: 3},
+		{Task: "Reviews", Count: 2, Start: "14:00"},
+		{Break: true},
+		{Break: true, Start: "12:30"},
+	} {
+		parsed, err := Parse(b.String())
+		require.NoError(t, err)
+		assert.Equal(t, b, parsed)
+	}
+}
+
+func TestBlock_Label(t *testing.T) {
+	assert.Equal(t, "API refactor ×3", Block{Task: "API refactor", Count: 3}.Label())
+	assert.Equal(t, "Inbox", Block{Task: "Inbox", Count: 1}.Label())
+	assert.Equal(t, "Reviews ×2 at 14:00", Block{Task: "Reviews", Count: 2, Start: "14:00"}.Label())
+	assert.Equal(t, "Long break", Block{Break: true}.Label())
+}
+
+func testPlan() *Plan {
+	p := New(day)
+	p.Blocks = []Block{
+		{Task: "API refactor", Count: 3},
+		{Break: true},
+		{Task: "Reviews", Count: 2, Start: "14:00"},
+	}
+	return p
+}
+
+func TestCurrent_InOrder(t *testing.T) {
+	p := testPlan()
+	morning := day.Add(9 * time.Hour)
+
+	assert.Equal(t, 0, p.Current(morning))
+	assert.Equal(t, 1, p.Next(morning))
+
+	for range 3 {
+		p.Record(0, morning)
+	}
+	assert.Equal(t, 1, p.Current(morning))
+	assert.Equal(t, 2, p.Next(morning))
+
+	p.Record(1, morning)
+	assert.Equal(t, 2, p.Current(morning))
+	assert.Equal(t, -1, p.Next(morning))
+
+	p.Record(2, morning)
+	p.Record(2, morning)
+	assert.Equal(t, -1, p.Current(morning))
+	assert.Equal(t, -1, p.Next(morning))
+}
+
+func TestCurrent_FixedStartJumpsAhead(t *testing.T) {
+	p := testPlan()
+	p.Record(0, day.Add(9*time.Hour))
+
+	assert.Equal(t, 0, p.Current(day.Add(13*time.Hour+59*time.Minute)))
+	assert.Equal(t, 2, p.Current(day.Add(14*time.Hour)))
+	assert.Equal(t, -1, p.Next(day.Add(14*time.Hour)), "the blocks left behind don't come back")
+}
+
+func TestRecord_KeepsFirstStart(t *testing.T) {
+	p := testPlan()
+	first := day.Add(9 * time.Hour)
+
+	p.Record(0, first)
+	p.Record(0, first.Add(30*time.Minute))
+
+	assert.Equal(t, 2, p.Blocks[0].Done)
+	assert.True(t, p.Blocks[0].Started.Equal(first))
+}
+
+func TestTotals(t *testing.T) {
+	p := testPlan()
+	p.Record(0, day)
+	p.Record(1, day)
+	for range 3 {
+		p.Record(2, day) // An extra session doesn't count twice
+	}
+
+	planned, done := p.Totals()
+
+	assert.Equal(t, 5, planned)
+	assert.Equal(t, 3, done)
+}
+
+func TestStartTime(t *testing.T) {
+	p := testPlan()
+
+	start, ok := p.StartTime(2, time.UTC)
+	require.True(t, ok)
+	assert.Equal(t, day.Add(14*time.Hour), start)
+
+	_, ok = p.StartTime(0, time.UTC)
+	assert.False(t, ok)
+}
+
+func TestStartTime_AcrossDSTChange(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	p := New(time.Date(2026, 3, 8, 0, 0, 0, 0, ny))
+	p.Blocks = []Block{{Task: "Reviews", Count: 2, Start: "14:00"}}
+
+	start, ok := p.StartTime(0, ny)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 8, 14, 0, 0, 0, ny), start, "clocks went forward at 02:00")
+}
+
+func TestStartTime_BeforeDayStart(t *testing.T) {
+	p := New(day.Add(4 * time.Hour))
+	p.Blocks = []Block{
+		{Task: "Late", Count: 1, Start: "23:00"},
+		{Task: "Night", Count: 1, Start: "01:30"},
+	}
+
+	late, _ := p.StartTime(0, time.UTC)
+	assert.Equal(t, day.Add(23*time.Hour), late)
+	night, _ := p.StartTime(1, time.UTC)
+	assert.Equal(t, day.Add(25*time.Hour+30*time.Minute), night, "still the same day, after midnight")
+
+	assert.Equal(t, 0, p.Current(day.Add(23*time.Hour)))
+	assert.Equal(t, 1, p.Current(day.Add(25*time.Hour+30*time.Minute)))
+}
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pomodoro", "plan.json")
+	p := testPlan()
+	p.Record(0, day.Add(9*time.Hour))
+
+	require.NoError(t, p.Save(path))
+	loaded, err := Load(path, day)
+
+	require.NoError(t, err)
+	assert.Equal(t, p.Date, loaded.Date)
+	require.Len(t, loaded.Blocks, 3)
+	assert.Equal(t, 1, loaded.Blocks[0].Done)
+	assert.True(t, loaded.Blocks[0].Started.Equal(day.Add(9*time.Hour)))
+	assert.Equal(t, "14:00", loaded.Blocks[2].Start)
+
+	loaded, err = Load(path, day.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, loaded.Blocks, 3)
+	start, _ := loaded.StartTime(2, time.UTC)
+	assert.Equal(t, day.Add(14*time.Hour), start)
+	loaded.Blocks[2].Start = "02:00"
+	start, _ = loaded.StartTime(2, time.UTC)
+	assert.Equal(t, day.Add(26*time.Hour), start, "the day start comes from the day loaded for")
+}
+
+func TestLoad_Missing(t *testing.T) {
+	p, err := Load(filepath.Join(t.TempDir(), "plan.json"), day)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-02", p.Date)
+	assert.Empty(t, p.Blocks)
+}
+
+func TestLoad_OtherDayStartsFresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, testPlan().Save(path))
+
+	p, err := Load(path, day.AddDate(0, 0, 1))
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-03", p.Date)
+	assert.Empty(t, p.Blocks)
+
+	stored, err := Read(path)
+	require.NoError(t, err)
+	assert.Len(t, stored.Blocks, 3, "the earlier day's plan can still be read")
+	assert.True(t, stored.Before(day.AddDate(0, 0, 1)))
+	assert.False(t, stored.Before(day))
+}
+
+func TestLoad_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+
+	p, err := Load(path, day)
+
+	assert.ErrorContains(t, err, "plan.json")
+	assert.Empty(t, p.Blocks)
+}
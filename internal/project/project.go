@@ -0,0 +1,47 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+)
+
+// Detect returns the default project for sessions started in dir
+// The git repository name wins over the directory name, which wins over
+// the configured default
+func Detect(cfg config.ProjectConfig, dir string) string {
+	if cfg.FromGit {
+		if root, ok := GitRoot(dir); ok {
+			return filepath.Base(root)
+		}
+	}
+	if cfg.FromDirectory && dir != "" {
+		if abs, err := filepath.Abs(dir); err == nil && abs != string(filepath.Separator) {
+			return filepath.Base(abs)
+		}
+	}
+	return cfg.Default
+}
+
+// GitRoot walks up from dir to the directory containing .git
+// A .git file (worktrees, submodules) counts as well as a directory
+func GitRoot(dir string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
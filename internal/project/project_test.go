@@ -0,0 +1,73 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeRepo(t *testing.T) (root, nested string) {
+	t.Helper()
+	root = filepath.Join(t.TempDir(), "api-server")
+	nested = filepath.Join(root, "internal", "handlers")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+	return root, nested
+}
+
+func TestGitRoot(t *testing.T) {
+	root, nested := makeRepo(t)
+
+	found, ok := GitRoot(nested)
+
+	assert.True(t, ok)
+	assert.Equal(t, root, found)
+}
+
+func TestGitRoot_WorktreeFile(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "worktree")
+	require.NoError(t, os.MkdirAll(root, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".git"), []byte("gitdir: /elsewhere\n"), 0644))
+
+	found, ok := GitRoot(root)
+
+	assert.True(t, ok)
+	assert.Equal(t, root, found)
+}
+
+func TestGitRoot_NotARepo(t *testing.T) {
+	_, ok := GitRoot(t.TempDir())
+	assert.False(t, ok)
+
+	_, ok = GitRoot("")
+	assert.False(t, ok)
+}
+
+func TestDetect(t *testing.T) {
+	_, nested := makeRepo(t)
+	plain := filepath.Join(t.TempDir(), "notes")
+	require.NoError(t, os.Mkdir(plain, 0755))
+
+	tests := []struct {
+		name     string
+		cfg      config.ProjectConfig
+		dir      string
+		expected string
+	}{
+		{"git repository name", config.ProjectConfig{FromGit: true, Default: "misc"}, nested, "api-server"},
+		{"git disabled uses directory", config.ProjectConfig{FromDirectory: true}, nested, "handlers"},
+		{"no repo falls back to directory", config.ProjectConfig{FromGit: true, FromDirectory: true}, plain, "notes"},
+		{"no repo falls back to default", config.ProjectConfig{FromGit: true, Default: "misc"}, plain, "misc"},
+		{"nothing configured", config.ProjectConfig{}, nested, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Detect(tt.cfg, tt.dir))
+		})
+	}
+}
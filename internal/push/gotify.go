@@ -0,0 +1,49 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Gotify posts messages to a Gotify server as an application
+type Gotify struct {
+	URL    string // Server, e.g. https://gotify.example.com
+	Token  string // Application token
+	Client *http.Client
+}
+
+// NewGotify creates a sender for the application with token
+func NewGotify(url, token string) *Gotify {
+	return &Gotify{URL: url, Token: token, Client: http.DefaultClient}
+}
+
+// gotifyMessage is the body of POST /message
+type gotifyMessage struct {
+	Title    string `json:"title,omitempty"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// Name implements Sender
+func (g *Gotify) Name() string {
+	return "gotify"
+}
+
+// Send implements Sender
+func (g *Gotify) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(gotifyMessage{Title: msg.Title, Message: msg.Body, Priority: msg.Priority})
+	if err != nil {
+		return err
+	}
+	url := strings.TrimRight(g.URL, "/") + "/message"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", g.Token)
+	return do(g.Client, g.Name(), req)
+}
@@ -0,0 +1,50 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGotify_Send(t *testing.T) {
+	var got *http.Request
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+
+	g := NewGotify(srv.URL, "app-token")
+	err := g.Send(context.Background(), Message{Title: "Work Session Complete!", Body: "Time for a break.", Priority: 8})
+
+	require.NoError(t, err)
+	assert.Equal(t, "/message", got.URL.Path)
+	assert.Equal(t, "app-token", got.Header.Get("X-Gotify-Key"))
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, map[string]any{
+		"title":    "Work Session Complete!",
+		"message":  "Time for a break.",
+		"priority": float64(8),
+	}, body)
+}
+
+func TestGotify_SendRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := NewGotify(srv.URL, "wrong").Send(context.Background(), Message{Body: "hi"})
+
+	var status *StatusError
+	require.ErrorAs(t, err, &status)
+	assert.Equal(t, http.StatusUnauthorized, status.Code)
+	assert.False(t, status.Temporary())
+}
@@ -0,0 +1,49 @@
+package push
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Ntfy publishes to a topic on an ntfy server
+type Ntfy struct {
+	URL    string // Server, e.g. https://ntfy.sh
+	Topic  string
+	Token  string // Access token or "user:password", empty for none
+	Client *http.Client
+}
+
+// NewNtfy creates a sender for topic on the server at url
+func NewNtfy(url, topic, token string) *Ntfy {
+	return &Ntfy{URL: url, Topic: topic, Token: token, Client: http.DefaultClient}
+}
+
+// Name implements Sender
+func (n *Ntfy) Name() string {
+	return "ntfy"
+}
+
+// Send implements Sender
+// The body is the message and the rest goes in headers, ntfy's plain
+// publishing form
+func (n *Ntfy) Send(ctx context.Context, msg Message) error {
+	url := strings.TrimRight(n.URL, "/") + "/" + n.Topic
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(msg.Body))
+	if err != nil {
+		return err
+	}
+	if msg.Title != "" {
+		req.Header.Set("Title", msg.Title)
+	}
+	if msg.Priority > 0 {
+		req.Header.Set("Priority", strconv.Itoa(msg.Priority))
+	}
+	if user, password, ok := strings.Cut(n.Token, ":"); ok {
+		req.SetBasicAuth(user, password)
+	} else if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+	return do(n.Client, n.Name(), req)
+}
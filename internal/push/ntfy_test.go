@@ -0,0 +1,46 @@
+package push
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNtfy_Send(t *testing.T) {
+	var got *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got, body = r, string(data)
+	}))
+	defer srv.Close()
+
+	n := NewNtfy(srv.URL+"/", "focus", "tk_secret")
+	err := n.Send(context.Background(), Message{Title: "Break Over!", Body: "Ready to focus again?", Priority: 4})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "/focus", got.URL.Path)
+	assert.Equal(t, "Ready to focus again?", body)
+	assert.Equal(t, "Break Over!", got.Header.Get("Title"))
+	assert.Equal(t, "4", got.Header.Get("Priority"))
+	assert.Equal(t, "Bearer tk_secret", got.Header.Get("Authorization"))
+}
+
+func TestNtfy_SendBasicAuth(t *testing.T) {
+	var user, password string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ = r.BasicAuth()
+		assert.Empty(t, r.Header.Get("Priority"), "no priority leaves the default")
+	}))
+	defer srv.Close()
+
+	require.NoError(t, NewNtfy(srv.URL, "focus", "me:pass").Send(context.Background(), Message{Body: "hi"}))
+	assert.Equal(t, "me", user)
+	assert.Equal(t, "pass", password)
+}
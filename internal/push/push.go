@@ -0,0 +1,117 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+)
+
+// Message is a notification sent to a push server
+type Message struct {
+	Title    string
+	Body     string
+	Priority int // 0 leaves it to the server
+}
+
+// Sender delivers messages to a push server
+type Sender interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// FromConfig creates the sender for the configured service
+func FromConfig(cfg config.PushConfig) (Sender, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("push: no server url")
+	}
+	switch cfg.Service {
+	case "", "ntfy":
+		if cfg.Topic == "" {
+			return nil, errors.New("push: ntfy needs a topic")
+		}
+		return NewNtfy(cfg.URL, cfg.Topic, cfg.Token), nil
+	case "gotify":
+		if cfg.Token == "" {
+			return nil, errors.New("push: gotify needs an application token")
+		}
+		return NewGotify(cfg.URL, cfg.Token), nil
+	}
+	return nil, fmt.Errorf("push: unknown service %q", cfg.Service)
+}
+
+// Deliver sends msg, trying again up to retries times after failures that
+// may pass, waiting backoff and then twice as long each time
+// It blocks until done, so run it off the UI goroutine
+func Deliver(ctx context.Context, s Sender, msg Message, retries int, backoff time.Duration) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = s.Send(ctx, msg); err == nil {
+			return nil
+		}
+		var status *StatusError
+		if errors.As(err, &status) && !status.Temporary() {
+			return err
+		}
+		if attempt >= retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff << attempt):
+		}
+	}
+}
+
+// StatusError is a response from the server other than success
+type StatusError struct {
+	Service string
+	Code    int
+	Message string
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: %d %s", e.Service, e.Code, http.StatusText(e.Code))
+	}
+	return fmt.Sprintf("%s: %d %s", e.Service, e.Code, e.Message)
+}
+
+// Temporary returns whether trying again later may succeed
+func (e *StatusError) Temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= 500
+}
+
+// do sends req and turns an unsuccessful response into a StatusError
+func do(client *http.Client, service string, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", service, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return &StatusError{Service: service, Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+}
+
+// Expand fills in {title}, {message}, {session}, {task} and {project} in a
+// message template, returning the message itself for an empty template
+func Expand(template string, vars map[string]string) string {
+	if template == "" {
+		return vars["message"]
+	}
+	pairs := make([]string, 0, 2*len(vars))
+	for name, value := range vars {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
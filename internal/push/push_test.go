@@ -0,0 +1,92 @@
+package push
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer fails the first failures requests with status, then succeeds
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			http.Error(w, "try later", status)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestDeliver_RetriesTemporaryFailures(t *testing.T) {
+	srv, calls := flakyServer(t, 2, http.StatusServiceUnavailable)
+
+	err := Deliver(context.Background(), NewNtfy(srv.URL, "t", ""), Message{Body: "hi"}, 3, time.Millisecond)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestDeliver_GivesUpAfterRetries(t *testing.T) {
+	srv, calls := flakyServer(t, 10, http.StatusBadGateway)
+
+	err := Deliver(context.Background(), NewNtfy(srv.URL, "t", ""), Message{Body: "hi"}, 2, time.Millisecond)
+
+	var status *StatusError
+	require.ErrorAs(t, err, &status)
+	assert.Equal(t, http.StatusBadGateway, status.Code)
+	assert.Equal(t, "ntfy: 502 try later", err.Error())
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestDeliver_DoesNotRetryRejections(t *testing.T) {
+	srv, calls := flakyServer(t, 10, http.StatusForbidden)
+
+	err := Deliver(context.Background(), NewNtfy(srv.URL, "t", ""), Message{Body: "hi"}, 3, time.Millisecond)
+
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDeliver_StopsWhenCancelled(t *testing.T) {
+	srv, calls := flakyServer(t, 10, http.StatusServiceUnavailable)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := Deliver(ctx, NewNtfy(srv.URL, "t", ""), Message{Body: "hi"}, 5, time.Hour)
+
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestFromConfig(t *testing.T) {
+	s, err := FromConfig(config.PushConfig{Service: "ntfy", URL: "https://ntfy.sh", Topic: "focus"})
+	require.NoError(t, err)
+	assert.Equal(t, "ntfy", s.Name())
+
+	s, err = FromConfig(config.PushConfig{Service: "gotify", URL: "https://gotify.test", Token: "app"})
+	require.NoError(t, err)
+	assert.Equal(t, "gotify", s.Name())
+
+	_, err = FromConfig(config.PushConfig{Service: "ntfy", URL: "https://ntfy.sh"})
+	assert.EqualError(t, err, "push: ntfy needs a topic")
+	_, err = FromConfig(config.PushConfig{Service: "gotify", URL: "https://gotify.test"})
+	assert.EqualError(t, err, "push: gotify needs an application token")
+	_, err = FromConfig(config.PushConfig{Service: "ntfy", Topic: "focus"})
+	assert.EqualError(t, err, "push: no server url")
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"message": "Time for a break.", "session": "Work session", "task": "Write docs"}
+
+	assert.Equal(t, "Time for a break.", Expand("", vars))
+	assert.Equal(t, "Work session done (Write docs)", Expand("{session} done ({task})", vars))
+}
@@ -0,0 +1,103 @@
+//go:build darwin || windows || sound
+
+package sound
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ebitengine/oto/v3"
+)
+
+// The device is opened once in this format and every clip is converted to it
+const (
+	deviceRate     = 44100
+	deviceChannels = 2
+)
+
+// device plays clips through the system audio output
+type device struct {
+	once sync.Once
+	ctx  *oto.Context
+	err  error
+}
+
+// system is shared because only one audio context can be opened per process
+var system = &device{}
+
+// Device returns the system audio output, which is opened on first use
+func Device() Output {
+	return system
+}
+
+// Available reports whether this build can play sounds
+func Available() bool {
+	return true
+}
+
+// open creates the audio context and waits for it to be ready
+func (d *device) open() error {
+	d.once.Do(func() {
+		ctx, ready, err := oto.NewContext(&oto.NewContextOptions{
+			SampleRate:   deviceRate,
+			ChannelCount: deviceChannels,
+			Format:       oto.FormatFloat32LE,
+		})
+		if err != nil {
+			d.err = fmt.Errorf("sound: %w", err)
+			return
+		}
+		<-ready
+		d.ctx = ctx
+	})
+	return d.err
+}
+
+// Play implements Output
+func (d *device) Play(clip *Clip, volume float64, loop bool) (Voice, error) {
+	if err := d.open(); err != nil {
+		return nil, err
+	}
+
+	pcm := encodeFloat32(clip.Convert(deviceRate, deviceChannels, 1))
+	var r io.Reader = bytes.NewReader(pcm)
+	if loop {
+		r = &loopReader{data: pcm}
+	}
+	player := d.ctx.NewPlayer(r)
+	player.SetVolume(volume)
+	player.Play()
+
+	// Players are released once unreachable, so hold on to this one until
+	// it has finished or been stopped
+	go func() {
+		for player.IsPlaying() {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+	return deviceVoice{player: player}, nil
+}
+
+// deviceVoice stops a playing clip
+type deviceVoice struct {
+	player *oto.Player
+}
+
+// Stop implements Voice
+func (v deviceVoice) Stop() {
+	v.player.Pause()
+}
+
+// encodeFloat32 lays samples out as little-endian 32-bit floats
+func encodeFloat32(samples []float32) []byte {
+	out := make([]byte, 4*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint32(out[4*i:], math.Float32bits(s))
+	}
+	return out
+}
@@ -0,0 +1,23 @@
+//go:build !darwin && !windows && !sound
+
+package sound
+
+// Device returns the system audio output
+// Audio on Linux and BSD needs cgo and the ALSA headers, so it is only built
+// with -tags sound; without it sounds fail with ErrNoDevice
+func Device() Output {
+	return noDevice{}
+}
+
+// Available reports whether this build can play sounds
+func Available() bool {
+	return false
+}
+
+// noDevice is the output of builds without audio support
+type noDevice struct{}
+
+// Play implements Output
+func (noDevice) Play(*Clip, float64, bool) (Voice, error) {
+	return nil, ErrNoDevice
+}
@@ -0,0 +1,20 @@
+package sound
+
+import (
+	"bytes"
+
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// decodeOgg reads an Ogg Vorbis file
+func decodeOgg(data []byte) (*Clip, error) {
+	samples, format, err := oggvorbis.ReadAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return &Clip{
+		Rate:     format.SampleRate,
+		Channels: format.Channels,
+		Samples:  samples,
+	}, nil
+}
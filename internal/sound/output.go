@@ -0,0 +1,172 @@
+package sound
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrNoDevice is returned when the binary was built without audio output
+var ErrNoDevice = errors.New("sound: this build has no audio output, rebuild with -tags sound")
+
+// Output sends clips to an audio device
+type Output interface {
+	// Play starts clip at volume (0 to 1) without waiting for it to finish
+	// A looping clip repeats until the returned Voice is stopped
+	Play(clip *Clip, volume float64, loop bool) (Voice, error)
+}
+
+// Voice is a clip being played
+type Voice interface {
+	Stop()
+}
+
+// Player plays sounds by name, decoding each one once
+type Player struct {
+	out      Output
+	clips    map[string]*Clip
+	loop     Voice
+	loopName string
+}
+
+// NewPlayer creates a player sending sounds to out
+func NewPlayer(out Output) *Player {
+	return &Player{out: out, clips: map[string]*Clip{}}
+}
+
+// Play plays a bundled sound or file once at volume percent (0-100)
+func (p *Player) Play(name string, volume int) error {
+	clip, err := p.clip(name)
+	if err != nil {
+		return err
+	}
+	_, err = p.out.Play(clip, percent(volume), false)
+	return err
+}
+
+// Loop repeats a sound until StopLoop is called
+// Asking for the sound that is already looping does nothing
+func (p *Player) Loop(name string, volume int) error {
+	if p.loop != nil && p.loopName == name {
+		return nil
+	}
+	p.StopLoop()
+	clip, err := p.clip(name)
+	if err != nil {
+		return err
+	}
+	voice, err := p.out.Play(clip, percent(volume), true)
+	if err != nil {
+		return err
+	}
+	p.loop, p.loopName = voice, name
+	return nil
+}
+
+// StopLoop stops the looping sound, if any
+func (p *Player) StopLoop() {
+	if p.loop != nil {
+		p.loop.Stop()
+	}
+	p.loop, p.loopName = nil, ""
+}
+
+// Looping returns whether a sound is looping
+func (p *Player) Looping() bool {
+	return p.loop != nil
+}
+
+// Forget drops decoded clips so edited files are read again
+func (p *Player) Forget() {
+	p.clips = map[string]*Clip{}
+}
+
+// clip returns the decoded sound, loading it on first use
+func (p *Player) clip(name string) (*Clip, error) {
+	if clip, ok := p.clips[name]; ok {
+		return clip, nil
+	}
+	clip, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+	p.clips[name] = clip
+	return clip, nil
+}
+
+// percent converts a 0-100 volume to the 0-1 range, clamping it
+func percent(volume int) float64 {
+	return float64(min(max(volume, 0), 100)) / 100
+}
+
+// loopReader repeats data forever
+type loopReader struct {
+	data []byte
+	pos  int
+}
+
+// Read implements io.Reader
+func (l *loopReader) Read(b []byte) (int, error) {
+	if len(l.data) == 0 {
+		return 0, io.EOF
+	}
+	n := 0
+	for n < len(b) {
+		c := copy(b[n:], l.data[l.pos:])
+		n += c
+		l.pos = (l.pos + c) % len(l.data)
+	}
+	return n, nil
+}
+
+// Recorder is an Output that logs what it was asked to play instead of
+// making a sound, for tests and machines without audio
+type Recorder struct {
+	mu     sync.Mutex
+	played []Played
+}
+
+// Played is one entry in a Recorder's log
+type Played struct {
+	Name    string
+	Volume  float64
+	Loop    bool
+	Stopped bool
+}
+
+// Play implements Output
+func (r *Recorder) Play(clip *Clip, volume float64, loop bool) (Voice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.played = append(r.played, Played{Name: clip.Name, Volume: volume, Loop: loop})
+	return recordedVoice{r: r, i: len(r.played) - 1}, nil
+}
+
+// Played returns everything played so far, oldest first
+func (r *Recorder) Played() []Played {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Played(nil), r.played...)
+}
+
+// Names returns the names of the sounds played so far, oldest first
+func (r *Recorder) Names() []string {
+	var names []string
+	for _, p := range r.Played() {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// recordedVoice marks its Recorder entry stopped
+type recordedVoice struct {
+	r *Recorder
+	i int
+}
+
+// Stop implements Voice
+func (v recordedVoice) Stop() {
+	v.r.mu.Lock()
+	defer v.r.mu.Unlock()
+	v.r.played[v.i].Stopped = true
+}
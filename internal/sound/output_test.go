@@ -0,0 +1,78 @@
+package sound
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayer_Play(t *testing.T) {
+	out := &Recorder{}
+	p := NewPlayer(out)
+
+	require.NoError(t, p.Play("chime", 70))
+	require.NoError(t, p.Play("bell", 150))
+
+	assert.Equal(t, []Played{
+		{Name: "chime", Volume: 0.7},
+		{Name: "bell", Volume: 1},
+	}, out.Played())
+}
+
+func TestPlayer_PlayMissing(t *testing.T) {
+	out := &Recorder{}
+	p := NewPlayer(out)
+
+	assert.Error(t, p.Play("/no/such/sound.wav", 50))
+	assert.Empty(t, out.Played())
+}
+
+func TestPlayer_Loop(t *testing.T) {
+	out := &Recorder{}
+	p := NewPlayer(out)
+
+	require.NoError(t, p.Loop("tick", 30))
+	require.NoError(t, p.Loop("tick", 30))
+	assert.True(t, p.Looping())
+	assert.Equal(t, []Played{{Name: "tick", Volume: 0.3, Loop: true}}, out.Played(), "already looping")
+
+	require.NoError(t, p.Loop("ding", 30))
+	p.StopLoop()
+	p.StopLoop()
+
+	assert.False(t, p.Looping())
+	assert.Equal(t, []Played{
+		{Name: "tick", Volume: 0.3, Loop: true, Stopped: true},
+		{Name: "ding", Volume: 0.3, Loop: true, Stopped: true},
+	}, out.Played())
+}
+
+// failingOutput stands in for a machine without audio
+type failingOutput struct{}
+
+func (failingOutput) Play(*Clip, float64, bool) (Voice, error) {
+	return nil, errors.New("no device")
+}
+
+func TestPlayer_OutputError(t *testing.T) {
+	p := NewPlayer(failingOutput{})
+
+	assert.EqualError(t, p.Play("chime", 50), "no device")
+	assert.EqualError(t, p.Loop("tick", 50), "no device")
+	assert.False(t, p.Looping())
+}
+
+func TestLoopReader(t *testing.T) {
+	r := &loopReader{data: []byte("abc")}
+	buf := make([]byte, 7)
+
+	n, err := r.Read(buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+	assert.Equal(t, "abcabca", string(buf))
+	n, _ = r.Read(buf[:2])
+	assert.Equal(t, "bc", string(buf[:n]))
+}
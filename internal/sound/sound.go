@@ -0,0 +1,134 @@
+package sound
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed sounds/*.wav
+var bundled embed.FS
+
+// Clip is decoded audio ready to be played
+type Clip struct {
+	Name     string
+	Rate     int       // Samples per second
+	Channels int       // 1 for mono, 2 for stereo
+	Samples  []float32 // Interleaved by channel, from -1 to 1
+}
+
+// Duration returns how long the clip plays for
+func (c *Clip) Duration() time.Duration {
+	if c.Rate == 0 || c.Channels == 0 {
+		return 0
+	}
+	frames := len(c.Samples) / c.Channels
+	return time.Duration(frames) * time.Second / time.Duration(c.Rate)
+}
+
+// Bundled lists the names of the sounds built into the binary
+func Bundled() []string {
+	entries, _ := bundled.ReadDir("sounds")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".wav"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load decodes a bundled sound by name, or a WAV or OGG file by path
+// A leading ~/ in a path is expanded to the home directory
+func Load(name string) (*Clip, error) {
+	if data, err := bundled.ReadFile("sounds/" + name + ".wav"); err == nil && !strings.ContainsAny(name, `/\.`) {
+		return Decode(name, data)
+	}
+
+	path := name
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, rest)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !strings.ContainsAny(name, `/\.`) {
+			return nil, fmt.Errorf("sound %q: not a bundled sound (%s) or a file", name, strings.Join(Bundled(), ", "))
+		}
+		return nil, err
+	}
+	return Decode(name, data)
+}
+
+// Decode reads a WAV or OGG Vorbis file, telling them apart by content
+func Decode(name string, data []byte) (*Clip, error) {
+	var clip *Clip
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte("RIFF")):
+		clip, err = decodeWAV(data)
+	case bytes.HasPrefix(data, []byte("OggS")):
+		clip, err = decodeOgg(data)
+	default:
+		return nil, fmt.Errorf("sound %q: not a WAV or OGG file", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sound %q: %w", name, err)
+	}
+	clip.Name = name
+	return clip, nil
+}
+
+// Convert returns the clip's samples at another rate and channel count,
+// scaled by volume
+// Resampling is linear, which is plenty for short notification sounds
+func (c *Clip) Convert(rate, channels int, volume float64) []float32 {
+	frames := len(c.Samples) / c.Channels
+	if frames == 0 || rate <= 0 || channels <= 0 {
+		return nil
+	}
+	outFrames := int(int64(frames) * int64(rate) / int64(c.Rate))
+	out := make([]float32, outFrames*channels)
+	step := float64(c.Rate) / float64(rate)
+	for i := 0; i < outFrames; i++ {
+		pos := float64(i) * step
+		j := int(pos)
+		frac := float32(pos - float64(j))
+		for ch := 0; ch < channels; ch++ {
+			a := c.sample(j, ch, channels)
+			b := a
+			if j+1 < frames {
+				b = c.sample(j+1, ch, channels)
+			}
+			out[i*channels+ch] = (a + (b-a)*frac) * float32(volume)
+		}
+	}
+	return out
+}
+
+// sample returns one output channel of a frame, mixing stereo down to mono
+// and repeating mono across channels
+func (c *Clip) sample(frame, channel, channels int) float32 {
+	base := frame * c.Channels
+	if channels == c.Channels {
+		return c.Samples[base+channel]
+	}
+	if c.Channels == 1 {
+		return c.Samples[base]
+	}
+	if channels > 1 && channel < c.Channels {
+		return c.Samples[base+channel]
+	}
+	var sum float32
+	for ch := 0; ch < c.Channels; ch++ {
+		sum += c.Samples[base+ch]
+	}
+	return sum / float32(c.Channels)
+}
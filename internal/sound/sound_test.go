@@ -0,0 +1,76 @@
+package sound
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundled(t *testing.T) {
+	assert.Equal(t, []string{"bell", "chime", "ding", "tick"}, Bundled())
+
+	for _, name := range Bundled() {
+		clip, err := Load(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, name, clip.Name)
+		assert.Greater(t, clip.Duration(), 100*time.Millisecond, name)
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beep.wav")
+	require.NoError(t, os.WriteFile(path, wavFile(1, 8000, 16, 1, []byte{0, 0, 0xff, 0x7f}), 0644))
+
+	clip, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, path, clip.Name)
+	assert.Equal(t, 8000, clip.Rate)
+	assert.Len(t, clip.Samples, 2)
+}
+
+func TestLoad_Unknown(t *testing.T) {
+	_, err := Load("gong")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a bundled sound (bell, chime, ding, tick)")
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.wav"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDecode_Ogg(t *testing.T) {
+	data, err := os.ReadFile("testdata/short.ogg")
+	require.NoError(t, err)
+
+	clip, err := Decode("short.ogg", data)
+
+	require.NoError(t, err)
+	assert.Equal(t, 44100, clip.Rate)
+	assert.Equal(t, 1, clip.Channels)
+	assert.Equal(t, time.Second, clip.Duration())
+}
+
+func TestDecode_Unknown(t *testing.T) {
+	_, err := Decode("notes.txt", []byte("hello"))
+	assert.EqualError(t, err, `sound "notes.txt": not a WAV or OGG file`)
+
+	_, err = Decode("broken.ogg", []byte("OggS broken"))
+	assert.Error(t, err)
+}
+
+func TestConvert(t *testing.T) {
+	mono := &Clip{Rate: 2, Channels: 1, Samples: []float32{0, 1}}
+
+	// Upsampling interpolates, mono is copied to both channels
+	assert.Equal(t, []float32{0, 0, 0.5, 0.5, 1, 1, 1, 1}, mono.Convert(4, 2, 1))
+	// Volume scales every sample
+	assert.Equal(t, []float32{0, 0.5}, mono.Convert(2, 1, 0.5))
+
+	stereo := &Clip{Rate: 1, Channels: 2, Samples: []float32{1, 0, 0.5, -0.5}}
+	assert.Equal(t, []float32{0.5, 0}, stereo.Convert(1, 1, 1), "stereo is mixed down to mono")
+	assert.Nil(t, (&Clip{Rate: 1, Channels: 1}).Convert(44100, 2, 1))
+}
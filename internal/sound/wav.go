@@ -0,0 +1,96 @@
+package sound
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// WAV sample formats, see the WAVE format tag registry
+const (
+	wavPCM        = 1
+	wavFloat      = 3
+	wavExtensible = 0xFFFE
+)
+
+// decodeWAV reads integer PCM (8, 16, 24 or 32 bit) and 32-bit float WAV files
+func decodeWAV(data []byte) (*Clip, error) {
+	if len(data) < 12 || string(data[8:12]) != "WAVE" {
+		return nil, errors.New("not a WAVE file")
+	}
+
+	var format, channels, bits int
+	var rate int
+	var samples []byte
+	haveFormat := false
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := data[pos+8:]
+		if size > len(body) {
+			// Tolerate a truncated final chunk, as many encoders write one
+			size = len(body)
+		}
+		body = body[:size]
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, errors.New("short fmt chunk")
+			}
+			format = int(binary.LittleEndian.Uint16(body[0:2]))
+			channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			rate = int(binary.LittleEndian.Uint32(body[4:8]))
+			bits = int(binary.LittleEndian.Uint16(body[14:16]))
+			if format == wavExtensible && size >= 26 {
+				format = int(binary.LittleEndian.Uint16(body[24:26]))
+			}
+			haveFormat = true
+		case "data":
+			samples = body
+		}
+		// Chunks are padded to an even length
+		pos += 8 + size + size%2
+	}
+
+	if !haveFormat {
+		return nil, errors.New("missing fmt chunk")
+	}
+	if samples == nil {
+		return nil, errors.New("missing data chunk")
+	}
+	if channels < 1 || rate < 1 {
+		return nil, fmt.Errorf("invalid format: %d channels at %d Hz", channels, rate)
+	}
+
+	clip := &Clip{Rate: rate, Channels: channels}
+	width := bits / 8
+	switch {
+	case format == wavPCM && (bits == 8 || bits == 16 || bits == 24 || bits == 32):
+	case format == wavFloat && bits == 32:
+	default:
+		return nil, fmt.Errorf("unsupported encoding: format %d, %d bits", format, bits)
+	}
+
+	n := len(samples) / width
+	n -= n % channels
+	clip.Samples = make([]float32, n)
+	for i := range clip.Samples {
+		b := samples[i*width : (i+1)*width]
+		switch {
+		case format == wavFloat:
+			clip.Samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case bits == 8:
+			clip.Samples[i] = (float32(b[0]) - 128) / 128
+		case bits == 16:
+			clip.Samples[i] = float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+		case bits == 24:
+			v := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+			clip.Samples[i] = float32(v) / (1 << 23)
+		case bits == 32:
+			clip.Samples[i] = float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+		}
+	}
+	return clip, nil
+}
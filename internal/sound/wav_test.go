@@ -0,0 +1,101 @@
+package sound
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wavFile builds a WAV file holding data in the given format
+func wavFile(format, rate, bits, channels int, data []byte) []byte {
+	le := binary.LittleEndian
+	fmtChunk := make([]byte, 16)
+	le.PutUint16(fmtChunk[0:], uint16(format))
+	le.PutUint16(fmtChunk[2:], uint16(channels))
+	le.PutUint32(fmtChunk[4:], uint32(rate))
+	le.PutUint32(fmtChunk[8:], uint32(rate*channels*bits/8))
+	le.PutUint16(fmtChunk[12:], uint16(channels*bits/8))
+	le.PutUint16(fmtChunk[14:], uint16(bits))
+
+	out := []byte("RIFF\x00\x00\x00\x00WAVE")
+	out = appendChunk(out, "fmt ", fmtChunk)
+	out = appendChunk(out, "data", data)
+	le.PutUint32(out[4:], uint32(len(out)-8))
+	return out
+}
+
+// appendChunk adds a RIFF chunk, padding it to an even length
+func appendChunk(out []byte, id string, body []byte) []byte {
+	out = append(out, id...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(body)))
+	out = append(out, body...)
+	if len(body)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+func TestDecodeWAV_Encodings(t *testing.T) {
+	float := func(v float32) []byte {
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(v))
+	}
+	tests := []struct {
+		name   string
+		format int
+		bits   int
+		data   []byte
+		want   []float32
+	}{
+		{"8-bit", wavPCM, 8, []byte{128, 0, 192}, []float32{0, -1, 0.5}},
+		{"16-bit", wavPCM, 16, []byte{0x00, 0x80, 0x00, 0x40}, []float32{-1, 0.5}},
+		{"24-bit", wavPCM, 24, []byte{0x00, 0x00, 0xc0, 0x00, 0x00, 0x40}, []float32{-0.5, 0.5}},
+		{"32-bit", wavPCM, 32, []byte{0, 0, 0, 0x80, 0, 0, 0, 0x40}, []float32{-1, 0.5}},
+		{"float", wavFloat, 32, append(float(0.25), float(-0.75)...), []float32{0.25, -0.75}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip, err := decodeWAV(wavFile(tt.format, 8000, tt.bits, 1, tt.data))
+
+			require.NoError(t, err)
+			assert.Equal(t, 8000, clip.Rate)
+			assert.Equal(t, 1, clip.Channels)
+			assert.Equal(t, tt.want, clip.Samples)
+		})
+	}
+}
+
+func TestDecodeWAV_SkipsOtherChunks(t *testing.T) {
+	data := wavFile(wavPCM, 8000, 16, 2, []byte{0, 0x40, 0, 0xc0, 0, 0})
+	// Insert an odd-sized LIST chunk between fmt and data
+	list := appendChunk(nil, "LIST", []byte("INFO!"))
+	data = append(data[:36], append(list, data[36:]...)...)
+
+	clip, err := decodeWAV(data)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, clip.Channels)
+	assert.Equal(t, []float32{0.5, -0.5}, clip.Samples, "a partial frame is dropped")
+}
+
+func TestDecodeWAV_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"not wave", []byte("RIFF\x04\x00\x00\x00AVI "), "not a WAVE file"},
+		{"no fmt", appendChunk([]byte("RIFF\x00\x00\x00\x00WAVE"), "data", []byte{0, 0}), "missing fmt chunk"},
+		{"no data", wavFile(wavPCM, 8000, 16, 1, nil)[:36], "missing data chunk"},
+		{"adpcm", wavFile(2, 8000, 4, 1, []byte{0}), "unsupported encoding: format 2, 4 bits"},
+		{"no channels", wavFile(wavPCM, 8000, 16, 0, []byte{0, 0}), "invalid format: 0 channels at 8000 Hz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeWAV(tt.data)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mattermost sets the status through the Mattermost REST API (v4) with a
+// personal access token
+type Mattermost struct {
+	URL    string // Server, e.g. https://chat.example.com
+	Token  string
+	Client *http.Client
+
+	mu     sync.Mutex
+	userID string // Looked up on first use
+}
+
+// NewMattermost creates a client for the server at url
+func NewMattermost(url, token string) *Mattermost {
+	return &Mattermost{URL: url, Token: token, Client: http.DefaultClient}
+}
+
+// mattermostCustomStatus is the body of PUT /users/me/status/custom
+type mattermostCustomStatus struct {
+	Emoji     string `json:"emoji"` // Name without colons
+	Text      string `json:"text"`
+	Duration  string `json:"duration,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// mattermostStatus is the body of PUT /users/me/status
+type mattermostStatus struct {
+	UserID     string `json:"user_id"`
+	Status     string `json:"status"`
+	DNDEndTime int64  `json:"dnd_end_time,omitempty"`
+}
+
+// Name implements Service
+func (m *Mattermost) Name() string {
+	return "mattermost"
+}
+
+// SetStatus implements Service
+func (m *Mattermost) SetStatus(ctx context.Context, st Status) error {
+	custom := mattermostCustomStatus{Emoji: strings.Trim(st.Emoji, ":"), Text: st.Text}
+	if !st.Expires.IsZero() {
+		custom.Duration = "date_and_time"
+		custom.ExpiresAt = st.Expires.UTC().Format(time.RFC3339)
+	}
+	return m.do(ctx, http.MethodPut, "/users/me/status/custom", custom, nil)
+}
+
+// ClearStatus implements Service
+func (m *Mattermost) ClearStatus(ctx context.Context) error {
+	return m.do(ctx, http.MethodDelete, "/users/me/status/custom", nil, nil)
+}
+
+// SetDND implements Service
+func (m *Mattermost) SetDND(ctx context.Context, until time.Time) error {
+	return m.setStatus(ctx, mattermostStatus{Status: "dnd", DNDEndTime: until.Unix()})
+}
+
+// EndDND implements Service
+func (m *Mattermost) EndDND(ctx context.Context) error {
+	return m.setStatus(ctx, mattermostStatus{Status: "online"})
+}
+
+// setStatus changes the availability, which needs the user's ID
+func (m *Mattermost) setStatus(ctx context.Context, st mattermostStatus) error {
+	id, err := m.user(ctx)
+	if err != nil {
+		return err
+	}
+	st.UserID = id
+	return m.do(ctx, http.MethodPut, "/users/me/status", st, nil)
+}
+
+// user returns the ID of the token's user
+func (m *Mattermost) user(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.userID != "" {
+		return m.userID, nil
+	}
+	var me struct {
+		ID string `json:"id"`
+	}
+	if err := m.do(ctx, http.MethodGet, "/users/me", nil, &me); err != nil {
+		return "", err
+	}
+	m.userID = me.ID
+	return m.userID, nil
+}
+
+// do calls the API, sending body and decoding the response into out when
+// they aren't nil
+func (m *Mattermost) do(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(m.URL, "/")+"/api/v4"+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+m.Token)
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mattermost: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("mattermost %s %s: %s", method, path, apiErr.Message)
+		}
+		return fmt.Errorf("mattermost %s %s: %s", method, path, resp.Status)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("mattermost %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package status
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mattermostCall is a request received by the mock server
+type mattermostCall struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// mockMattermost serves the parts of the REST API status sync uses
+func mockMattermost(t *testing.T) (*Mattermost, *[]mattermostCall) {
+	t.Helper()
+	var calls []mattermostCall
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer mm-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Invalid or expired session, please login again."}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, mattermostCall{Method: r.Method, Path: r.URL.Path, Body: string(body)})
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v4/users/me":
+			_, _ = w.Write([]byte(`{"id":"u123","username":"me"}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"OK"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return NewMattermost(srv.URL+"/", "mm-token"), &calls
+}
+
+func TestMattermost_CustomStatus(t *testing.T) {
+	m, calls := mockMattermost(t)
+	expires := time.Date(2024, 6, 3, 10, 25, 0, 0, time.UTC)
+
+	require.NoError(t, m.SetStatus(context.Background(), Status{Text: "Focusing", Emoji: ":tomato:", Expires: expires}))
+	require.NoError(t, m.ClearStatus(context.Background()))
+
+	require.Len(t, *calls, 2)
+	assert.Equal(t, http.MethodPut, (*calls)[0].Method)
+	assert.Equal(t, "/api/v4/users/me/status/custom", (*calls)[0].Path)
+	assert.JSONEq(t, `{"emoji":"tomato","text":"Focusing","duration":"date_and_time","expires_at":"2024-06-03T10:25:00Z"}`, (*calls)[0].Body)
+	assert.Equal(t, mattermostCall{Method: http.MethodDelete, Path: "/api/v4/users/me/status/custom"}, (*calls)[1])
+}
+
+func TestMattermost_DND(t *testing.T) {
+	m, calls := mockMattermost(t)
+	until := time.Unix(1717410300, 0)
+
+	require.NoError(t, m.SetDND(context.Background(), until))
+	require.NoError(t, m.EndDND(context.Background()))
+
+	require.Len(t, *calls, 3, "the user is looked up once")
+	assert.Equal(t, "/api/v4/users/me", (*calls)[0].Path)
+	assert.JSONEq(t, `{"user_id":"u123","status":"dnd","dnd_end_time":1717410300}`, (*calls)[1].Body)
+	assert.JSONEq(t, `{"user_id":"u123","status":"online"}`, (*calls)[2].Body)
+}
+
+func TestMattermost_Error(t *testing.T) {
+	m, _ := mockMattermost(t)
+	m.Token = "wrong"
+
+	err := m.ClearStatus(context.Background())
+
+	assert.EqualError(t, err, "mattermost DELETE /users/me/status/custom: Invalid or expired session, please login again.")
+}
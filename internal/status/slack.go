@@ -0,0 +1,119 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlackAPI is the base URL of the Slack Web API
+const SlackAPI = "https://slack.com/api"
+
+// Slack sets the status through the Slack Web API with a user token
+// (xoxp-) that has the users.profile:write and dnd:write scopes
+type Slack struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+// NewSlack creates a client for the Web API at url
+func NewSlack(url, token string) *Slack {
+	return &Slack{URL: url, Token: token, Client: http.DefaultClient}
+}
+
+// slackResponse is the envelope of every Web API response
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// Name implements Service
+func (s *Slack) Name() string {
+	return "slack"
+}
+
+// SetStatus implements Service
+func (s *Slack) SetStatus(ctx context.Context, st Status) error {
+	var expiration int64
+	if !st.Expires.IsZero() {
+		expiration = st.Expires.Unix()
+	}
+	return s.callJSON(ctx, "users.profile.set", map[string]any{
+		"profile": map[string]any{
+			"status_text":       st.Text,
+			"status_emoji":      st.Emoji,
+			"status_expiration": expiration,
+		},
+	})
+}
+
+// ClearStatus implements Service
+func (s *Slack) ClearStatus(ctx context.Context) error {
+	return s.SetStatus(ctx, Status{})
+}
+
+// SetDND implements Service
+// Snoozes are set in whole minutes, rounded up
+func (s *Slack) SetDND(ctx context.Context, until time.Time) error {
+	minutes := max(1, int((time.Until(until)+time.Minute-1)/time.Minute))
+	return s.callForm(ctx, "dnd.setSnooze", url.Values{"num_minutes": {strconv.Itoa(minutes)}})
+}
+
+// EndDND implements Service
+func (s *Slack) EndDND(ctx context.Context) error {
+	err := s.callForm(ctx, "dnd.endSnooze", nil)
+	if err != nil && strings.HasSuffix(err.Error(), "snooze_not_active") {
+		return nil
+	}
+	return err
+}
+
+// callJSON calls a Web API method with a JSON body
+func (s *Slack) callJSON(ctx context.Context, method string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return s.call(ctx, method, "application/json; charset=utf-8", string(data))
+}
+
+// callForm calls a Web API method with form arguments, which the dnd
+// methods take
+func (s *Slack) callForm(ctx context.Context, method string, args url.Values) error {
+	return s.call(ctx, method, "application/x-www-form-urlencoded", args.Encode())
+}
+
+// call posts to a Web API method and checks the ok field
+func (s *Slack) call(ctx context.Context, method, contentType, body string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimRight(s.URL, "/")+"/"+method, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack %s: %s", method, resp.Status)
+	}
+	var result slackResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack %s: %s", method, result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slackCall is a request received by the mock Web API
+type slackCall struct {
+	Method string
+	Auth   string
+	Body   string
+}
+
+// mockSlack answers Web API calls with the given error, ok if empty
+func mockSlack(t *testing.T, errorCode string) (*Slack, *[]slackCall) {
+	t.Helper()
+	var calls []slackCall
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, slackCall{Method: r.URL.Path, Auth: r.Header.Get("Authorization"), Body: string(body)})
+		w.Header().Set("Content-Type", "application/json")
+		if errorCode != "" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"` + errorCode + `"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	return NewSlack(srv.URL, "xoxp-test"), &calls
+}
+
+func TestSlack_SetStatus(t *testing.T) {
+	s, calls := mockSlack(t, "")
+	expires := time.Unix(1717410300, 0)
+
+	require.NoError(t, s.SetStatus(context.Background(), Status{Text: "Focusing", Emoji: ":tomato:", Expires: expires}))
+
+	require.Len(t, *calls, 1)
+	call := (*calls)[0]
+	assert.Equal(t, "/users.profile.set", call.Method)
+	assert.Equal(t, "Bearer xoxp-test", call.Auth)
+	assert.JSONEq(t, `{"profile":{"status_text":"Focusing","status_emoji":":tomato:","status_expiration":1717410300}}`, call.Body)
+}
+
+func TestSlack_ClearStatus(t *testing.T) {
+	s, calls := mockSlack(t, "")
+
+	require.NoError(t, s.ClearStatus(context.Background()))
+
+	var body map[string]map[string]any
+	require.NoError(t, json.Unmarshal([]byte((*calls)[0].Body), &body))
+	assert.Equal(t, map[string]any{"status_text": "", "status_emoji": "", "status_expiration": float64(0)}, body["profile"])
+}
+
+func TestSlack_DND(t *testing.T) {
+	s, calls := mockSlack(t, "")
+
+	require.NoError(t, s.SetDND(context.Background(), time.Now().Add(24*time.Minute+10*time.Second)))
+	require.NoError(t, s.EndDND(context.Background()))
+
+	require.Len(t, *calls, 2)
+	assert.Equal(t, "/dnd.setSnooze", (*calls)[0].Method)
+	form, err := url.ParseQuery((*calls)[0].Body)
+	require.NoError(t, err)
+	assert.Equal(t, "25", form.Get("num_minutes"))
+	assert.Equal(t, "/dnd.endSnooze", (*calls)[1].Method)
+}
+
+func TestSlack_Errors(t *testing.T) {
+	s, _ := mockSlack(t, "invalid_auth")
+	assert.EqualError(t, s.SetStatus(context.Background(), Status{}), "slack users.profile.set: invalid_auth")
+
+	s, _ = mockSlack(t, "snooze_not_active")
+	assert.NoError(t, s.EndDND(context.Background()), "ending a snooze that already ended is fine")
+}
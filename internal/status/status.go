@@ -0,0 +1,140 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+)
+
+// Status is a custom chat status that clears itself at Expires
+type Status struct {
+	Text    string
+	Emoji   string // In :name: form
+	Expires time.Time
+}
+
+// Service sets the user's status on a chat server
+type Service interface {
+	Name() string
+	SetStatus(ctx context.Context, s Status) error
+	ClearStatus(ctx context.Context) error
+	SetDND(ctx context.Context, until time.Time) error
+	EndDND(ctx context.Context) error
+}
+
+// FromConfig creates the configured service, or nil if status sync is off
+func FromConfig(cfg config.StatusConfig, creds *config.Credentials) (Service, error) {
+	switch cfg.Service {
+	case "":
+		return nil, nil
+	case "slack":
+		if creds.Slack.Token == "" {
+			return nil, fmt.Errorf("status: no slack token in %s", config.CredentialsFile)
+		}
+		return NewSlack(SlackAPI, creds.Slack.Token), nil
+	case "mattermost":
+		if creds.Mattermost.Token == "" {
+			return nil, fmt.Errorf("status: no mattermost token in %s", config.CredentialsFile)
+		}
+		return NewMattermost(cfg.URL, creds.Mattermost.Token), nil
+	}
+	return nil, fmt.Errorf("status: unknown service %q", cfg.Service)
+}
+
+// state is what the chat server should show
+type state struct {
+	focus  bool
+	status Status
+	dnd    bool
+}
+
+// Syncer keeps the chat status in line with the timer
+// Focus and Clear record what should be shown and return at once; Apply
+// does the network calls, so callers can run it in the background, and
+// always pushes the latest wish however the calls interleave
+type Syncer struct {
+	svc Service
+
+	mu   sync.Mutex // Guards want
+	want state
+
+	applying sync.Mutex // Held while talking to the server, guards have
+	have     state
+}
+
+// NewSyncer creates a syncer for svc
+func NewSyncer(svc Service) *Syncer {
+	return &Syncer{svc: svc}
+}
+
+// Focus asks for the focus status until the session ends
+func (s *Syncer) Focus(text, emoji string, until time.Time, dnd bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.want = state{focus: true, status: Status{Text: text, Emoji: emoji, Expires: until}, dnd: dnd}
+}
+
+// Clear asks for the status and do not disturb to be removed
+func (s *Syncer) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.want = state{}
+}
+
+// Focused returns whether the focus status is wanted
+func (s *Syncer) Focused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.want.focus
+}
+
+// Apply brings the server in line with the latest Focus or Clear
+func (s *Syncer) Apply(ctx context.Context) error {
+	s.applying.Lock()
+	defer s.applying.Unlock()
+
+	s.mu.Lock()
+	want := s.want
+	s.mu.Unlock()
+	if want == s.have {
+		return nil
+	}
+
+	var errs []error
+	if want.focus {
+		if err := s.svc.SetStatus(ctx, want.status); err != nil {
+			errs = append(errs, err)
+		}
+	} else if s.have.focus {
+		if err := s.svc.ClearStatus(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if want.dnd {
+		if err := s.svc.SetDND(ctx, want.status.Expires); err != nil {
+			errs = append(errs, err)
+		}
+	} else if s.have.dnd {
+		if err := s.svc.EndDND(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		// Left unrecorded so the next Apply tries again
+		return errors.Join(errs...)
+	}
+	s.have = want
+	return nil
+}
+
+// StatusText fills in {end} and {task} in a status template
+func StatusText(format string, end time.Time, task string) string {
+	text := strings.NewReplacer("{end}", end.Format("15:04"), "{task}", task).Replace(format)
+	// Drop what an empty task leaves behind, e.g. "Focusing on  until"
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,114 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService records the calls made to it
+type fakeService struct {
+	calls []string
+	err   error
+}
+
+func (f *fakeService) Name() string { return "fake" }
+
+func (f *fakeService) SetStatus(_ context.Context, s Status) error {
+	f.calls = append(f.calls, fmt.Sprintf("status %s %s %s", s.Emoji, s.Text, s.Expires.Format("15:04")))
+	return f.err
+}
+
+func (f *fakeService) ClearStatus(context.Context) error {
+	f.calls = append(f.calls, "clear")
+	return f.err
+}
+
+func (f *fakeService) SetDND(_ context.Context, until time.Time) error {
+	f.calls = append(f.calls, "dnd "+until.Format("15:04"))
+	return f.err
+}
+
+func (f *fakeService) EndDND(context.Context) error {
+	f.calls = append(f.calls, "end dnd")
+	return f.err
+}
+
+func TestSyncer_FocusThenClear(t *testing.T) {
+	svc := &fakeService{}
+	s := NewSyncer(svc)
+	until := time.Date(2024, 6, 3, 10, 25, 0, 0, time.Local)
+
+	s.Focus("Focusing", ":tomato:", until, true)
+	assert.True(t, s.Focused())
+	require.NoError(t, s.Apply(context.Background()))
+	require.NoError(t, s.Apply(context.Background()), "nothing changed")
+	s.Clear()
+	require.NoError(t, s.Apply(context.Background()))
+
+	assert.Equal(t, []string{"status :tomato: Focusing 10:25", "dnd 10:25", "clear", "end dnd"}, svc.calls)
+}
+
+func TestSyncer_WithoutDND(t *testing.T) {
+	svc := &fakeService{}
+	s := NewSyncer(svc)
+
+	s.Focus("Focusing", ":tomato:", time.Date(2024, 6, 3, 10, 25, 0, 0, time.Local), false)
+	require.NoError(t, s.Apply(context.Background()))
+	s.Clear()
+	require.NoError(t, s.Apply(context.Background()))
+
+	assert.Equal(t, []string{"status :tomato: Focusing 10:25", "clear"}, svc.calls)
+}
+
+func TestSyncer_ClearBeforeApplyDoesNothing(t *testing.T) {
+	svc := &fakeService{}
+	s := NewSyncer(svc)
+
+	s.Focus("Focusing", ":tomato:", time.Now(), true)
+	s.Clear()
+	require.NoError(t, s.Apply(context.Background()))
+
+	assert.Empty(t, svc.calls, "only the latest wish is pushed")
+}
+
+func TestSyncer_RetriesAfterFailure(t *testing.T) {
+	svc := &fakeService{err: errors.New("offline")}
+	s := NewSyncer(svc)
+
+	s.Focus("Focusing", ":tomato:", time.Date(2024, 6, 3, 10, 25, 0, 0, time.Local), false)
+	require.Error(t, s.Apply(context.Background()))
+	svc.err = nil
+	require.NoError(t, s.Apply(context.Background()))
+
+	assert.Len(t, svc.calls, 2)
+}
+
+func TestFromConfig(t *testing.T) {
+	creds := &config.Credentials{Slack: config.TokenCredentials{Token: "xoxp-1"}}
+
+	svc, err := FromConfig(config.StatusConfig{}, creds)
+	require.NoError(t, err)
+	assert.Nil(t, svc)
+
+	svc, err = FromConfig(config.StatusConfig{Service: "slack"}, creds)
+	require.NoError(t, err)
+	assert.Equal(t, "slack", svc.Name())
+
+	_, err = FromConfig(config.StatusConfig{Service: "mattermost", URL: "https://chat.test"}, creds)
+	assert.EqualError(t, err, "status: no mattermost token in credentials.toml")
+}
+
+func TestStatusText(t *testing.T) {
+	end := time.Date(2024, 6, 3, 10, 25, 0, 0, time.Local)
+
+	assert.Equal(t, "Focusing until 10:25", StatusText("Focusing until {end}", end, ""))
+	assert.Equal(t, "On Write docs until 10:25", StatusText("On {task} until {end}", end, "Write docs"))
+	assert.Equal(t, "On until 10:25", StatusText("On {task} until {end}", end, ""))
+}
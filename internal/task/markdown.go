@@ -0,0 +1,85 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// markdownItem matches a checklist item such as "- [ ] Write docs"
+var markdownItem = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\]\s+)(.*)$`)
+
+// Markdown reads and completes checklist items in a Markdown file
+type Markdown struct {
+	Path string
+}
+
+// NewMarkdown creates a source for the Markdown checklist at path
+func NewMarkdown(path string) *Markdown {
+	return &Markdown{Path: path}
+}
+
+// Name implements Source
+func (s *Markdown) Name() string {
+	return s.Path
+}
+
+// List implements Source
+// Items inside fenced code blocks are ignored
+func (s *Markdown) List() ([]Task, error) {
+	f, err := readLineFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []Task
+	inFence := false
+	for i := range f.lines {
+		raw := f.text(i)
+		if isFence(raw) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := markdownItem.FindStringSubmatch(raw)
+		if m == nil || m[2] != " " {
+			continue
+		}
+		tasks = append(tasks, Task{
+			ID:     fmt.Sprintf("%s:%d", s.Path, i+1),
+			Title:  strings.TrimSpace(m[4]),
+			Source: s.Name(),
+			Line:   i + 1,
+			raw:    raw,
+		})
+	}
+	return tasks, nil
+}
+
+// Complete implements Source
+// Only the box is changed from "[ ]" to "[x]"
+func (s *Markdown) Complete(t Task) error {
+	f, err := readLineFile(s.Path)
+	if err != nil {
+		return err
+	}
+	i, err := f.locate(t)
+	if err != nil {
+		return err
+	}
+
+	m := markdownItem.FindStringSubmatch(f.text(i))
+	if m == nil {
+		return fmt.Errorf("%s:%d: not a checklist item: %w", s.Path, i+1, ErrModified)
+	}
+	f.replace(i, m[1]+"x"+m[3]+m[4])
+	return f.write()
+}
+
+// isFence reports whether a line opens or closes a fenced code block
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
@@ -0,0 +1,73 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const markdownFixture = "# Sprint\r\n" +
+	"\r\n" +
+	"Some notes about the sprint.\r\n" +
+	"- [ ] Write **docs**\r\n" +
+	"  * [x] Set up CI\r\n" +
+	"  * [ ]   Fix flaky test  \r\n" +
+	"```md\r\n" +
+	"- [ ] example in a code block\r\n" +
+	"```\r\n" +
+	"+ [ ] Ship it"
+
+func TestMarkdown_List(t *testing.T) {
+	source := NewMarkdown(writeTaskFile(t, "TODO.md", markdownFixture))
+
+	tasks, err := source.List()
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, "Write **docs**", tasks[0].Title)
+	assert.Equal(t, 4, tasks[0].Line)
+	assert.Equal(t, "Fix flaky test", tasks[1].Title)
+	assert.Equal(t, "Ship it", tasks[2].Title)
+	assert.Equal(t, 10, tasks[2].Line)
+}
+
+func TestMarkdown_CompleteKeepsFormatting(t *testing.T) {
+	path := writeTaskFile(t, "TODO.md", markdownFixture)
+	source := NewMarkdown(path)
+	tasks, err := source.List()
+	require.NoError(t, err)
+
+	require.NoError(t, source.Complete(tasks[1]))
+	require.NoError(t, source.Complete(tasks[2]))
+
+	expected := "# Sprint\r\n" +
+		"\r\n" +
+		"Some notes about the sprint.\r\n" +
+		"- [ ] Write **docs**\r\n" +
+		"  * [x] Set up CI\r\n" +
+		"  * [x]   Fix flaky test  \r\n" +
+		"```md\r\n" +
+		"- [ ] example in a code block\r\n" +
+		"```\r\n" +
+		"+ [x] Ship it"
+	assert.Equal(t, expected, readTaskFile(t, path))
+
+	remaining, err := source.List()
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+}
+
+func TestMarkdown_CompleteAfterItemChecked(t *testing.T) {
+	path := writeTaskFile(t, "TODO.md", "- [ ] a\n")
+	source := NewMarkdown(path)
+	tasks, err := source.List()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("- [x] a\n"), 0644))
+	err = source.Complete(tasks[0])
+
+	assert.True(t, errors.Is(err, ErrModified))
+}
@@ -0,0 +1,210 @@
+package task
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+)
+
+// ErrModified is returned when a task file changed since it was listed
+// and the task can no longer be found in it unambiguously
+var ErrModified = errors.New("task file changed on disk")
+
+// Task is a pending item from a task source
+type Task struct {
+	ID      string // Unique within the app, e.g. "todo.txt:12"
+	Title   string
+	Project string
+	Tags    []string
+	Source  string // Name of the source the task came from
+	Line    int    // 1-based line number in the source file
+	Ref     string // Source-specific identifier, e.g. a Taskwarrior UUID
+	raw     string // Line as read, used to find the task again
+}
+
+// Source is somewhere tasks are read from and completed in
+type Source interface {
+	Name() string
+	List() ([]Task, error)
+	Complete(t Task) error
+}
+
+// Tracker is a source that tracks when work on a task starts and stops
+type Tracker interface {
+	Start(t Task) error
+	Stop(t Task) error
+	Annotate(t Task, note string) error
+}
+
+// lineFile is a text file edited line by line without disturbing the rest
+type lineFile struct {
+	path     string
+	lines    []string // Lines including their line endings
+	checksum [32]byte // Checksum of the content as last read
+}
+
+// readLineFile reads path, keeping each line's original ending
+func readLineFile(path string) (*lineFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &lineFile{path: path, checksum: sha256.Sum256(data)}
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			f.lines = append(f.lines, string(data))
+			break
+		}
+		f.lines = append(f.lines, string(data[:i+1]))
+		data = data[i+1:]
+	}
+	return f, nil
+}
+
+// text returns line i (0-based) without its line ending
+func (f *lineFile) text(i int) string {
+	return strings.TrimRight(f.lines[i], "\r\n")
+}
+
+// replace swaps the text of line i, keeping its line ending
+func (f *lineFile) replace(i int, text string) {
+	line := f.lines[i]
+	ending := line[len(strings.TrimRight(line, "\r\n")):]
+	f.lines[i] = text + ending
+}
+
+// locate finds the 0-based line index of t in the current content
+// If the line moved because the file was edited, it is found by its text
+func (f *lineFile) locate(t Task) (int, error) {
+	if i := t.Line - 1; i >= 0 && i < len(f.lines) && f.text(i) == t.raw {
+		return i, nil
+	}
+
+	found := -1
+	for i := range f.lines {
+		if f.text(i) != t.raw {
+			continue
+		}
+		if found >= 0 {
+			return 0, fmt.Errorf("%s: %q appears more than once: %w", f.path, t.Title, ErrModified)
+		}
+		found = i
+	}
+	if found < 0 {
+		return 0, fmt.Errorf("%s: %q not found: %w", f.path, t.Title, ErrModified)
+	}
+	return found, nil
+}
+
+// write atomically replaces the file, refusing if it changed since read
+func (f *lineFile) write() error {
+	current, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	if sha256.Sum256(current) != f.checksum {
+		return fmt.Errorf("%s: %w", f.path, ErrModified)
+	}
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strings.Join(f.lines, "")); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// ListAll lists pending tasks from every source
+// Sources that fail are reported in the error but don't hide the others
+func ListAll(sources []Source) ([]Task, error) {
+	var tasks []Task
+	var errs []error
+	for _, s := range sources {
+		list, err := s.List()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name(), err))
+			continue
+		}
+		tasks = append(tasks, list...)
+	}
+	return tasks, errors.Join(errs...)
+}
+
+// Find returns the source a task came from
+func Find(sources []Source, t Task) (Source, bool) {
+	for _, s := range sources {
+		if s.Name() == t.Source {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// FromConfig builds sources for the configured files that exist
+// Paths may start with ~ and relative paths are resolved against dir
+func FromConfig(cfg config.TaskConfig, dir string) []Source {
+	var sources []Source
+	if path := resolvePath(cfg.TodoTxt, dir); path != "" {
+		sources = append(sources, NewTodoTxt(path))
+	}
+	for _, p := range cfg.Markdown {
+		if path := resolvePath(p, dir); path != "" {
+			sources = append(sources, NewMarkdown(path))
+		}
+	}
+	if cfg.Taskwarrior {
+		command := cfg.TaskwarriorCommand
+		if command == "" {
+			command = "task"
+		}
+		if path, err := exec.LookPath(command); err == nil {
+			sources = append(sources, NewTaskwarrior(path))
+		}
+	}
+	return sources
+}
+
+// resolvePath expands and resolves p, returning "" if no file is there
+func resolvePath(p, dir string) string {
+	if p == "" {
+		return ""
+	}
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		p = filepath.Join(home, rest)
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(dir, p)
+	}
+	if info, err := os.Stat(p); err != nil || info.IsDir() {
+		return ""
+	}
+	return p
+}
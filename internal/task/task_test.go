@@ -0,0 +1,99 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTaskFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func readTaskFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestReadLineFile_KeepsEndings(t *testing.T) {
+	path := writeTaskFile(t, "f.txt", "one\r\ntwo\nthree")
+
+	f, err := readLineFile(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one\r\n", "two\n", "three"}, f.lines)
+	assert.Equal(t, "one", f.text(0))
+
+	f.replace(0, "ONE")
+	f.replace(2, "THREE")
+	assert.Equal(t, []string{"ONE\r\n", "two\n", "THREE"}, f.lines)
+}
+
+func TestLineFile_WriteDetectsConcurrentChange(t *testing.T) {
+	path := writeTaskFile(t, "f.txt", "one\n")
+	f, err := readLineFile(path)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\n"), 0600))
+	f.replace(0, "ONE")
+	err = f.write()
+
+	assert.True(t, errors.Is(err, ErrModified))
+	assert.Equal(t, "one\ntwo\n", readTaskFile(t, path), "the newer file must not be clobbered")
+}
+
+func TestLineFile_WriteKeepsMode(t *testing.T) {
+	path := writeTaskFile(t, "f.txt", "one\n")
+	f, err := readLineFile(path)
+	require.NoError(t, err)
+
+	f.replace(0, "ONE")
+	require.NoError(t, f.write())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TODO.md"), []byte("- [ ] a\n"), 0644))
+	todo := writeTaskFile(t, "todo.txt", "a\n")
+
+	sources := FromConfig(config.TaskConfig{
+		TodoTxt:  todo,
+		Markdown: []string{"TODO.md", "MISSING.md"},
+	}, dir)
+
+	require.Len(t, sources, 2, "missing files are skipped")
+	assert.Equal(t, todo, sources[0].Name())
+	assert.Equal(t, filepath.Join(dir, "TODO.md"), sources[1].Name())
+}
+
+func TestListAll_And_Find(t *testing.T) {
+	md := NewMarkdown(writeTaskFile(t, "TODO.md", "- [ ] a\n"))
+	txt := NewTodoTxt(writeTaskFile(t, "todo.txt", "b\n"))
+	missing := NewTodoTxt(filepath.Join(t.TempDir(), "gone.txt"))
+	sources := []Source{md, missing, txt}
+
+	tasks, err := ListAll(sources)
+
+	assert.Error(t, err, "failing sources are reported")
+	require.Len(t, tasks, 2, "other sources are still listed")
+	source, ok := Find(sources, tasks[1])
+	assert.True(t, ok)
+	assert.Equal(t, txt, source)
+
+	_, ok = Find(sources, Task{Source: "elsewhere"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,105 @@
+package task
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// taskwarriorOverrides keep Taskwarrior from prompting or printing chatter
+var taskwarriorOverrides = []string{"rc.confirmation=off", "rc.verbose=nothing"}
+
+// Taskwarrior lists and tracks tasks through the task command
+type Taskwarrior struct {
+	Command string
+}
+
+// NewTaskwarrior creates a source that runs the given task binary
+func NewTaskwarrior(command string) *Taskwarrior {
+	return &Taskwarrior{Command: command}
+}
+
+// taskwarriorTask is the subset of `task export` output we use
+type taskwarriorTask struct {
+	ID          int      `json:"id"`
+	UUID        string   `json:"uuid"`
+	Description string   `json:"description"`
+	Project     string   `json:"project"`
+	Tags        []string `json:"tags"`
+	Status      string   `json:"status"`
+}
+
+// Name implements Source
+func (s *Taskwarrior) Name() string {
+	return "taskwarrior"
+}
+
+// List implements Source
+func (s *Taskwarrior) List() ([]Task, error) {
+	out, err := s.run("status:pending", "export")
+	if err != nil {
+		return nil, err
+	}
+
+	var exported []taskwarriorTask
+	if err := json.Unmarshal(out, &exported); err != nil {
+		return nil, fmt.Errorf("task export: %w", err)
+	}
+
+	var tasks []Task
+	for _, t := range exported {
+		if t.Status != "" && t.Status != "pending" {
+			continue
+		}
+		tasks = append(tasks, Task{
+			ID:      "taskwarrior:" + t.UUID,
+			Title:   t.Description,
+			Project: t.Project,
+			Tags:    t.Tags,
+			Source:  s.Name(),
+			Ref:     t.UUID,
+		})
+	}
+	return tasks, nil
+}
+
+// Complete implements Source
+func (s *Taskwarrior) Complete(t Task) error {
+	_, err := s.run(t.Ref, "done")
+	return err
+}
+
+// Start implements Tracker
+func (s *Taskwarrior) Start(t Task) error {
+	_, err := s.run(t.Ref, "start")
+	return err
+}
+
+// Stop implements Tracker
+func (s *Taskwarrior) Stop(t Task) error {
+	_, err := s.run(t.Ref, "stop")
+	return err
+}
+
+// Annotate implements Tracker
+func (s *Taskwarrior) Annotate(t Task, note string) error {
+	_, err := s.run(t.Ref, "annotate", note)
+	return err
+}
+
+// run executes task with the overrides and returns its stdout
+func (s *Taskwarrior) run(args ...string) ([]byte, error) {
+	cmd := exec.Command(s.Command, append(append([]string{}, taskwarriorOverrides...), args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("task %s: %s", strings.Join(args, " "), msg)
+		}
+		return nil, fmt.Errorf("task %s: %w", strings.Join(args, " "), err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,124 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taskwarriorExport = `[
+{"id":1,"uuid":"a1b2","description":"Refactor parser","project":"api","tags":["backend"],"status":"pending"},
+{"id":2,"uuid":"c3d4","description":"Write release notes","status":"pending"},
+{"id":0,"uuid":"e5f6","description":"Old","status":"completed"}
+]`
+
+// fakeTaskwarrior writes a stand-in task binary that logs its arguments
+// and prints export JSON, so tests run without Taskwarrior installed
+func fakeTaskwarrior(t *testing.T, export string, exitCode int) (command, log string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake task binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	log = filepath.Join(dir, "calls.log")
+	exportFile := filepath.Join(dir, "export.json")
+	require.NoError(t, os.WriteFile(exportFile, []byte(export), 0644))
+
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$@" >> %q
+case "$*" in
+  *" export") cat %q ;;
+esac
+if [ %d -ne 0 ]; then echo "Task not found." >&2; fi
+exit %d
+`, log, exportFile, exitCode, exitCode)
+	command = filepath.Join(dir, "task")
+	require.NoError(t, os.WriteFile(command, []byte(script), 0755))
+	return command, log
+}
+
+func readCalls(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestTaskwarrior_List(t *testing.T) {
+	command, log := fakeTaskwarrior(t, taskwarriorExport, 0)
+	source := NewTaskwarrior(command)
+
+	tasks, err := source.List()
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Refactor parser", tasks[0].Title)
+	assert.Equal(t, "api", tasks[0].Project)
+	assert.Equal(t, []string{"backend"}, tasks[0].Tags)
+	assert.Equal(t, "a1b2", tasks[0].Ref)
+	assert.Equal(t, "taskwarrior", tasks[0].Source)
+	assert.Equal(t, []string{"rc.confirmation=off rc.verbose=nothing status:pending export"}, readCalls(t, log))
+}
+
+func TestTaskwarrior_TrackingCommands(t *testing.T) {
+	command, log := fakeTaskwarrior(t, "[]", 0)
+	source := NewTaskwarrior(command)
+	task := Task{Title: "Refactor parser", Ref: "a1b2"}
+
+	require.NoError(t, source.Start(task))
+	require.NoError(t, source.Stop(task))
+	require.NoError(t, source.Annotate(task, "Pomodoro #2 completed (25m)"))
+	require.NoError(t, source.Complete(task))
+
+	assert.Equal(t, []string{
+		"rc.confirmation=off rc.verbose=nothing a1b2 start",
+		"rc.confirmation=off rc.verbose=nothing a1b2 stop",
+		"rc.confirmation=off rc.verbose=nothing a1b2 annotate Pomodoro #2 completed (25m)",
+		"rc.confirmation=off rc.verbose=nothing a1b2 done",
+	}, readCalls(t, log))
+}
+
+func TestTaskwarrior_Errors(t *testing.T) {
+	command, _ := fakeTaskwarrior(t, "[]", 1)
+	source := NewTaskwarrior(command)
+
+	err := source.Start(Task{Ref: "zzzz"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Task not found.")
+
+	command, _ = fakeTaskwarrior(t, "not json", 0)
+	_, err = NewTaskwarrior(command).List()
+	assert.Error(t, err)
+
+	_, err = NewTaskwarrior(filepath.Join(t.TempDir(), "missing")).List()
+	assert.Error(t, err)
+}
+
+func TestTaskwarrior_IsTracker(t *testing.T) {
+	var source Source = NewTaskwarrior("task")
+	_, ok := source.(Tracker)
+	assert.True(t, ok)
+
+	source = NewMarkdown("TODO.md")
+	_, ok = source.(Tracker)
+	assert.False(t, ok)
+}
+
+func TestFromConfig_Taskwarrior(t *testing.T) {
+	command, _ := fakeTaskwarrior(t, "[]", 0)
+
+	sources := FromConfig(config.TaskConfig{Taskwarrior: true, TaskwarriorCommand: command}, t.TempDir())
+	require.Len(t, sources, 1)
+	assert.Equal(t, "taskwarrior", sources[0].Name())
+
+	sources = FromConfig(config.TaskConfig{Taskwarrior: true, TaskwarriorCommand: filepath.Join(t.TempDir(), "nope")}, t.TempDir())
+	assert.Empty(t, sources, "a missing binary is skipped")
+}
@@ -0,0 +1,110 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// todo.txt line prefixes, see https://github.com/todotxt/todo.txt
+var (
+	todoPriority = regexp.MustCompile(`^\(([A-Z])\) `)
+	todoDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} `)
+)
+
+// TodoTxt reads and completes tasks in a todo.txt file
+type TodoTxt struct {
+	Path string
+	Now  func() time.Time // Completion date source, time.Now if nil
+}
+
+// NewTodoTxt creates a source for the todo.txt file at path
+func NewTodoTxt(path string) *TodoTxt {
+	return &TodoTxt{Path: path}
+}
+
+// Name implements Source
+func (s *TodoTxt) Name() string {
+	return s.Path
+}
+
+// List implements Source
+func (s *TodoTxt) List() ([]Task, error) {
+	f, err := readLineFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []Task
+	for i := range f.lines {
+		raw := f.text(i)
+		if strings.TrimSpace(raw) == "" || strings.HasPrefix(raw, "x ") {
+			continue
+		}
+		_, _, text := splitTodoLine(raw)
+		t := Task{
+			ID:     fmt.Sprintf("%s:%d", s.Path, i+1),
+			Title:  text,
+			Source: s.Name(),
+			Line:   i + 1,
+			raw:    raw,
+		}
+		for _, word := range strings.Fields(text) {
+			if project, ok := strings.CutPrefix(word, "+"); ok && project != "" && t.Project == "" {
+				t.Project = project
+			}
+			if context, ok := strings.CutPrefix(word, "@"); ok && context != "" {
+				t.Tags = append(t.Tags, context)
+			}
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// Complete implements Source
+// The line is marked "x <today>", keeping its creation date and moving any
+// priority into a pri: tag as the todo.txt format recommends
+func (s *TodoTxt) Complete(t Task) error {
+	f, err := readLineFile(s.Path)
+	if err != nil {
+		return err
+	}
+	i, err := f.locate(t)
+	if err != nil {
+		return err
+	}
+
+	priority, created, text := splitTodoLine(f.text(i))
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	done := "x " + now().Format("2006-01-02") + " "
+	if created != "" {
+		done += created + " "
+	}
+	done += text
+	if priority != "" {
+		done += " pri:" + priority
+	}
+	f.replace(i, done)
+	return f.write()
+}
+
+// splitTodoLine splits an incomplete todo.txt line into its priority,
+// creation date and remaining text
+func splitTodoLine(line string) (priority, created, text string) {
+	text = line
+	if m := todoPriority.FindStringSubmatch(text); m != nil {
+		priority = m[1]
+		text = text[len(m[0]):]
+	}
+	if m := todoDate.FindString(text); m != "" {
+		created = strings.TrimSpace(m)
+		text = text[len(m):]
+	}
+	return priority, created, text
+}
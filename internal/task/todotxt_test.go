@@ -0,0 +1,106 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const todoFixture = `(A) 2024-01-10 Call mom +family @phone
+x 2024-01-09 2024-01-08 Already done
+2024-01-11 Write report +work @office @laptop
+
+Buy milk
+`
+
+func fixedNow() time.Time {
+	return time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+}
+
+func TestTodoTxt_List(t *testing.T) {
+	source := NewTodoTxt(writeTaskFile(t, "todo.txt", todoFixture))
+
+	tasks, err := source.List()
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, "Call mom +family @phone", tasks[0].Title)
+	assert.Equal(t, "family", tasks[0].Project)
+	assert.Equal(t, []string{"phone"}, tasks[0].Tags)
+	assert.Equal(t, 1, tasks[0].Line)
+	assert.Equal(t, []string{"office", "laptop"}, tasks[1].Tags)
+	assert.Equal(t, "Buy milk", tasks[2].Title)
+	assert.Equal(t, 5, tasks[2].Line)
+}
+
+func TestTodoTxt_Complete(t *testing.T) {
+	path := writeTaskFile(t, "todo.txt", todoFixture)
+	source := &TodoTxt{Path: path, Now: fixedNow}
+	tasks, err := source.List()
+	require.NoError(t, err)
+
+	require.NoError(t, source.Complete(tasks[0]))
+	require.NoError(t, source.Complete(tasks[2]))
+
+	expected := `x 2024-01-15 2024-01-10 Call mom +family @phone pri:A
+x 2024-01-09 2024-01-08 Already done
+2024-01-11 Write report +work @office @laptop
+
+x 2024-01-15 Buy milk
+`
+	assert.Equal(t, expected, readTaskFile(t, path))
+}
+
+func TestTodoTxt_CompleteAfterUnrelatedEdit(t *testing.T) {
+	path := writeTaskFile(t, "todo.txt", "Buy milk\nWalk dog\n")
+	source := &TodoTxt{Path: path, Now: fixedNow}
+	tasks, err := source.List()
+	require.NoError(t, err)
+
+	// Someone adds a task at the top while we work
+	require.NoError(t, os.WriteFile(path, []byte("New task\r\nBuy milk\nWalk dog\n"), 0644))
+
+	require.NoError(t, source.Complete(tasks[1]))
+	assert.Equal(t, "New task\r\nBuy milk\nx 2024-01-15 Walk dog\n", readTaskFile(t, path))
+}
+
+func TestTodoTxt_CompleteAfterTaskRemoved(t *testing.T) {
+	path := writeTaskFile(t, "todo.txt", "Buy milk\n")
+	source := &TodoTxt{Path: path, Now: fixedNow}
+	tasks, err := source.List()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("Buy oat milk\n"), 0644))
+	err = source.Complete(tasks[0])
+
+	assert.True(t, errors.Is(err, ErrModified))
+	assert.Equal(t, "Buy oat milk\n", readTaskFile(t, path))
+}
+
+func TestTodoTxt_CompleteAmbiguous(t *testing.T) {
+	path := writeTaskFile(t, "todo.txt", "Stretch\n")
+	source := &TodoTxt{Path: path, Now: fixedNow}
+	tasks, err := source.List()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("Drink water\nStretch\nStretch\n"), 0644))
+	err = source.Complete(tasks[0])
+
+	assert.True(t, errors.Is(err, ErrModified))
+}
+
+func TestSplitTodoLine(t *testing.T) {
+	priority, created, text := splitTodoLine("(B) 2024-01-10 Do thing")
+	assert.Equal(t, "B", priority)
+	assert.Equal(t, "2024-01-10", created)
+	assert.Equal(t, "Do thing", text)
+
+	priority, created, text = splitTodoLine("(b) Do thing")
+	assert.Empty(t, priority, "lowercase is not a priority")
+	assert.Empty(t, created)
+	assert.Equal(t, "(b) Do thing", text)
+}
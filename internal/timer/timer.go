@@ -22,6 +22,36 @@ const (
 	PomodorosBeforeLongBreak = 4
 )
 
+// Durations sets session lengths and how many pomodoros make a cycle
+type Durations struct {
+	Work       time.Duration
+	ShortBreak time.Duration
+	LongBreak  time.Duration
+	Cycle      int // Work sessions before a long break
+}
+
+// DefaultDurations returns the standard Pomodoro timings
+func DefaultDurations() Durations {
+	return Durations{
+		Work:       WorkDuration,
+		ShortBreak: ShortBreakDuration,
+		LongBreak:  LongBreakDuration,
+		Cycle:      PomodorosBeforeLongBreak,
+	}
+}
+
+// For returns the length of a session type
+func (d Durations) For(sessionType SessionType) time.Duration {
+	switch sessionType {
+	case ShortBreak:
+		return d.ShortBreak
+	case LongBreak:
+		return d.LongBreak
+	default:
+		return d.Work
+	}
+}
+
 // Timer represents the pomodoro timer state
 type Timer struct {
 	SessionType    SessionType
@@ -30,19 +60,54 @@ type Timer struct {
 	Running        bool
 	PomodoroCount  int // Completed work sessions in the current cycle (0-4)
 	TotalPomodoros int // Total pomodoros completed
+	Durations      Durations
+
+	warned bool // Whether the pre-end warning was given this session
 }
 
 // New creates a new timer starting with a work session
 func New() *Timer {
+	return NewWithDurations(DefaultDurations())
+}
+
+// NewWithDurations creates a new timer with custom session lengths
+func NewWithDurations(d Durations) *Timer {
 	return &Timer{
 		SessionType:    Work,
-		Duration:       WorkDuration,
-		Remaining:      WorkDuration,
+		Duration:       d.Work,
+		Remaining:      d.Work,
 		Running:        false,
 		PomodoroCount:  0,
 		TotalPomodoros: 0,
+		Durations:      d,
+	}
+}
+
+// SetDurations changes session lengths
+// A session that hasn't started yet picks up its new length immediately;
+// one in progress keeps its length and the change applies from the next
+func (t *Timer) SetDurations(d Durations) {
+	t.Durations = d
+	if !t.Running && t.Remaining == t.Duration {
+		t.Duration = d.For(t.SessionType)
+		t.Remaining = t.Duration
+	}
+}
+
+// Cycle returns how many work sessions come before a long break
+func (t *Timer) Cycle() int {
+	if t.Durations.Cycle <= 0 {
+		return PomodorosBeforeLongBreak
 	}
+	return t.Durations.Cycle
+}
 
+// durations returns the configured lengths, falling back to the defaults
+func (t *Timer) durations() Durations {
+	if t.Durations == (Durations{}) {
+		return DefaultDurations()
+	}
+	return t.Durations
 }
 
 // Start begins the timer
@@ -62,8 +127,29 @@ func (t *Timer) Toggle() {
 
 // Reset resets the current session timer
 func (t *Timer) Reset() {
+	t.Duration = t.durations().For(t.SessionType)
 	t.Remaining = t.Duration
 	t.Running = false
+	t.warned = false
+}
+
+// SetLength makes the current session d long from now, e.g. to end it as
+// a meeting starts
+func (t *Timer) SetLength(d time.Duration) {
+	t.Duration = d
+	t.Remaining = d
+	t.warned = false
+}
+
+// UseLongBreak turns a short break that hasn't started into a long break,
+// beginning a new cycle
+func (t *Timer) UseLongBreak() {
+	if t.SessionType != ShortBreak || t.Running || t.Remaining != t.Duration {
+		return
+	}
+	t.SessionType = LongBreak
+	t.PomodoroCount = 0
+	t.Reset()
 }
 
 // Tick decrements the timer by one second
@@ -78,6 +164,23 @@ func (t *Timer) IsComplete() bool {
 	return t.Remaining <= 0
 }
 
+// InWarning returns whether the session is within lead of its end
+// A lead as long as the session itself gives no warning
+func (t *Timer) InWarning(lead time.Duration) bool {
+	return lead > 0 && lead < t.Duration && t.Remaining > 0 && t.Remaining <= lead
+}
+
+// WarningDue reports the first time in a session that it is within lead
+// of its end, and false after that until the session is reset or over
+// However ticks arrive, and across pauses, the warning is due only once
+func (t *Timer) WarningDue(lead time.Duration) bool {
+	if t.warned || !t.InWarning(lead) {
+		return false
+	}
+	t.warned = true
+	return true
+}
+
 // Progress returns the completion percentage (0.0 to 1.0)
 func (t *Timer) Progress() float64 {
 	if t.Duration == 0 {
@@ -106,25 +209,23 @@ func (t *Timer) completeSession(countWork bool) {
 
 	switch t.SessionType {
 	case Work:
-		if t.PomodoroCount >= PomodorosBeforeLongBreak {
+		if t.PomodoroCount >= t.Cycle() {
 			t.SessionType = LongBreak
-			t.Duration = LongBreakDuration
 			t.PomodoroCount = 0
 		} else {
 			t.SessionType = ShortBreak
-			t.Duration = ShortBreakDuration
 		}
 	case ShortBreak:
 		t.SessionType = Work
-		t.Duration = WorkDuration
 	case LongBreak:
 		t.SessionType = Work
-		t.Duration = WorkDuration
 		// PomodoroCount remains at 0 after long break.
 	}
 
+	t.Duration = t.durations().For(t.SessionType)
 	t.Remaining = t.Duration
 	t.Running = false
+	t.warned = false
 }
 
 // NextSession prepares for the next session (called after user confirmation)
@@ -136,25 +237,23 @@ func (t *Timer) NextSession() {
 
 	switch t.SessionType {
 	case Work:
-		if t.PomodoroCount >= PomodorosBeforeLongBreak {
+		if t.PomodoroCount >= t.Cycle() {
 			t.SessionType = LongBreak
-			t.Duration = LongBreakDuration
 			t.PomodoroCount = 0
 		} else {
 			t.SessionType = ShortBreak
-			t.Duration = ShortBreakDuration
 		}
 	case ShortBreak:
 		t.SessionType = Work
-		t.Duration = WorkDuration
 	case LongBreak:
 		t.SessionType = Work
-		t.Duration = WorkDuration
 		// PomodoroCount remains at 0 after long break.
 	}
 
+	t.Duration = t.durations().For(t.SessionType)
 	t.Remaining = t.Duration
 	t.Running = false
+	t.warned = false
 }
 
 // FormatRemaining returns the remaining time as MM:SS
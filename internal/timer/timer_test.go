@@ -64,6 +64,46 @@ func TestReset(t *testing.T) {
 	assert.False(t, timer.Running, "reset should pause the timer")
 }
 
+func TestSetLength(t *testing.T) {
+	timer := New()
+	timer.Start()
+
+	timer.SetLength(12 * time.Minute)
+
+	assert.Equal(t, 12*time.Minute, timer.Duration)
+	assert.Equal(t, 12*time.Minute, timer.Remaining)
+	assert.True(t, timer.Running, "keeps running")
+	assert.Equal(t, 0.0, timer.Progress())
+
+	timer.Reset()
+	assert.Equal(t, DefaultDurations().Work, timer.Duration, "reset restores the configured length")
+}
+
+func TestUseLongBreak(t *testing.T) {
+	timer := New()
+	timer.CompleteSession()
+	require.Equal(t, ShortBreak, timer.SessionType)
+
+	timer.UseLongBreak()
+
+	assert.Equal(t, LongBreak, timer.SessionType)
+	assert.Equal(t, DefaultDurations().LongBreak, timer.Remaining)
+	assert.Equal(t, 0, timer.PomodoroCount, "a new cycle begins")
+	assert.Equal(t, 1, timer.TotalPomodoros)
+}
+
+func TestUseLongBreak_LeavesStartedSessions(t *testing.T) {
+	timer := New()
+	timer.UseLongBreak()
+	assert.Equal(t, Work, timer.SessionType)
+
+	timer.CompleteSession()
+	timer.Start()
+	timer.Tick()
+	timer.UseLongBreak()
+	assert.Equal(t, ShortBreak, timer.SessionType)
+}
+
 func TestTick(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -603,3 +643,101 @@ func TestNextSession(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWithDurations(t *testing.T) {
+	d := Durations{Work: 50 * time.Minute, ShortBreak: 10 * time.Minute, LongBreak: 30 * time.Minute, Cycle: 2}
+	timer := NewWithDurations(d)
+
+	assert.Equal(t, 50*time.Minute, timer.Duration)
+	assert.Equal(t, 50*time.Minute, timer.Remaining)
+	assert.Equal(t, 2, timer.Cycle())
+
+	timer.CompleteSession()
+	assert.Equal(t, ShortBreak, timer.SessionType)
+	assert.Equal(t, 10*time.Minute, timer.Duration)
+
+	timer.CompleteSession()
+	timer.CompleteSession()
+	assert.Equal(t, LongBreak, timer.SessionType, "long break should follow the configured cycle")
+	assert.Equal(t, 30*time.Minute, timer.Duration)
+}
+
+func TestSetDurations_IdleSessionUpdates(t *testing.T) {
+	timer := New()
+	timer.SetDurations(Durations{Work: 45 * time.Minute, ShortBreak: 5 * time.Minute, LongBreak: 15 * time.Minute, Cycle: 4})
+
+	assert.Equal(t, 45*time.Minute, timer.Duration)
+	assert.Equal(t, 45*time.Minute, timer.Remaining)
+}
+
+func TestSetDurations_SessionInProgressKeepsLength(t *testing.T) {
+	timer := New()
+	timer.Start()
+	timer.Tick()
+	timer.SetDurations(Durations{Work: 45 * time.Minute, ShortBreak: 8 * time.Minute, LongBreak: 15 * time.Minute, Cycle: 4})
+
+	assert.Equal(t, WorkDuration, timer.Duration, "running session should keep its length")
+
+	timer.CompleteSession()
+	assert.Equal(t, 8*time.Minute, timer.Duration, "next session should use the new length")
+}
+
+func TestCycle_ZeroValueUsesDefault(t *testing.T) {
+	timer := &Timer{SessionType: Work}
+
+	assert.Equal(t, PomodorosBeforeLongBreak, timer.Cycle())
+	timer.Skip()
+	assert.Equal(t, ShortBreakDuration, timer.Duration)
+}
+
+func TestInWarning(t *testing.T) {
+	timer := New()
+
+	assert.False(t, timer.InWarning(time.Minute), "not started")
+	timer.Remaining = time.Minute
+	assert.True(t, timer.InWarning(time.Minute))
+	assert.False(t, timer.InWarning(0), "no lead, no warning")
+	assert.False(t, timer.InWarning(WorkDuration), "lead as long as the session")
+	timer.Remaining = 0
+	assert.False(t, timer.InWarning(time.Minute), "session over")
+}
+
+func TestWarningDue_Once(t *testing.T) {
+	timer := New()
+	timer.Start()
+	timer.Remaining = time.Minute + 2*time.Second
+
+	var due []time.Duration
+	for timer.Remaining > 0 {
+		timer.Tick()
+		if timer.WarningDue(time.Minute) {
+			due = append(due, timer.Remaining)
+		}
+	}
+
+	assert.Equal(t, []time.Duration{time.Minute}, due)
+}
+
+func TestWarningDue_DroppedTicksAndPauses(t *testing.T) {
+	timer := New()
+	timer.Start()
+	timer.Remaining = 2 * time.Minute
+
+	// Ticks were lost and the threshold jumped over
+	timer.Remaining = 50 * time.Second
+	assert.True(t, timer.WarningDue(time.Minute))
+
+	timer.Pause()
+	timer.Start()
+	timer.Tick()
+	assert.False(t, timer.WarningDue(time.Minute), "resuming doesn't warn again")
+
+	timer.Reset()
+	timer.Start()
+	timer.Remaining = 30 * time.Second
+	assert.True(t, timer.WarningDue(time.Minute), "a reset session warns again")
+
+	timer.CompleteSession()
+	timer.Remaining = 30 * time.Second
+	assert.True(t, timer.WarningDue(time.Minute), "the next session warns again")
+}
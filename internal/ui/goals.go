@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// GoalProgress is progress toward a pomodoro count and focus time target
+// Zero targets are not shown
+type GoalProgress struct {
+	Pomodoros      int
+	PomodoroTarget int
+	Focus          time.Duration
+	FocusTarget    time.Duration
+}
+
+// Tracked reports whether any target is set
+func (g GoalProgress) Tracked() bool {
+	return g.PomodoroTarget > 0 || g.FocusTarget > 0
+}
+
+// Met reports whether every set target has been reached
+func (g GoalProgress) Met() bool {
+	if !g.Tracked() {
+		return false
+	}
+	if g.PomodoroTarget > 0 && g.Pomodoros < g.PomodoroTarget {
+		return false
+	}
+	if g.FocusTarget > 0 && g.Focus < g.FocusTarget {
+		return false
+	}
+	return true
+}
+
+// String renders progress as "3/8 · 1h15m/4h"
+func (g GoalProgress) String() string {
+	var parts []string
+	if g.PomodoroTarget > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d", g.Pomodoros, g.PomodoroTarget))
+	}
+	if g.FocusTarget > 0 {
+		parts = append(parts, FormatDuration(g.Focus)+"/"+FormatDuration(g.FocusTarget))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatDuration renders a duration compactly in hours and minutes, e.g. "1h15m"
+func FormatDuration(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	}
+}
+
+// RenderGoals renders daily and weekly progress for the session info line
+func RenderGoals(daily, weekly GoalProgress) string {
+	var parts []string
+	if daily.Tracked() {
+		label := "Today " + daily.String()
+		if daily.Met() {
+			label += " ✓"
+		}
+		parts = append(parts, label)
+	}
+	if weekly.Tracked() {
+		label := "Week " + weekly.String()
+		if weekly.Met() {
+			label += " ✓"
+		}
+		parts = append(parts, label)
+	}
+	return strings.Join(parts, " • ")
+}
+
+// RenderCelebration renders the overlay shown when a goal is reached
+func RenderCelebration(message string, width, height int) string {
+	celebrationStyle := lipgloss.NewStyle().
+		Background(Neon).
+		Foreground(DarkBg).
+		Bold(true).
+		Padding(1, 4)
+
+	return lipgloss.Place(
+		width, height,
+		lipgloss.Center, lipgloss.Center,
+		celebrationStyle.Render("🏆 "+message),
+	)
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoalProgress_Met(t *testing.T) {
+	tests := []struct {
+		name     string
+		goal     GoalProgress
+		tracked  bool
+		expected bool
+	}{
+		{"untracked", GoalProgress{Pomodoros: 9}, false, false},
+		{"count below", GoalProgress{Pomodoros: 3, PomodoroTarget: 8}, true, false},
+		{"count reached", GoalProgress{Pomodoros: 8, PomodoroTarget: 8}, true, true},
+		{"focus reached", GoalProgress{Focus: 4 * time.Hour, FocusTarget: 4 * time.Hour}, true, true},
+		{
+			"both needed",
+			GoalProgress{Pomodoros: 8, PomodoroTarget: 8, Focus: time.Hour, FocusTarget: 4 * time.Hour},
+			true, false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.tracked, tt.goal.Tracked())
+			assert.Equal(t, tt.expected, tt.goal.Met())
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "0m", FormatDuration(0))
+	assert.Equal(t, "45m", FormatDuration(45*time.Minute+30*time.Second))
+	assert.Equal(t, "4h", FormatDuration(4*time.Hour))
+	assert.Equal(t, "1h05m", FormatDuration(65*time.Minute))
+}
+
+func TestRenderGoals(t *testing.T) {
+	daily := GoalProgress{Pomodoros: 3, PomodoroTarget: 8, Focus: 75 * time.Minute, FocusTarget: 4 * time.Hour}
+	weekly := GoalProgress{Pomodoros: 40, PomodoroTarget: 40}
+
+	result := RenderGoals(daily, weekly)
+
+	assert.Equal(t, "Today 3/8 · 1h15m/4h • Week 40/40 ✓", result)
+	assert.Empty(t, RenderGoals(GoalProgress{}, GoalProgress{}))
+}
+
+func TestRenderCelebration(t *testing.T) {
+	result := RenderCelebration("Daily goal reached!", 80, 24)
+
+	assert.Contains(t, result, "Daily goal reached!")
+}
@@ -17,6 +17,10 @@ var helpItems = []HelpItem{
 	{"space/enter", "start/pause timer"},
 	{"s", "skip session"},
 	{"r", "reset timer"},
+	{"t", "set project/tags"},
+	{"a", "pick active task"},
+	{"x", "mark task done"},
+	{"p", "switch profile"},
 	{"n", "toggle notifications"},
 	{"?", "toggle help"},
 	{"q/ctrl+c", "quit"},
@@ -24,6 +28,11 @@ var helpItems = []HelpItem{
 
 // RenderHelp creates the help overlay
 func RenderHelp() string {
+	return RenderHelpItems(helpItems)
+}
+
+// RenderHelpItems creates the help overlay for the given bindings
+func RenderHelpItems(items []HelpItem) string {
 	var content strings.Builder
 
 	// Title
@@ -36,7 +45,7 @@ func RenderHelp() string {
 	content.WriteString("\n\n")
 
 	// Help items
-	for _, item := range helpItems {
+	for _, item := range items {
 		key := HelpKeyStyle.Render(item.Key)
 		desc := HelpDescStyle.Render(item.Desc)
 		content.WriteString(key + desc + "\n")
@@ -48,7 +57,13 @@ func RenderHelp() string {
 
 // RenderHelpCentered renders the help overlay centered in the terminal
 func RenderHelpCentered(width, height int) string {
-	help := RenderHelp()
+	return RenderHelpItemsCentered(helpItems, width, height)
+}
+
+// RenderHelpItemsCentered renders the help overlay for the given bindings
+// centered in the terminal
+func RenderHelpItemsCentered(items []HelpItem, width, height int) string {
+	help := RenderHelpItems(items)
 	return lipgloss.Place(
 		width, height,
 		lipgloss.Center, lipgloss.Center,
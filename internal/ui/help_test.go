@@ -17,6 +17,10 @@ func TestRenderHelp_ContainsAllKeyBindings(t *testing.T) {
 		"skip session",
 		"r",
 		"reset timer",
+		"t",
+		"set project/tags",
+		"pick active task",
+		"mark task done",
 		"n",
 		"toggle notifications",
 		"?",
@@ -89,3 +93,11 @@ func TestHelpItems_AllDefined(t *testing.T) {
 		assert.NotEmpty(t, item.Desc, "help item %d should have a description", i)
 	}
 }
+
+func TestRenderHelpItems(t *testing.T) {
+	result := RenderHelpItems([]HelpItem{{"p", "start/pause"}})
+
+	assert.Contains(t, result, "Keyboard Shortcuts")
+	assert.Contains(t, result, "start/pause")
+	assert.NotContains(t, result, "skip session")
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxPickerItems caps how many suggestions a picker shows
+const maxPickerItems = 8
+
+// FuzzyFilter returns items containing query as a case-insensitive
+// subsequence, best matches first. An empty query keeps every item
+func FuzzyFilter(query string, items []string) []string {
+	indices := FuzzyIndices(query, items)
+	result := make([]string, len(indices))
+	for i, index := range indices {
+		result[i] = items[index]
+	}
+	return result
+}
+
+// FuzzyIndices is FuzzyFilter returning positions in items instead
+func FuzzyIndices(query string, items []string) []int {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	type match struct {
+		index int
+		score int
+	}
+	var matches []match
+	for i, item := range items {
+		if query == "" {
+			matches = append(matches, match{i, 0})
+			continue
+		}
+		if score, ok := fuzzyScore(query, strings.ToLower(item)); ok {
+			matches = append(matches, match{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]int, len(matches))
+	for i, m := range matches {
+		result[i] = m.index
+	}
+	return result
+}
+
+// fuzzyScore scores a subsequence match, favouring consecutive runs,
+// word starts and prefixes
+func fuzzyScore(query, item string) (int, bool) {
+	runes := []rune(item)
+	score, qi, run := 0, 0, 0
+	q := []rune(query)
+	for i, r := range runes {
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			run = 0
+			continue
+		}
+		run++
+		score += run
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 3
+		}
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Shorter items are closer matches
+	return score*100 - len(runes), true
+}
+
+// RenderPicker renders a text input above a list of suggestions
+func RenderPicker(title, input string, items []string, selected int, hint string) string {
+	var content strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Foreground(Cyan).Bold(true)
+	content.WriteString(titleStyle.Render(title))
+	content.WriteString("\n\n")
+	content.WriteString(input)
+	content.WriteString("\n\n")
+
+	selectedStyle := lipgloss.NewStyle().Foreground(HotPink).Bold(true)
+	for i, item := range items {
+		if i == maxPickerItems {
+			content.WriteString(HelpDescStyle.Render("  …"))
+			content.WriteString("\n")
+			break
+		}
+		if i == selected {
+			content.WriteString(selectedStyle.Render("› " + item))
+		} else {
+			content.WriteString(HelpDescStyle.Render("  " + item))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString(HelpStyle.Render(hint))
+
+	return HelpOverlayStyle.Render(content.String())
+}
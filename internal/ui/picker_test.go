@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyFilter(t *testing.T) {
+	items := []string{"api-server", "website", "pomodoro", "apps"}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"empty keeps all", "", items},
+		{"prefix ranks first", "ap", []string{"apps", "api-server"}},
+		{"subsequence", "pmd", []string{"pomodoro"}},
+		{"word start", "srv", []string{"api-server"}},
+		{"case insensitive", "WEB", []string{"website"}},
+		{"no match", "xyz", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FuzzyFilter(tt.query, items))
+		})
+	}
+}
+
+func TestFuzzyIndices(t *testing.T) {
+	items := []string{"Write docs", "Fix bug", "Write docs"}
+
+	assert.Equal(t, []int{0, 2}, FuzzyIndices("docs", items), "duplicates keep their own positions")
+	assert.Equal(t, []int{0, 1, 2}, FuzzyIndices("", items))
+}
+
+func TestRenderPicker(t *testing.T) {
+	result := RenderPicker("Pick", "> ap", []string{"api", "apps"}, 1, "ENTER to apply")
+
+	assert.Contains(t, result, "Pick")
+	assert.Contains(t, result, "> ap")
+	assert.Contains(t, result, "› apps")
+	assert.Contains(t, result, "  api")
+	assert.Contains(t, result, "ENTER to apply")
+}
+
+func TestRenderPicker_TruncatesLongLists(t *testing.T) {
+	var items []string
+	for i := 0; i < 20; i++ {
+		items = append(items, fmt.Sprintf("item-%02d", i))
+	}
+
+	result := RenderPicker("Pick", "", items, 0, "")
+
+	assert.Contains(t, result, "item-07")
+	assert.NotContains(t, result, "item-08")
+	assert.Contains(t, result, "…")
+}
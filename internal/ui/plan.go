@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kanishkathakur1/pomodoro/internal/plan"
+)
+
+// PlanLine renders a block as planned versus actual, e.g.
+// "14:00  Reviews                    1/2  started 14:05"
+func PlanLine(b plan.Block) string {
+	start := b.Start
+	if start == "" {
+		start = "  ·"
+	}
+	name := b.Task
+	progress := fmt.Sprintf("%d/%d", b.Done, b.Count)
+	if b.Break {
+		name = "Long break"
+		progress = "–"
+		if b.Done > 0 {
+			progress = "✓"
+		}
+	}
+	if runes := []rune(name); len(runes) > 24 {
+		name = string(runes[:23]) + "…"
+	}
+	line := fmt.Sprintf("%-5s  %-24s %5s", start, name, progress)
+	if !b.Started.IsZero() {
+		line += "  started " + b.Started.Local().Format("15:04")
+	}
+	return line
+}
+
+// PlanTotals summarizes the work sessions done against the plan
+func PlanTotals(p *plan.Plan) string {
+	planned, done := p.Totals()
+	return fmt.Sprintf("%d of %d planned pomodoros done", done, planned)
+}
+
+// RenderPlan renders the day plan editor: the blocks with the current one
+// marked, and an input for adding more
+func RenderPlan(p *plan.Plan, now time.Time, input string, selected int, message, hint string) string {
+	var content strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Foreground(Cyan).Bold(true)
+	content.WriteString(titleStyle.Render("▤ Day Plan"))
+	content.WriteString("\n\n")
+
+	if len(p.Blocks) == 0 {
+		content.WriteString(HelpDescStyle.Render("Nothing planned yet, add blocks like \"3 API refactor\""))
+		content.WriteString("\n")
+	}
+	current := p.Current(now)
+	selectedStyle := lipgloss.NewStyle().Foreground(HotPink).Bold(true)
+	for i, b := range p.Blocks {
+		marker := " "
+		switch {
+		case i == current:
+			marker = "▶"
+		case b.Finished():
+			marker = "✓"
+		}
+		line := marker + " " + PlanLine(b)
+		if i == selected {
+			content.WriteString(selectedStyle.Render("› " + line))
+		} else {
+			content.WriteString(HelpDescStyle.Render("  " + line))
+		}
+		content.WriteString("\n")
+	}
+	if len(p.Blocks) > 0 {
+		content.WriteString(SessionInfoStyle.UnsetMargins().Render(PlanTotals(p)))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	content.WriteString(input)
+	content.WriteString("\n")
+	if message != "" {
+		content.WriteString(WarningStyle.Render(message))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(HelpStyle.Render(hint))
+
+	return HelpOverlayStyle.Render(content.String())
+}
+
+// RenderPlanSummary renders the finished plan as planned versus actual
+func RenderPlanSummary(p *plan.Plan) string {
+	var content strings.Builder
+	content.WriteString(SessionInfoStyle.UnsetMargins().Render("▤ Planned vs actual"))
+	content.WriteString("\n")
+	for _, b := range p.Blocks {
+		content.WriteString(HelpDescStyle.Render(PlanLine(b)))
+		content.WriteString("\n")
+	}
+	content.WriteString(SessionInfoStyle.UnsetMargins().Render(PlanTotals(p)))
+	return content.String()
+}
+
+// RenderPastPlan renders the planned versus actual summary of a day that
+// is over, whether or not every block was finished
+func RenderPastPlan(p *plan.Plan, width, height int) string {
+	day := p.Date
+	if date, err := time.Parse("2006-01-02", p.Date); err == nil {
+		day = date.Format("Monday 2 January")
+	}
+	content := HelpDescStyle.Render("The day is over: "+day) + "\n\n" +
+		RenderPlanSummary(p) + "\n\n" +
+		HelpDescStyle.Render("Press any key to continue")
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content)
+}
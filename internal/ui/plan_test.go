@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/plan"
+	"github.com/stretchr/testify/assert"
+)
+
+var planDay = time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+
+func testPlan() *plan.Plan {
+	p := plan.New(planDay)
+	p.Blocks = []plan.Block{
+		{Task: "API refactor", Count: 3, Done: 3, Started: planDay.Add(9*time.Hour + 5*time.Minute)},
+		{Break: true},
+		{Task: "Reviews", Count: 2, Start: "14:00"},
+	}
+	return p
+}
+
+func TestPlanLine(t *testing.T) {
+	p := testPlan()
+
+	assert.Regexp(t, `^  ·\s+API refactor\s+3/3  started 09:05$`, PlanLine(p.Blocks[0]))
+	assert.Regexp(t, `^  ·\s+Long break\s+–$`, PlanLine(p.Blocks[1]))
+	assert.Regexp(t, `^14:00  Reviews\s+0/2$`, PlanLine(p.Blocks[2]))
+
+	p.Blocks[1].Done = 1
+	assert.Regexp(t, `Long break\s+✓$`, PlanLine(p.Blocks[1]))
+}
+
+func TestPlanLine_TruncatesLongTasks(t *testing.T) {
+	line := PlanLine(plan.Block{Task: "Untangle the configuration loader once and for all", Count: 1})
+
+	assert.Contains(t, line, "Untangle the configurat…")
+	assert.NotContains(t, line, "for all")
+}
+
+func TestRenderPlan(t *testing.T) {
+	result := RenderPlan(testPlan(), planDay.Add(11*time.Hour), "> 2 docs", 2, "", "ENTER to add")
+
+	assert.Contains(t, result, "▤ Day Plan")
+	assert.Contains(t, result, "✓ ")
+	assert.Contains(t, result, "▶ ")
+	assert.Contains(t, result, "› ")
+	assert.Contains(t, result, "3 of 5 planned pomodoros done")
+	assert.Contains(t, result, "> 2 docs")
+	assert.Contains(t, result, "ENTER to add")
+}
+
+func TestRenderPlan_Empty(t *testing.T) {
+	result := RenderPlan(plan.New(planDay), planDay, "", 0, "no task", "")
+
+	assert.Contains(t, result, "Nothing planned yet")
+	assert.NotContains(t, result, "planned pomodoros")
+	assert.Contains(t, result, "no task")
+}
+
+func TestRenderPlanSummary(t *testing.T) {
+	result := RenderPlanSummary(testPlan())
+
+	assert.Contains(t, result, "Planned vs actual")
+	assert.Contains(t, result, "API refactor")
+	assert.Contains(t, result, "Reviews")
+	assert.Contains(t, result, "3 of 5 planned pomodoros done")
+}
+
+func TestRenderPastPlan(t *testing.T) {
+	result := RenderPastPlan(testPlan(), 80, 24)
+
+	assert.Contains(t, result, "The day is over: Monday 2 March")
+	assert.Contains(t, result, "3 of 5 planned pomodoros done")
+	assert.Contains(t, result, "Press any key")
+}
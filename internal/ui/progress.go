@@ -9,6 +9,11 @@ import (
 
 // RenderProgressBar creates a styled progress bar
 func RenderProgressBar(percent float64, width int) string {
+	return RenderProgressBarColor(percent, width, ProgressColor)
+}
+
+// RenderProgressBarColor creates a progress bar filled in the given color
+func RenderProgressBarColor(percent float64, width int, color lipgloss.Color) string {
 	// Ensure percent is between 0 and 1
 	if percent < 0 {
 		percent = 0
@@ -31,7 +36,7 @@ func RenderProgressBar(percent float64, width int) string {
 	emptyBar := strings.Repeat("░", empty)
 
 	// Style the parts
-	filledStyle := lipgloss.NewStyle().Foreground(Magenta)
+	filledStyle := lipgloss.NewStyle().Foreground(color)
 	emptyStyle := lipgloss.NewStyle().Foreground(DarkGray)
 
 	bar := filledStyle.Render(filledBar) + emptyStyle.Render(emptyBar)
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Cyberpunk color palette
 var (
@@ -110,8 +114,90 @@ var (
 
 	HelpDescStyle = lipgloss.NewStyle().
 		Foreground(LightGray)
+
+	// Toast style for short status messages
+	ToastStyle = lipgloss.NewStyle().
+		Foreground(Yellow).
+		MarginTop(1)
+
+	// Warning banner style for problems that need attention
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(DarkBg).
+		Background(Yellow).
+		Bold(true).
+		Padding(0, 1)
 )
 
+// Theme is a set of colors for the session types and highlights
+type Theme struct {
+	Work       lipgloss.Color
+	ShortBreak lipgloss.Color
+	LongBreak  lipgloss.Color
+	Accent     lipgloss.Color // Titles, borders and session info
+	Progress   lipgloss.Color // Filled part of the progress bar
+	Warning    lipgloss.Color // Progress bar pulse as a session is about to end
+}
+
+// Themes lists the built-in themes by name
+var Themes = map[string]Theme{
+	"neon": {
+		Work:       HotPink,
+		ShortBreak: Cyan,
+		LongBreak:  Purple,
+		Accent:     Cyan,
+		Progress:   Magenta,
+		Warning:    Yellow,
+	},
+	"mono": {
+		Work:       lipgloss.Color("#FFFFFF"),
+		ShortBreak: lipgloss.Color("#BBBBBB"),
+		LongBreak:  lipgloss.Color("#888888"),
+		Accent:     lipgloss.Color("#DDDDDD"),
+		Progress:   lipgloss.Color("#AAAAAA"),
+		Warning:    lipgloss.Color("#FFFFFF"),
+	},
+	"solarized": {
+		Work:       lipgloss.Color("#DC322F"),
+		ShortBreak: lipgloss.Color("#2AA198"),
+		LongBreak:  lipgloss.Color("#6C71C4"),
+		Accent:     lipgloss.Color("#268BD2"),
+		Progress:   lipgloss.Color("#B58900"),
+		Warning:    lipgloss.Color("#CB4B16"),
+	},
+}
+
+// ProgressColor is the color of the filled part of the progress bar
+var ProgressColor = Magenta
+
+// ProgressWarningColor alternates with ProgressColor while a session is
+// about to end
+var ProgressWarningColor = Yellow
+
+// ApplyTheme switches the session colors and styles to a built-in theme
+// An empty name selects the default neon theme
+func ApplyTheme(name string) error {
+	if name == "" {
+		name = "neon"
+	}
+	theme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+
+	WorkColor = theme.Work
+	ShortBreakColor = theme.ShortBreak
+	LongBreakColor = theme.LongBreak
+	ProgressColor = theme.Progress
+	ProgressWarningColor = theme.Warning
+
+	TitleStyle = TitleStyle.Foreground(theme.Accent)
+	SessionInfoStyle = SessionInfoStyle.Foreground(theme.Accent)
+	CompletionStyle = CompletionStyle.BorderForeground(theme.Accent)
+	HelpOverlayStyle = HelpOverlayStyle.BorderForeground(theme.Accent)
+	HelpKeyStyle = HelpKeyStyle.Foreground(theme.Work)
+	return nil
+}
+
 // GetSessionColor returns the appropriate color for a session type
 func GetSessionColor(sessionType string) lipgloss.Color {
 	switch sessionType {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetSessionColor(t *testing.T) {
@@ -119,3 +120,23 @@ func TestShortBreakColorIsCyan(t *testing.T) {
 func TestLongBreakColorIsPurple(t *testing.T) {
 	assert.Equal(t, Purple, LongBreakColor)
 }
+
+func TestApplyTheme(t *testing.T) {
+	defer ApplyTheme("neon")
+
+	require.NoError(t, ApplyTheme("solarized"))
+	assert.Equal(t, Themes["solarized"].Work, GetSessionColor("work"))
+	assert.Equal(t, Themes["solarized"].LongBreak, GetSessionColor("long_break"))
+	assert.Equal(t, Themes["solarized"].Progress, ProgressColor)
+	assert.Equal(t, Themes["solarized"].Warning, ProgressWarningColor)
+
+	require.NoError(t, ApplyTheme(""))
+	assert.Equal(t, HotPink, GetSessionColor("work"), "empty name selects the default theme")
+}
+
+func TestApplyTheme_Unknown(t *testing.T) {
+	before := WorkColor
+
+	assert.Error(t, ApplyTheme("vaporwave"))
+	assert.Equal(t, before, WorkColor, "unknown theme should change nothing")
+}
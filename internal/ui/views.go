@@ -49,18 +49,74 @@ func RenderSplash(frame int, width, height int) string {
 	)
 }
 
+// TimerOptions carries optional extras shown in the timer view
+type TimerOptions struct {
+	DailyGoal  GoalProgress
+	WeeklyGoal GoalProgress
+	Project    string
+	Tags       []string
+	Task       string
+	Toast      string
+	Warning    string // Banner shown above the timer, e.g. for config errors
+	Profile    string
+	EndingSoon bool   // In the pre-end warning window, pulses the progress bar
+	Quiet      string // Why notifications are muted, e.g. "Do not disturb"
+	Meeting    string // The next meeting, e.g. "Standup at 14:00 · in 35m"
+	Conflict   bool   // The meeting starts before the session ends
+	Plan       string // Where the day plan stands, e.g. "Block 1 of 3 · 2/3 · Next: Long break"
+}
+
 // RenderTimer renders the main timer view
 func RenderTimer(t *timer.Timer, width, height int, paused bool) string {
+	return RenderTimerWithOptions(t, width, height, paused, TimerOptions{})
+}
+
+// RenderTimerWithOptions renders the main timer view with optional extras
+func RenderTimerWithOptions(t *timer.Timer, width, height int, paused bool, opts TimerOptions) string {
 	// Get session-appropriate color
 	sessionColor := GetSessionColor(string(t.SessionType))
 	timerStyle := lipgloss.NewStyle().Foreground(sessionColor).Bold(true)
 
 	var content strings.Builder
 
+	if opts.Warning != "" {
+		content.WriteString(WarningStyle.Render("⚠ " + opts.Warning))
+		content.WriteString("\n\n")
+	}
+
 	// Session title
 	titleStyle := lipgloss.NewStyle().Foreground(sessionColor).Bold(true)
 	content.WriteString(titleStyle.Render(t.SessionName()))
-	content.WriteString("\n\n")
+	content.WriteString("\n")
+	if opts.Profile != "" {
+		content.WriteString(HelpDescStyle.Render("◆ " + opts.Profile))
+		content.WriteString("\n")
+	}
+	if opts.Quiet != "" {
+		content.WriteString(HelpDescStyle.Render("☾ " + opts.Quiet))
+		content.WriteString("\n")
+	}
+	if opts.Meeting != "" {
+		style := HelpDescStyle
+		if opts.Conflict {
+			style = WarningStyle
+		}
+		content.WriteString(style.Render("📅 " + opts.Meeting))
+		content.WriteString("\n")
+	}
+	if opts.Plan != "" {
+		content.WriteString(HelpDescStyle.Render("▤ " + opts.Plan))
+		content.WriteString("\n")
+	}
+	if opts.Task != "" {
+		content.WriteString(SessionInfoStyle.UnsetMargins().Render("▸ " + opts.Task))
+		content.WriteString("\n")
+	}
+	if labels := RenderLabels(opts.Project, opts.Tags); labels != "" {
+		content.WriteString(labels)
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
 
 	// ASCII time display
 	content.WriteString(RenderTime(t.MinutesRemaining(), t.SecondsRemaining(), timerStyle))
@@ -71,20 +127,20 @@ func RenderTimer(t *timer.Timer, width, height int, paused bool) string {
 	if width < 60 {
 		progressWidth = width - 10
 	}
-	content.WriteString(RenderProgressBar(t.Progress(), progressWidth))
+	content.WriteString(RenderProgressBarColor(t.Progress(), progressWidth, progressColor(t, opts.EndingSoon)))
 	content.WriteString("\n\n")
 
 	// Session counter
 	pomodoroCount := t.PomodoroCount
 	if t.SessionType == timer.LongBreak {
-		pomodoroCount = timer.PomodorosBeforeLongBreak
+		pomodoroCount = t.Cycle()
 	}
 	if pomodoroCount < 0 {
 		pomodoroCount = 0
 	}
-	sessionInfo := fmt.Sprintf("Pomodoro %d/%d", pomodoroCount, timer.PomodorosBeforeLongBreak)
+	sessionInfo := fmt.Sprintf("Pomodoro %d/%d", pomodoroCount, t.Cycle())
 	if t.SessionType == timer.Work {
-		if t.PomodoroCount >= timer.PomodorosBeforeLongBreak {
+		if t.PomodoroCount >= t.Cycle() {
 			sessionInfo += " • Long break next!"
 		} else {
 			sessionInfo += " • Short break next"
@@ -92,6 +148,9 @@ func RenderTimer(t *timer.Timer, width, height int, paused bool) string {
 	} else {
 		sessionInfo += " • Work session next"
 	}
+	if goals := RenderGoals(opts.DailyGoal, opts.WeeklyGoal); goals != "" {
+		sessionInfo += " • " + goals
+	}
 	content.WriteString(SessionInfoStyle.Render(sessionInfo))
 	content.WriteString("\n")
 
@@ -106,6 +165,11 @@ func RenderTimer(t *timer.Timer, width, height int, paused bool) string {
 	// Help hint
 	content.WriteString(HelpStyle.Render("Press ? for help • q to quit"))
 
+	if opts.Toast != "" {
+		content.WriteString("\n")
+		content.WriteString(ToastStyle.Render(opts.Toast))
+	}
+
 	// Center the content
 	return lipgloss.Place(
 		width, height,
@@ -114,6 +178,15 @@ func RenderTimer(t *timer.Timer, width, height int, paused bool) string {
 	)
 }
 
+// progressColor pulses the progress bar once a second while the session is
+// ending soon
+func progressColor(t *timer.Timer, endingSoon bool) lipgloss.Color {
+	if endingSoon && t.SecondsRemaining()%2 == 0 {
+		return ProgressWarningColor
+	}
+	return ProgressColor
+}
+
 // RenderComplete renders the session complete view
 func RenderComplete(completedSession timer.SessionType, nextSession timer.SessionType) string {
 	var content strings.Builder
@@ -166,3 +239,57 @@ func RenderFlash(width, height int) string {
 		flashMsg,
 	)
 }
+
+// RenderReflection renders the optional note and focus prompt after a session
+func RenderReflection(completedSession timer.SessionType, input string, focus int) string {
+	var content strings.Builder
+
+	completeMsg := "🎉 Work session complete!"
+	if completedSession != timer.Work {
+		completeMsg = "☕ Session complete!"
+	}
+	content.WriteString(CompletionStyle.Render(completeMsg))
+	content.WriteString("\n\n")
+
+	content.WriteString(SessionInfoStyle.Render("What did you get done?"))
+	content.WriteString("\n")
+	content.WriteString(input)
+	content.WriteString("\n\n")
+
+	content.WriteString(SessionInfoStyle.Render("Focus " + RenderFocusRating(focus)))
+	content.WriteString("\n\n")
+
+	content.WriteString(HelpStyle.Render("↑/↓ rate focus • ENTER to save • ESC to skip"))
+
+	return content.String()
+}
+
+// RenderFocusRating renders a 1-5 rating as stars, or a dash when unrated
+func RenderFocusRating(focus int) string {
+	if focus <= 0 {
+		return "–"
+	}
+	if focus > 5 {
+		focus = 5
+	}
+	return strings.Repeat("★", focus) + strings.Repeat("☆", 5-focus)
+}
+
+// RenderLabels renders a session's project and tags, e.g. "api · #backend #q2"
+func RenderLabels(project string, tags []string) string {
+	var parts []string
+	if project != "" {
+		parts = append(parts, project)
+	}
+	if len(tags) > 0 {
+		hashed := make([]string, len(tags))
+		for i, tag := range tags {
+			hashed[i] = "#" + tag
+		}
+		parts = append(parts, strings.Join(hashed, " "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return HelpDescStyle.Render(strings.Join(parts, " · "))
+}
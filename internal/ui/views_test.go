@@ -2,6 +2,7 @@ package ui
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kanishkathakur1/pomodoro/internal/timer"
 	"github.com/stretchr/testify/assert"
@@ -228,3 +229,94 @@ func TestRenderFlash_DifferentSizes(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderReflection(t *testing.T) {
+	result := RenderReflection(timer.Work, "> notes", 3)
+
+	assert.Contains(t, result, "Work session complete!")
+	assert.Contains(t, result, "> notes")
+	assert.Contains(t, result, "★★★☆☆")
+	assert.Contains(t, result, "ESC to skip")
+}
+
+func TestRenderFocusRating(t *testing.T) {
+	tests := []struct {
+		name     string
+		focus    int
+		expected string
+	}{
+		{"unrated", 0, "–"},
+		{"one", 1, "★☆☆☆☆"},
+		{"five", 5, "★★★★★"},
+		{"clamped", 9, "★★★★★"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RenderFocusRating(tt.focus))
+		})
+	}
+}
+
+func TestRenderTimerWithOptions_ShowsGoals(t *testing.T) {
+	tmr := timer.New()
+	opts := TimerOptions{DailyGoal: GoalProgress{Pomodoros: 2, PomodoroTarget: 8}}
+
+	result := RenderTimerWithOptions(tmr, 120, 40, true, opts)
+
+	assert.Contains(t, result, "Pomodoro 0/4")
+	assert.Contains(t, result, "Today 2/8")
+}
+
+func TestRenderLabels(t *testing.T) {
+	assert.Contains(t, RenderLabels("api", []string{"backend", "q2"}), "api · #backend #q2")
+	assert.Contains(t, RenderLabels("", []string{"q2"}), "#q2")
+	assert.Empty(t, RenderLabels("", nil))
+}
+
+func TestRenderTimerWithOptions_ShowsLabels(t *testing.T) {
+	result := RenderTimerWithOptions(timer.New(), 120, 40, true, TimerOptions{Project: "api", Tags: []string{"backend"}})
+
+	assert.Contains(t, result, "api · #backend")
+}
+
+func TestRenderTimerWithOptions_ShowsTaskAndToast(t *testing.T) {
+	result := RenderTimerWithOptions(timer.New(), 120, 40, true, TimerOptions{Task: "Write docs", Toast: "Saved"})
+
+	assert.Contains(t, result, "▸ Write docs")
+	assert.Contains(t, result, "Saved")
+}
+
+func TestRenderTimer_ShowsQuietIndicator(t *testing.T) {
+	result := RenderTimerWithOptions(timer.New(), 120, 40, true, TimerOptions{Quiet: "Do not disturb"})
+	assert.Contains(t, result, "☾ Do not disturb")
+
+	assert.NotContains(t, RenderTimer(timer.New(), 120, 40, true), "☾")
+}
+
+func TestRenderTimer_ShowsMeeting(t *testing.T) {
+	result := RenderTimerWithOptions(timer.New(), 120, 40, true, TimerOptions{Meeting: "Standup at 14:00 · in 35m"})
+	assert.Contains(t, result, "📅 Standup at 14:00 · in 35m")
+
+	result = RenderTimerWithOptions(timer.New(), 120, 40, true, TimerOptions{Meeting: "Standup at 14:00 · in 5m", Conflict: true})
+	assert.Contains(t, result, "📅 Standup at 14:00 · in 5m")
+
+	assert.NotContains(t, RenderTimer(timer.New(), 120, 40, true), "📅")
+}
+
+func TestRenderTimer_ShowsPlan(t *testing.T) {
+	result := RenderTimerWithOptions(timer.New(), 120, 40, true, TimerOptions{Plan: "Block 1 of 3 · 1/3 · Next: Long break"})
+	assert.Contains(t, result, "▤ Block 1 of 3 · 1/3 · Next: Long break")
+
+	assert.NotContains(t, RenderTimer(timer.New(), 120, 40, true), "▤")
+}
+
+func TestProgressColor_PulsesWhenEndingSoon(t *testing.T) {
+	tm := timer.New()
+	tm.Remaining = 58 * time.Second
+
+	assert.Equal(t, ProgressColor, progressColor(tm, false))
+	assert.Equal(t, ProgressWarningColor, progressColor(tm, true))
+	tm.Remaining -= time.Second
+	assert.Equal(t, ProgressColor, progressColor(tm, true))
+}
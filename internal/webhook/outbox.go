@@ -0,0 +1,171 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Retry pauses start at retryBase and double after each failed attempt, up
+// to retryMax
+const (
+	retryBase = 30 * time.Second
+	retryMax  = time.Hour
+)
+
+// Outbox keeps failed deliveries on disk, one JSON file each, until they
+// are accepted
+type Outbox struct {
+	dir      string
+	flushing sync.Mutex
+}
+
+// NewOutbox creates an outbox in dir, which is made on first use
+func NewOutbox(dir string) *Outbox {
+	return &Outbox{dir: dir}
+}
+
+// OpenOutbox returns the outbox next to the config file
+func OpenOutbox() (*Outbox, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewOutbox(filepath.Join(configDir, "pomodoro", "outbox")), nil
+}
+
+// Dir returns the directory backing the outbox
+func (o *Outbox) Dir() string {
+	return o.dir
+}
+
+// Add stores a delivery that failed with err and schedules its retry
+func (o *Outbox) Add(d *Delivery, err error, now time.Time) error {
+	d.Attempts++
+	d.LastErr = err.Error()
+	d.Next = now.Add(retryDelay(d.Attempts))
+	return o.write(d)
+}
+
+// Hold stores a delivery before its first attempt, so that a crash while
+// it is being sent doesn't lose it; Flush leaves it alone until the attempt
+// has had time to finish
+func (o *Outbox) Hold(d *Delivery, now time.Time) error {
+	d.Next = now.Add(d.Timeout + retryBase)
+	return o.write(d)
+}
+
+// Remove deletes a delivery that was accepted
+func (o *Outbox) Remove(d *Delivery) error {
+	if err := os.Remove(o.path(d)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Pending lists the stored deliveries, oldest first
+func (o *Outbox) Pending() ([]*Delivery, error) {
+	entries, err := os.ReadDir(o.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Delivery
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(o.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var d Delivery
+		if err := json.Unmarshal(data, &d); err != nil {
+			// Left in place for a person to look at
+			continue
+		}
+		pending = append(pending, &d)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+	return pending, nil
+}
+
+// Flush retries the deliveries that are due, removing those accepted and
+// rescheduling the rest, and returns how many are still waiting
+// A flush already under way makes this one do nothing
+func (o *Outbox) Flush(client *http.Client, now time.Time) (int, error) {
+	if !o.flushing.TryLock() {
+		return 0, nil
+	}
+	defer o.flushing.Unlock()
+
+	pending, err := o.Pending()
+	if err != nil {
+		return 0, err
+	}
+	waiting := 0
+	for _, d := range pending {
+		if now.Before(d.Next) {
+			waiting++
+			continue
+		}
+		if sendErr := d.Send(client); sendErr != nil {
+			waiting++
+			if err := o.Add(d, sendErr, now); err != nil {
+				return waiting, err
+			}
+			continue
+		}
+		if err := o.Remove(d); err != nil {
+			return waiting, err
+		}
+	}
+	return waiting, nil
+}
+
+// write saves d atomically, so a crash never leaves half a file
+func (o *Outbox) write(d *Delivery) error {
+	if err := os.MkdirAll(o.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(o.dir, ".delivery-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), o.path(d))
+}
+
+// path returns the file holding d
+func (o *Outbox) path(d *Delivery) string {
+	return filepath.Join(o.dir, d.ID+".json")
+}
+
+// retryDelay returns the pause after the given number of attempts
+func retryDelay(attempts int) time.Duration {
+	delay := retryBase
+	for i := 1; i < attempts && delay < retryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMax)
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox_AddAndPending(t *testing.T) {
+	o := NewOutbox(filepath.Join(t.TempDir(), "outbox"))
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+
+	pending, err := o.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, pending, "a missing directory is an empty outbox")
+
+	require.NoError(t, o.Add(&Delivery{ID: "b", URL: "https://example.test", Body: []byte("2")}, errors.New("down"), now))
+	require.NoError(t, o.Add(&Delivery{ID: "a", URL: "https://example.test", Body: []byte("1")}, errors.New("down"), now))
+
+	pending, err = o.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "a", pending[0].ID)
+	assert.Equal(t, []byte("1"), pending[0].Body)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "down", pending[0].LastErr)
+	assert.Equal(t, now.Add(retryBase), pending[0].Next)
+}
+
+func TestOutbox_Flush(t *testing.T) {
+	var up atomic.Bool
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	o := NewOutbox(t.TempDir())
+	now := time.Now()
+	require.NoError(t, o.Add(&Delivery{ID: "1", URL: srv.URL, Timeout: time.Second}, errors.New("down"), now))
+
+	waiting, err := o.Flush(http.DefaultClient, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, waiting)
+	assert.Zero(t, calls.Load(), "not due yet")
+
+	later := now.Add(retryBase)
+	waiting, err = o.Flush(http.DefaultClient, later)
+	require.NoError(t, err)
+	assert.Equal(t, 1, waiting)
+	pending, _ := o.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, 2, pending[0].Attempts)
+	assert.True(t, later.Add(2*retryBase).Equal(pending[0].Next))
+
+	up.Store(true)
+	waiting, err = o.Flush(http.DefaultClient, later.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, waiting)
+	pending, _ = o.Pending()
+	assert.Empty(t, pending)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestOutbox_HoldAndRemove(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	o := NewOutbox(t.TempDir())
+	now := time.Now()
+	d := &Delivery{ID: "1", URL: srv.URL, Timeout: time.Second}
+	require.NoError(t, o.Hold(d, now))
+
+	pending, err := o.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Zero(t, pending[0].Attempts)
+
+	_, err = o.Flush(http.DefaultClient, now)
+	require.NoError(t, err)
+	assert.Zero(t, calls.Load(), "left to the attempt under way")
+
+	require.NoError(t, o.Remove(d))
+	require.NoError(t, o.Remove(d), "already gone")
+	pending, _ = o.Pending()
+	assert.Empty(t, pending)
+}
+
+func TestOutbox_SkipsUnreadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600))
+	o := NewOutbox(dir)
+	require.NoError(t, o.Add(&Delivery{ID: "1"}, errors.New("down"), time.Now()))
+
+	pending, err := o.Pending()
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, retryBase, retryDelay(1))
+	assert.Equal(t, 4*retryBase, retryDelay(3))
+	assert.Equal(t, retryMax, retryDelay(20))
+}
@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+)
+
+// DefaultTimeout limits a delivery when the target sets none
+const DefaultTimeout = 10 * time.Second
+
+// SignatureHeader carries the HMAC-SHA256 of the body, as "sha256=<hex>",
+// for targets with a secret
+const SignatureHeader = "X-Pomodoro-Signature"
+
+// Event is a session event as seen by webhook templates
+type Event struct {
+	Event     string        `json:"event"` // session_start, session_end or reminder
+	Time      time.Time     `json:"time"`
+	Session   string        `json:"session"` // work, short_break or long_break
+	Profile   string        `json:"profile,omitempty"`
+	Project   string        `json:"project,omitempty"`
+	Tags      []string      `json:"tags,omitempty"`
+	Task      string        `json:"task,omitempty"`
+	Completed bool          `json:"completed"`
+	Elapsed   time.Duration `json:"-"` // Sent as elapsed_seconds
+	Reminder  int           `json:"reminder,omitempty"`
+	Stats     Stats         `json:"stats"`
+}
+
+// MarshalJSON sends the elapsed time in whole seconds
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	return json.Marshal(struct {
+		event
+		ElapsedSeconds int64 `json:"elapsed_seconds"`
+	}{event(e), seconds(e.Elapsed)})
+}
+
+// UnmarshalJSON reads an event sent by MarshalJSON
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type event Event
+	var v struct {
+		event
+		ElapsedSeconds int64 `json:"elapsed_seconds"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = Event(v.event)
+	e.Elapsed = time.Duration(v.ElapsedSeconds) * time.Second
+	return nil
+}
+
+// Stats are the focus totals when the event happened
+type Stats struct {
+	TodayPomodoros int           `json:"today_pomodoros"`
+	TodayFocus     time.Duration `json:"-"` // Sent as today_focus_seconds
+	WeekPomodoros  int           `json:"week_pomodoros"`
+	WeekFocus      time.Duration `json:"-"` // Sent as week_focus_seconds
+}
+
+// MarshalJSON sends the focus totals in whole seconds
+func (s Stats) MarshalJSON() ([]byte, error) {
+	type stats Stats
+	return json.Marshal(struct {
+		stats
+		TodayFocusSeconds int64 `json:"today_focus_seconds"`
+		WeekFocusSeconds  int64 `json:"week_focus_seconds"`
+	}{stats(s), seconds(s.TodayFocus), seconds(s.WeekFocus)})
+}
+
+// UnmarshalJSON reads totals sent by MarshalJSON
+func (s *Stats) UnmarshalJSON(data []byte) error {
+	type stats Stats
+	var v struct {
+		stats
+		TodayFocusSeconds int64 `json:"today_focus_seconds"`
+		WeekFocusSeconds  int64 `json:"week_focus_seconds"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Stats(v.stats)
+	s.TodayFocus = time.Duration(v.TodayFocusSeconds) * time.Second
+	s.WeekFocus = time.Duration(v.WeekFocusSeconds) * time.Second
+	return nil
+}
+
+// seconds returns d in whole seconds
+func seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+// funcs are available in body templates
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"minutes": func(d time.Duration) int {
+		return int(d.Minutes())
+	},
+}
+
+// Target is a configured webhook ready to render events
+type Target struct {
+	URL     string
+	Events  []string
+	Headers map[string]string
+	Secret  string
+	Timeout time.Duration
+	body    *template.Template // nil sends the event as JSON
+}
+
+// NewTarget parses a webhook's settings
+func NewTarget(cfg config.WebhookConfig) (*Target, error) {
+	t := &Target{
+		URL:     cfg.URL,
+		Events:  cfg.Events,
+		Headers: cfg.Headers,
+		Secret:  cfg.Secret,
+		Timeout: cfg.Timeout,
+	}
+	if strings.TrimSpace(cfg.Body) != "" {
+		body, err := template.New(cfg.URL).Funcs(funcs).Option("missingkey=error").Parse(cfg.Body)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: %w", cfg.URL, err)
+		}
+		t.body = body
+	}
+	return t, nil
+}
+
+// Wants returns whether the target subscribes to event, every event if
+// it lists none
+func (t *Target) Wants(event string) bool {
+	return len(t.Events) == 0 || slices.Contains(t.Events, event)
+}
+
+// Delivery renders ev into the request for this target
+func (t *Target) Delivery(ev Event) (*Delivery, error) {
+	var body []byte
+	if t.body == nil {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	} else {
+		var buf bytes.Buffer
+		if err := t.body.Execute(&buf, ev); err != nil {
+			return nil, fmt.Errorf("webhook %s: %w", t.URL, err)
+		}
+		body = buf.Bytes()
+	}
+
+	headers := map[string]string{
+		"Content-Type":     "application/json",
+		"X-Pomodoro-Event": ev.Event,
+	}
+	for name, value := range t.Headers {
+		headers[name] = value
+	}
+	if t.Secret != "" {
+		headers[SignatureHeader] = Sign(t.Secret, body)
+	}
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Delivery{
+		ID:      newID(ev.Time),
+		URL:     t.URL,
+		Headers: headers,
+		Body:    body,
+		Timeout: timeout,
+		Created: ev.Time,
+	}, nil
+}
+
+// Sign returns the signature header value for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Delivery is a rendered webhook request, kept in the outbox until the
+// server accepts it
+type Delivery struct {
+	ID       string            `json:"id"`
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers"`
+	Body     []byte            `json:"body"`
+	Timeout  time.Duration     `json:"timeout"`
+	Created  time.Time         `json:"created"`
+	Attempts int               `json:"attempts"`
+	Next     time.Time         `json:"next"`       // Earliest retry
+	LastErr  string            `json:"last_error"` // From the latest attempt
+}
+
+// Send posts the delivery
+// Any response other than 2xx is an error
+func (d *Delivery) Send(client *http.Client) error {
+	req, err := http.NewRequest(http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return err
+	}
+	for name, value := range d.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("X-Pomodoro-Delivery", d.ID)
+
+	c := *client
+	c.Timeout = d.Timeout
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", d.URL, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s: %s", d.URL, resp.Status)
+	}
+	return nil
+}
+
+// newID returns a unique, time-ordered delivery ID
+func newID(t time.Time) string {
+	var random [4]byte
+	_, _ = rand.Read(random[:])
+	return fmt.Sprintf("%s-%s", t.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(random[:]))
+}
@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kanishkathakur1/pomodoro/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEvent = Event{
+	Event:     "session_end",
+	Time:      time.Date(2024, 6, 3, 10, 25, 0, 0, time.UTC),
+	Session:   "work",
+	Project:   "api",
+	Tags:      []string{"backend"},
+	Task:      "Write docs",
+	Completed: true,
+	Elapsed:   25 * time.Minute,
+	Stats:     Stats{TodayPomodoros: 3, TodayFocus: 75 * time.Minute},
+}
+
+func TestTarget_DefaultBodyIsJSON(t *testing.T) {
+	target, err := NewTarget(config.WebhookConfig{URL: "https://example.test/hook"})
+	require.NoError(t, err)
+
+	d, err := target.Delivery(testEvent)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(d.Body, &got))
+	assert.Equal(t, "session_end", got["event"])
+	assert.Equal(t, "2024-06-03T10:25:00Z", got["time"])
+	assert.Equal(t, "Write docs", got["task"])
+	assert.Equal(t, []any{"backend"}, got["tags"])
+	assert.Equal(t, true, got["completed"])
+	assert.Equal(t, 1500.0, got["elapsed_seconds"], "durations are sent in seconds")
+	assert.NotContains(t, got, "elapsed")
+	assert.Equal(t, map[string]any{
+		"today_pomodoros":     3.0,
+		"today_focus_seconds": 4500.0,
+		"week_pomodoros":      0.0,
+		"week_focus_seconds":  0.0,
+	}, got["stats"])
+
+	var event Event
+	require.NoError(t, json.Unmarshal(d.Body, &event))
+	assert.Equal(t, testEvent, event)
+	assert.Equal(t, "application/json", d.Headers["Content-Type"])
+	assert.Equal(t, "session_end", d.Headers["X-Pomodoro-Event"])
+	assert.Equal(t, DefaultTimeout, d.Timeout)
+}
+
+func TestTarget_Template(t *testing.T) {
+	target, err := NewTarget(config.WebhookConfig{
+		URL:     "https://example.test/hook",
+		Body:    `{"text": {{json (printf "%s done: %s" .Session .Task)}}, "minutes": {{minutes .Elapsed}}, "today": {{.Stats.TodayPomodoros}}}`,
+		Headers: map[string]string{"Authorization": "Bearer abc", "Content-Type": "text/plain"},
+		Timeout: 3 * time.Second,
+	})
+	require.NoError(t, err)
+
+	d, err := target.Delivery(testEvent)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"text": "work done: Write docs", "minutes": 25, "today": 3}`, string(d.Body))
+	assert.Equal(t, "Bearer abc", d.Headers["Authorization"])
+	assert.Equal(t, "text/plain", d.Headers["Content-Type"], "configured headers win")
+	assert.Equal(t, 3*time.Second, d.Timeout)
+}
+
+func TestTarget_TemplateErrors(t *testing.T) {
+	_, err := NewTarget(config.WebhookConfig{URL: "https://example.test", Body: "{{.Session"})
+	assert.Error(t, err)
+
+	target, err := NewTarget(config.WebhookConfig{URL: "https://example.test", Body: "{{.Nope}}"})
+	require.NoError(t, err)
+	_, err = target.Delivery(testEvent)
+	assert.Error(t, err)
+}
+
+func TestTarget_Wants(t *testing.T) {
+	all, _ := NewTarget(config.WebhookConfig{URL: "https://example.test"})
+	ends, _ := NewTarget(config.WebhookConfig{URL: "https://example.test", Events: []string{"session_end"}})
+
+	assert.True(t, all.Wants("session_start"))
+	assert.True(t, ends.Wants("session_end"))
+	assert.False(t, ends.Wants("session_start"))
+}
+
+func TestTarget_Signs(t *testing.T) {
+	target, _ := NewTarget(config.WebhookConfig{URL: "https://example.test", Body: "hello", Secret: "key"})
+
+	d, err := target.Delivery(testEvent)
+	require.NoError(t, err)
+
+	// echo -n hello | openssl dgst -sha256 -hmac key
+	assert.Equal(t, "sha256=9307b3b915efb5171ff14d8cb55fbcc798c6c0ef1456d66ded1a6aa723a58b7b", d.Headers[SignatureHeader])
+}
+
+func TestDelivery_Send(t *testing.T) {
+	var got *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got, body = r, string(data)
+	}))
+	defer srv.Close()
+
+	d := &Delivery{ID: "1", URL: srv.URL, Headers: map[string]string{"X-Test": "yes"}, Body: []byte("hi"), Timeout: time.Second}
+	require.NoError(t, d.Send(http.DefaultClient))
+
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "hi", body)
+	assert.Equal(t, "yes", got.Header.Get("X-Test"))
+	assert.Equal(t, "1", got.Header.Get("X-Pomodoro-Delivery"))
+}
+
+func TestDelivery_SendFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	d := &Delivery{URL: srv.URL, Timeout: time.Second}
+	assert.ErrorContains(t, d.Send(http.DefaultClient), "500 Internal Server Error")
+
+	d.Timeout = 10 * time.Millisecond
+	assert.ErrorContains(t, d.Send(http.DefaultClient), "Timeout")
+}
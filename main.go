@@ -1,17 +1,33 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kanishkathakur1/pomodoro/internal/app"
+	"github.com/kanishkathakur1/pomodoro/internal/cli"
+	"github.com/kanishkathakur1/pomodoro/internal/notify"
 )
 
 func main() {
+	args, err := cli.Configure(os.Args[1:], os.Environ(), os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+	if len(args) > 0 {
+		os.Exit(cli.Run(args, os.Stdout, os.Stderr))
+	}
+
 	p := tea.NewProgram(
 		app.New(),
 		tea.WithAltScreen(),
+		tea.WithOutput(notify.Output),
 		tea.WithMouseCellMotion(),
 	)
 